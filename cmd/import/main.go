@@ -0,0 +1,60 @@
+package main
+
+import (
+	"avito-test-task/config"
+	"avito-test-task/internal/app"
+	"avito-test-task/internal/domain"
+	"encoding/json"
+	"flag"
+	"github.com/google/uuid"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	kind := flag.String("kind", app.ImportFlats, "what to import: houses or flats")
+	path := flag.String("file", "", "CSV or JSONL file to import")
+	format := flag.String("format", "", "file format: csv or jsonl, by default taken from the file extension")
+	owner := flag.String("owner", "", "id of the user who will own imported flats")
+	flag.Parse()
+
+	if *path == "" {
+		log.Fatal("-file is required")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*path)), ".")
+		if *format == "ndjson" {
+			*format = domain.ImportFormatJSONL
+		}
+	}
+
+	var ownerID uuid.UUID
+	if *owner != "" {
+		id, err := uuid.Parse(*owner)
+		if err != nil {
+			log.Fatalf("bad -owner: %v", err)
+		}
+		ownerID = id
+	}
+
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		log.Fatal("can't read config file")
+	}
+
+	report, err := app.Import(cfg, *kind, *format, *path, ownerID)
+	if err != nil {
+		log.Fatalf("import failed: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		log.Fatalf("can't print import report: %v", err)
+	}
+	if len(report.Errors) > 0 {
+		os.Exit(2)
+	}
+}
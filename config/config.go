@@ -6,9 +6,11 @@ import (
 )
 
 type Config struct {
-	Logger `yaml:"logger"`
-	Db     `yaml:"postgres"`
-	Secret `yaml:"secret"`
+	Logger     `yaml:"logger"`
+	Db         `yaml:"postgres"`
+	Secret     `yaml:"secret"`
+	Moderation `yaml:"moderation"`
+	Login      `yaml:"login"`
 }
 
 type Logger struct {
@@ -17,7 +19,38 @@ type Logger struct {
 }
 
 type Secret struct {
-	Key string `yaml:"key"`
+	Key   string `yaml:"key"`
+	KeyID string `yaml:"key-id" env-default:"main"`
+	// PreviousKeys maps ids of rotated out keys to the keys; they verify tokens until these expire.
+	PreviousKeys map[string]string `yaml:"previous-keys"`
+	// Algorithm is HS256 to sign with Key, or RS256 or EdDSA to sign with the PEM key from PrivateKeyFile.
+	Algorithm      string `yaml:"algorithm" env-default:"HS256"`
+	PrivateKeyFile string `yaml:"private-key-file"`
+	// PreviousPublicKeyFiles maps ids of rotated out asymmetric keys to their PEM public keys.
+	PreviousPublicKeyFiles map[string]string `yaml:"previous-public-key-files"`
+	Issuer                 string            `yaml:"issuer" env-default:"avito-test-task"`
+	Audience               string            `yaml:"audience" env-default:"avito-test-task"`
+	LeewaySec              int               `yaml:"leeway-sec" env-default:"30"`
+	AccessTokenTTLMin      int               `yaml:"access-token-ttl-min" env-default:"15"`
+	RefreshTokenTTLHours   int               `yaml:"refresh-token-ttl-hours" env-default:"720"`
+}
+
+type Moderation struct {
+	LeaseTTLSec         int `yaml:"lease-ttl-sec" env-default:"900"`
+	ReclaimFrequencySec int `yaml:"reclaim-frequency-sec" env-default:"30"`
+	// DeclineReasons maps decline reason codes accepted by /flat/update to their descriptions.
+	DeclineReasons map[string]string `yaml:"decline-reasons"`
+}
+
+// Login sets how failed logins slow down password guessing, see domain.LoginPolicy.
+type Login struct {
+	FreeAttempts        int `yaml:"free-attempts" env-default:"3"`
+	BaseDelaySec        int `yaml:"base-delay-sec" env-default:"1"`
+	MaxDelaySec         int `yaml:"max-delay-sec" env-default:"60"`
+	AccountLockAttempts int `yaml:"account-lock-attempts" env-default:"10"`
+	IPLockAttempts      int `yaml:"ip-lock-attempts" env-default:"100"`
+	LockMin             int `yaml:"lock-min" env-default:"15"`
+	WindowMin           int `yaml:"window-min" env-default:"60"`
 }
 
 type Db struct {
@@ -33,11 +66,11 @@ func ReadConfig() (*Config, error) {
 	cfg := Config{}
 	err := cleanenv.ReadConfig("config/config.yml", &cfg)
 	if err != nil {
-		return nil, fmt.Errorf("read config error: %v", err.Error)
+		return nil, fmt.Errorf("read config error: %v", err.Error())
 	}
 	err = cleanenv.ReadEnv(&cfg)
 	if err != nil {
-		return nil, fmt.Errorf("read env error: %v", err.Error)
+		return nil, fmt.Errorf("read env error: %v", err.Error())
 	}
 
 	return &cfg, nil
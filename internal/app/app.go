@@ -4,6 +4,7 @@ import (
 	"avito-test-task/config"
 	"avito-test-task/internal/delivery/handlers"
 	mdware "avito-test-task/internal/delivery/middleware"
+	"avito-test-task/internal/domain"
 	"avito-test-task/internal/ports"
 	"avito-test-task/internal/repo"
 	"avito-test-task/internal/usecase"
@@ -18,19 +19,35 @@ import (
 	"time"
 )
 
+func newPool(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	connString := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", cfg.User, cfg.Password,
+		cfg.Host, cfg.Port, cfg.Db.Db)
+
+	return pgxpool.New(ctx, connString)
+}
+
 func Run(cfg *config.Config) {
 	lg, err := pkg.CreateLogger(cfg.LogFile, "prod")
 	if err != nil {
 		log.Fatal("can't create logger")
 	}
 	pkg.Key = cfg.Key
+	pkg.KeyID = cfg.KeyID
+	pkg.PreviousKeys = cfg.PreviousKeys
+	err = pkg.LoadSigningKeys(cfg.Algorithm, cfg.PrivateKeyFile, cfg.PreviousPublicKeyFiles)
+	if err != nil {
+		log.Fatalf("can't load token signing keys: %v", err.Error())
+	}
+	pkg.Issuer = cfg.Issuer
+	pkg.Audience = cfg.Audience
+	pkg.Leeway = time.Duration(cfg.LeewaySec) * time.Second
+	pkg.AccessTokenTTL = time.Duration(cfg.AccessTokenTTLMin) * time.Minute
+	pkg.RefreshTokenTTL = time.Duration(cfg.RefreshTokenTTLHours) * time.Hour
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	connString := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", cfg.User, cfg.Password,
-		cfg.Host, cfg.Port, cfg.Db.Db)
-	pool, err := pgxpool.New(ctx, connString)
+	pool, err := newPool(ctx, cfg)
 	defer pool.Close()
 	if err != nil {
 		log.Fatalf("can't connect to postgresql: %v", err.Error())
@@ -41,35 +58,88 @@ func Run(cfg *config.Config) {
 	notifyRepo := repo.NewPostgresNotifyRepo(pool, retryAdapter)
 	notifySender := ports.NewSender()
 
-	done := make(chan bool, 1)
-	defer func() {
-		done <- true
-	}()
+	done := make(chan bool)
+	defer close(done)
 	houseRepo := repo.NewPostgresHouseRepo(pool, retryAdapter)
-	houseUsecase := usecase.NewHouseUsecase(houseRepo, notifySender, notifyRepo, done,
+	developerRepo := repo.NewPostgresDeveloperRepo(pool, retryAdapter)
+	houseUsecase := usecase.NewHouseUsecase(houseRepo, developerRepo, notifySender, notifyRepo, done,
 		5*time.Second, 5*time.Second, lg)
 	houseHandler := handlers.NewHouseHandler(houseUsecase, time.Duration(cfg.DbTimeoutSec)*time.Second, lg)
 
+	developerUsecase := usecase.NewDeveloperUsecase(developerRepo, houseRepo)
+	developerHandler := handlers.NewDeveloperHandler(developerUsecase, time.Duration(cfg.DbTimeoutSec)*time.Second, lg)
+
+	tokenRepo := repo.NewPostgresTokenRepo(pool, retryAdapter)
+	denyList := usecase.NewTokenDenyList(tokenRepo, done, time.Second, 5*time.Second, lg)
+	mdware.DenyList = denyList
+
+	loginAttemptRepo := repo.NewPostgresLoginAttemptRepo(pool, retryAdapter)
+	loginThrottle := usecase.NewLoginThrottle(loginAttemptRepo, domain.LoginPolicy{
+		FreeAttempts:        cfg.FreeAttempts,
+		BaseDelay:           time.Duration(cfg.BaseDelaySec) * time.Second,
+		MaxDelay:            time.Duration(cfg.MaxDelaySec) * time.Second,
+		AccountLockAttempts: cfg.AccountLockAttempts,
+		IPLockAttempts:      cfg.IPLockAttempts,
+		LockDuration:        time.Duration(cfg.LockMin) * time.Minute,
+		Window:              time.Duration(cfg.WindowMin) * time.Minute,
+	}, done, time.Hour, 5*time.Second, lg)
+
 	userRepo := repo.NewPostrgesUserRepo(pool, retryAdapter)
-	userUsecase := usecase.NewUserUsecase(userRepo)
+	userUsecase := usecase.NewUserUsecase(userRepo, tokenRepo, denyList, loginThrottle)
 	userHandler := handlers.NewUserHandler(userUsecase, time.Duration(cfg.DbTimeoutSec)*time.Second, lg)
 
 	flatRepo := repo.NewPostgresFlatRepo(pool, retryAdapter)
-	flatUsecase := usecase.NewFlatUsecase(flatRepo)
+	flatUsecase := usecase.NewFlatUsecase(flatRepo, time.Duration(cfg.LeaseTTLSec)*time.Second, cfg.DeclineReasons, done,
+		time.Duration(cfg.ReclaimFrequencySec)*time.Second, 5*time.Second, lg)
 	flatHandler := handlers.NewFlatHandler(flatUsecase, time.Duration(cfg.DbTimeoutSec)*time.Second, lg)
 
+	feedRepo := repo.NewPostgresFeedRepo(pool, retryAdapter)
+	feedUsecase := usecase.NewFeedUsecase(feedRepo, done, 5*time.Second, 5*time.Second, lg)
+	feedHandler := handlers.NewFeedHandler(feedUsecase, time.Duration(cfg.DbTimeoutSec)*time.Second, lg)
+
+	jwksHandler := handlers.NewJWKSHandler(lg)
+
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Recoverer)
 
 	r.Post("/house/create", mdware.AuthMiddleware(mdware.AccessMiddleware(houseHandler.Create)))
+	r.Post("/house/import", mdware.AuthMiddleware(mdware.AccessMiddleware(houseHandler.Import)))
+	r.Get("/house", mdware.AuthMiddleware(houseHandler.List))
+	r.Get("/house/search", mdware.AuthMiddleware(houseHandler.SearchByAddress))
+	r.Get("/house/nearby", mdware.AuthMiddleware(houseHandler.GetNearby))
+	r.Get("/house/geojson", mdware.AuthMiddleware(houseHandler.ExportGeoJSON))
 	r.Get("/house/{id}", mdware.AuthMiddleware(houseHandler.GetFlatsByID))
+	r.Put("/house/{id}", mdware.AuthMiddleware(mdware.AccessMiddleware(houseHandler.Update)))
 	r.Get("/dummyLogin", userHandler.DummyLogin)
 	r.Post("/register", userHandler.Register)
 	r.Post("/login", userHandler.Login)
+	r.Post("/token/refresh", userHandler.Refresh)
+	r.Post("/logout", mdware.AuthMiddleware(userHandler.Logout))
+	r.Post("/user/{id}/unlock", mdware.AuthMiddleware(mdware.AccessMiddleware(userHandler.Unlock)))
 	r.Post("/flat/update", mdware.AuthMiddleware(mdware.AccessMiddleware(flatHandler.Update)))
 	r.Post("/flat/create", mdware.AuthMiddleware(flatHandler.Create))
+	r.Post("/flat/import", mdware.AuthMiddleware(mdware.AccessMiddleware(flatHandler.Import)))
+	r.Get("/house/{id}/stats", mdware.AuthMiddleware(houseHandler.GetStats))
+	r.Get("/house/{id}/export", mdware.AuthMiddleware(houseHandler.Export))
 	r.Post("/house/{id}/subscribe", mdware.AuthMiddleware(houseHandler.Subscribe))
+	r.Get("/flat/search", mdware.AuthMiddleware(flatHandler.Search))
+	r.Post("/flat/edit", mdware.AuthMiddleware(flatHandler.Edit))
+	r.Post("/flat/withdraw", mdware.AuthMiddleware(flatHandler.Withdraw))
+	r.Post("/flat/restore", mdware.AuthMiddleware(flatHandler.Restore))
+	r.Post("/moderation/next", mdware.AuthMiddleware(mdware.AccessMiddleware(flatHandler.ClaimNext)))
+	r.Get("/flat/{house_id}/{flat_id}/history", mdware.AuthMiddleware(flatHandler.GetStatusHistory))
+	r.Get("/flat/{house_id}/{flat_id}/prices", mdware.AuthMiddleware(flatHandler.GetPriceHistory))
+	r.Post("/moderation/extend", mdware.AuthMiddleware(mdware.AccessMiddleware(flatHandler.ExtendLease)))
+	r.Post("/developer/create", mdware.AuthMiddleware(mdware.AccessMiddleware(developerHandler.Create)))
+	r.Get("/developer", mdware.AuthMiddleware(developerHandler.GetAll))
+	r.Get("/developer/{id}", mdware.AuthMiddleware(developerHandler.GetByID))
+	r.Put("/developer/{id}", mdware.AuthMiddleware(mdware.AccessMiddleware(developerHandler.Update)))
+	r.Delete("/developer/{id}", mdware.AuthMiddleware(mdware.AccessMiddleware(developerHandler.Delete)))
+	r.Get("/developer/{id}/houses", mdware.AuthMiddleware(developerHandler.GetHouses))
+	r.Get("/feed/{format}/house/{id}", feedHandler.Get)
+	r.Get("/feed/{format}/developer/{id}", feedHandler.Get)
+	r.Get("/.well-known/jwks.json", jwksHandler.Get)
 
 	fmt.Println("done")
 	err = http.ListenAndServe(":8081", r)
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"avito-test-task/config"
+	"avito-test-task/internal/domain"
+	"avito-test-task/internal/ports"
+	"avito-test-task/internal/repo"
+	"avito-test-task/internal/usecase"
+	"avito-test-task/pkg"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"os"
+	"time"
+)
+
+const (
+	ImportHouses = "houses"
+	ImportFlats  = "flats"
+)
+
+// Import loads houses or flats from a CSV or JSONL file the same way /house/import and /flat/import do.
+// Imported flats are owned by ownerID.
+func Import(cfg *config.Config, kind string, format string, path string, ownerID uuid.UUID) (domain.ImportResponse, error) {
+	lg, err := pkg.CreateLogger(cfg.LogFile, "prod")
+	if err != nil {
+		return domain.ImportResponse{}, fmt.Errorf("can't create logger: %v", err.Error())
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return domain.ImportResponse{}, fmt.Errorf("can't open import file: %v", err.Error())
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	pool, err := newPool(ctx, cfg)
+	if err != nil {
+		return domain.ImportResponse{}, fmt.Errorf("can't connect to postgresql: %v", err.Error())
+	}
+	defer pool.Close()
+
+	retryAdapter := repo.NewPostgresRetryAdapter(pool, 3, time.Second*3)
+
+	// The background workers of the usecases belong to the server, so they are stopped right away.
+	done := make(chan bool)
+	close(done)
+
+	switch kind {
+	case ImportHouses:
+		houseUsecase := usecase.NewHouseUsecase(repo.NewPostgresHouseRepo(pool, retryAdapter),
+			repo.NewPostgresDeveloperRepo(pool, retryAdapter), ports.NewSender(),
+			repo.NewPostgresNotifyRepo(pool, retryAdapter), done, time.Second, time.Second, lg)
+		return houseUsecase.Import(ctx, format, file, lg)
+	case ImportFlats:
+		if ownerID == uuid.Nil {
+			return domain.ImportResponse{}, fmt.Errorf("flats import needs an owner id")
+		}
+		flatUsecase := usecase.NewFlatUsecase(repo.NewPostgresFlatRepo(pool, retryAdapter),
+			time.Duration(cfg.LeaseTTLSec)*time.Second, cfg.DeclineReasons, done, time.Second, time.Second, lg)
+		return flatUsecase.Import(ctx, ownerID, format, file, lg)
+	}
+
+	return domain.ImportResponse{}, fmt.Errorf("unknown import kind %q", kind)
+}
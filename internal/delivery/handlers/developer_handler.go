@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"avito-test-task/internal/domain"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type DeveloperHandler struct {
+	uc        domain.DeveloperUsecase
+	lg        *zap.Logger
+	dbTimeout time.Duration
+}
+
+func NewDeveloperHandler(uc domain.DeveloperUsecase, timeout time.Duration, lg *zap.Logger) *DeveloperHandler {
+	return &DeveloperHandler{uc, lg, timeout}
+}
+
+// developerIDFromPath reads {id} from /developer/{id} and /developer/{id}/houses.
+func developerIDFromPath(path string) (int, error) {
+	pathParts := strings.Split(path, "/")
+	if len(pathParts) < 3 {
+		return 0, strconv.ErrSyntax
+	}
+
+	return strconv.Atoi(pathParts[2])
+}
+
+func (h *DeveloperHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var (
+		developerRequest  domain.DeveloperRequest
+		developerResponse domain.DeveloperResponse
+		respBody          []byte
+	)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.lg.Warn("developer handler: create error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ReadHTTPBodyError, ReadHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	err = json.Unmarshal(body, &developerRequest)
+	if err != nil {
+		h.lg.Warn("developer handler: create error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UnmarshalHTTPBodyError, UnmarshalHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	developerResponse, err = h.uc.Create(ctx, &developerRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("developer handler: create error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), CreateDeveloperError, CreateDeveloperErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(developerResponse)
+	if err != nil {
+		h.lg.Warn("developer handler: create error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *DeveloperHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	var (
+		developerResponse domain.DeveloperResponse
+		respBody          []byte
+	)
+	defer r.Body.Close()
+
+	id, err := developerIDFromPath(r.URL.Path)
+	if err != nil {
+		h.lg.Warn("developer handler: get by id error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	developerResponse, err = h.uc.GetByID(ctx, id, h.lg)
+	if err != nil {
+		h.lg.Warn("developer handler: get by id error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetDeveloperError, GetDeveloperErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(developerResponse)
+	if err != nil {
+		h.lg.Warn("developer handler: get by id error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *DeveloperHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	var (
+		developersResponse domain.DevelopersResponse
+		respBody           []byte
+	)
+	defer r.Body.Close()
+
+	query := r.URL.Query()
+	limit, err := getIntQueryParam(query, "limit")
+	if err != nil {
+		h.lg.Warn("developer handler: get all error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	developersResponse, err = h.uc.GetAll(ctx, query.Get("cursor"), limit, h.lg)
+	if err != nil {
+		h.lg.Warn("developer handler: get all error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ListDevelopersError, ListDevelopersErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(developersResponse)
+	if err != nil {
+		h.lg.Warn("developer handler: get all error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *DeveloperHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var (
+		developerRequest  domain.DeveloperRequest
+		developerResponse domain.DeveloperResponse
+		respBody          []byte
+	)
+	defer r.Body.Close()
+
+	id, err := developerIDFromPath(r.URL.Path)
+	if err != nil {
+		h.lg.Warn("developer handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.lg.Warn("developer handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ReadHTTPBodyError, ReadHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	err = json.Unmarshal(body, &developerRequest)
+	if err != nil {
+		h.lg.Warn("developer handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UnmarshalHTTPBodyError, UnmarshalHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	developerResponse, err = h.uc.Update(ctx, id, &developerRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("developer handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UpdateDeveloperError, UpdateDeveloperErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(developerResponse)
+	if err != nil {
+		h.lg.Warn("developer handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *DeveloperHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var respBody []byte
+	defer r.Body.Close()
+
+	id, err := developerIDFromPath(r.URL.Path)
+	if err != nil {
+		h.lg.Warn("developer handler: delete error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	err = h.uc.DeleteByID(ctx, id, h.lg)
+	if err != nil {
+		h.lg.Warn("developer handler: delete error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), DeleteDeveloperError, DeleteDeveloperErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *DeveloperHandler) GetHouses(w http.ResponseWriter, r *http.Request) {
+	var (
+		housesResponse domain.DeveloperHousesResponse
+		respBody       []byte
+	)
+	defer r.Body.Close()
+
+	id, err := developerIDFromPath(r.URL.Path)
+	if err != nil {
+		h.lg.Warn("developer handler: get houses error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := getIntQueryParam(query, "limit")
+	if err != nil {
+		h.lg.Warn("developer handler: get houses error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	housesResponse, err = h.uc.GetHouses(ctx, id, query.Get("cursor"), limit, h.lg)
+	if err != nil {
+		h.lg.Warn("developer handler: get houses error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetDeveloperHousesError, GetDeveloperHousesErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(housesResponse)
+	if err != nil {
+		h.lg.Warn("developer handler: get houses error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
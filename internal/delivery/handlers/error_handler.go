@@ -6,13 +6,16 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/go-chi/chi/v5/middleware"
+	"math"
 	"net/http"
+	"strconv"
 )
 
 type ErrorResponse struct {
-	Message   string `json:"message"`
-	RequestID string `json:"request_id"`
-	Code      int    `json:"code"`
+	Message       string `json:"message"`
+	RequestID     string `json:"request_id"`
+	Code          int    `json:"code"`
+	CurrentStatus string `json:"current_status,omitempty"`
 }
 
 type ErrorPair struct {
@@ -36,6 +39,36 @@ const (
 	SubscribeOnHouseError
 	NoAccessError
 	ExtractRoleFromTokenError
+	SearchFlatsError
+	EditFlatError
+	WithdrawFlatError
+	RestoreFlatError
+	ClaimNextFlatError
+	ExtendLeaseError
+	GetFlatStatusHistoryError
+	IllegalFlatTransitionError
+	UpdateHouseError
+	ListHousesError
+	SearchHousesError
+	NearbyHousesError
+	ExportGeoJSONError
+	CreateDeveloperError
+	GetDeveloperError
+	ListDevelopersError
+	UpdateDeveloperError
+	DeleteDeveloperError
+	GetDeveloperHousesError
+	GetHouseStatsError
+	GetFlatPriceHistoryError
+	ImportHousesError
+	ImportFlatsError
+	ExportHouseFlatsError
+	GetFeedError
+	RefreshTokenError
+	LogoutError
+	LoginDelayedError
+	AccountLockedError
+	UnlockUserError
 )
 
 const (
@@ -54,6 +87,36 @@ const (
 	SubscribeOnHouseErrorMsg     = "can't subscribe on house"
 	NoAccessErrorMsg             = "no enough access rights"
 	ExtractRoleFromTokenErrorMsg = "can't extract role"
+	SearchFlatsErrorMsg          = "can't search flats"
+	EditFlatErrorMsg             = "can't edit flat"
+	WithdrawFlatErrorMsg         = "can't withdraw flat"
+	RestoreFlatErrorMsg          = "can't restore flat"
+	ClaimNextFlatErrorMsg        = "can't take next flat for moderation"
+	ExtendLeaseErrorMsg          = "can't extend moderation lease"
+	GetFlatStatusHistoryErrorMsg = "can't get flat status history"
+	IllegalFlatTransitionMsg     = "flat status transition is not allowed"
+	UpdateHouseErrorMsg          = "can't update house"
+	ListHousesErrorMsg           = "can't list houses"
+	SearchHousesErrorMsg         = "can't search houses"
+	NearbyHousesErrorMsg         = "can't get nearby houses"
+	ExportGeoJSONErrorMsg        = "can't export houses to geojson"
+	CreateDeveloperErrorMsg      = "can't create developer"
+	GetDeveloperErrorMsg         = "can't get developer"
+	ListDevelopersErrorMsg       = "can't list developers"
+	UpdateDeveloperErrorMsg      = "can't update developer"
+	DeleteDeveloperErrorMsg      = "can't delete developer"
+	GetDeveloperHousesErrorMsg   = "can't get developer houses"
+	GetHouseStatsErrorMsg        = "can't get house stats"
+	GetFlatPriceHistoryErrorMsg  = "can't get flat price history"
+	ImportHousesErrorMsg         = "can't import houses"
+	ImportFlatsErrorMsg          = "can't import flats"
+	ExportHouseFlatsErrorMsg     = "can't export house flats"
+	GetFeedErrorMsg              = "can't get feed"
+	RefreshTokenErrorMsg         = "can't refresh token"
+	LogoutErrorMsg               = "can't logout"
+	LoginDelayedErrorMsg         = "too many failed login attempts, retry later"
+	AccountLockedErrorMsg        = "account is locked after failed login attempts"
+	UnlockUserErrorMsg           = "can't unlock user"
 )
 
 func CreateErrorResponse(ctx context.Context, errCode int, msg string) []byte {
@@ -70,15 +133,70 @@ func CreateErrorResponse(ctx context.Context, errCode int, msg string) []byte {
 	return response
 }
 
+// CreateFlatErrorResponse is CreateErrorResponse that reports the current flat status
+// when err is a rejected status transition.
+func CreateFlatErrorResponse(ctx context.Context, errCode int, msg string, err error) []byte {
+	var transitionErr *domain.FlatTransitionError
+	if !errors.As(err, &transitionErr) {
+		return CreateErrorResponse(ctx, errCode, msg)
+	}
+
+	errResponse := ErrorResponse{
+		Message:       IllegalFlatTransitionMsg,
+		RequestID:     middleware.GetReqID(ctx),
+		Code:          IllegalFlatTransitionError,
+		CurrentStatus: transitionErr.CurrentStatus,
+	}
+
+	response, err := json.Marshal(errResponse)
+	if err != nil {
+		return nil
+	}
+
+	return response
+}
+
+// CreateLoginErrorResponse is CreateErrorResponse that tells a delayed login attempt and
+// a locked account apart from a wrong password.
+func CreateLoginErrorResponse(ctx context.Context, errCode int, msg string, err error) []byte {
+	var blockedErr *domain.LoginBlockedError
+	if !errors.As(err, &blockedErr) {
+		return CreateErrorResponse(ctx, errCode, msg)
+	}
+
+	if errors.Is(blockedErr, domain.ErrUser_Locked) {
+		return CreateErrorResponse(ctx, AccountLockedError, AccountLockedErrorMsg)
+	}
+
+	return CreateErrorResponse(ctx, LoginDelayedError, LoginDelayedErrorMsg)
+}
+
 func GetReturnHTTPCode(w http.ResponseWriter, err error) int {
 	errorsList := []error{
 		domain.ErrHouse_BadRequest,
 		domain.ErrHouse_BadID,
 		domain.ErrHouse_BadYear,
+		domain.ErrHouse_BadDeveloper,
+		domain.ErrHouse_BadAddress,
+		domain.ErrHouse_BadSortField,
+		domain.ErrHouse_BadSortOrder,
+		domain.ErrHouse_BadYearRange,
+		domain.ErrHouse_BadLimit,
+		domain.ErrHouse_BadCursor,
+		domain.ErrHouse_BadQuery,
+		domain.ErrHouse_BadCoords,
+		domain.ErrHouse_BadRadius,
+		domain.ErrDeveloper_BadRequest,
+		domain.ErrDeveloper_BadID,
+		domain.ErrDeveloper_BadName,
+		domain.ErrDeveloper_BadLimit,
+		domain.ErrDeveloper_BadCursor,
 		domain.ErrUser_BadType,
 		domain.ErrUser_BadRequest,
 		domain.ErrUser_BadMail,
 		domain.ErrUser_BadPassword,
+		domain.ErrUser_NoLogin,
+		domain.ErrUser_BadId,
 		domain.ErrFlat_BadPrice,
 		domain.ErrFlat_BadID,
 		domain.ErrFlat_BadHouseID,
@@ -86,6 +204,65 @@ func GetReturnHTTPCode(w http.ResponseWriter, err error) int {
 		domain.ErrFlat_BadNewFlat,
 		domain.ErrFlat_BadStatus,
 		domain.ErrFlat_BadRequest,
+		domain.ErrFlat_BadPriceRange,
+		domain.ErrFlat_BadYearRange,
+		domain.ErrFlat_BadSortField,
+		domain.ErrFlat_BadSortOrder,
+		domain.ErrFlat_BadLimit,
+		domain.ErrFlat_BadCursor,
+		domain.ErrFlat_NoDeclineReason,
+		domain.ErrFlat_BadDeclineReason,
+		domain.ErrImport_BadFormat,
+		domain.ErrImport_BadHeader,
+		domain.ErrImport_TooManyRows,
+		domain.ErrToken_BadRequest,
+		domain.ErrFeed_BadFormat,
+		domain.ErrFeed_BadScope,
+		domain.ErrFeed_BadID,
+	}
+
+	unauthorizedErrorsList := []error{
+		domain.ErrToken_BadRefresh,
+		domain.ErrToken_Reused,
+		domain.ErrToken_BadAccess,
+	}
+
+	forbiddenErrorsList := []error{
+		domain.ErrFlat_NotOwner,
+	}
+
+	notFoundErrorsList := []error{
+		domain.ErrHouse_NotFound,
+		domain.ErrFlat_NotFound,
+		domain.ErrFlat_QueueEmpty,
+		domain.ErrDeveloper_NotFound,
+		domain.ErrFeed_NotFound,
+	}
+
+	conflictErrorsList := []error{
+		domain.ErrFlat_IllegalTransition,
+		domain.ErrFlat_OnModeration,
+		domain.ErrFlat_Conflict,
+		domain.ErrFlat_LeaseNotHeld,
+		domain.ErrFlat_Withdrawn,
+		domain.ErrFlat_NotWithdrawn,
+		domain.ErrDeveloper_NameTaken,
+		domain.ErrDeveloper_HasHouses,
+		domain.ErrUser_MailTaken,
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	var blockedErr *domain.LoginBlockedError
+	if errors.As(err, &blockedErr) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(blockedErr.RetryAfter.Seconds()))))
+		if errors.Is(blockedErr, domain.ErrUser_Locked) {
+			return http.StatusLocked
+		}
+		return http.StatusTooManyRequests
 	}
 
 	for _, e := range errorsList {
@@ -93,6 +270,30 @@ func GetReturnHTTPCode(w http.ResponseWriter, err error) int {
 			return http.StatusBadRequest
 		}
 	}
+
+	for _, e := range unauthorizedErrorsList {
+		if errors.Is(err, e) {
+			return http.StatusUnauthorized
+		}
+	}
+
+	for _, e := range forbiddenErrorsList {
+		if errors.Is(err, e) {
+			return http.StatusForbidden
+		}
+	}
+
+	for _, e := range notFoundErrorsList {
+		if errors.Is(err, e) {
+			return http.StatusNotFound
+		}
+	}
+
+	for _, e := range conflictErrorsList {
+		if errors.Is(err, e) {
+			return http.StatusConflict
+		}
+	}
 	w.Header().Set("Retry-After", "120")
 	return http.StatusInternalServerError
 }
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"avito-test-task/internal/domain"
+	"avito-test-task/pkg"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+
+	ExportLangRu = "ru"
+	ExportLangEn = "en"
+
+	CSVExportContentType = "text/csv; charset=utf-8"
+	// utf8BOM lets spreadsheet programs detect the encoding of exported CSV files.
+	utf8BOM = "\ufeff"
+)
+
+var (
+	errBadExportFormat = errors.New("bad export format")
+	errBadExportLang   = errors.New("bad export language")
+	errBadExportColumn = errors.New("bad export column")
+)
+
+type flatExportColumn struct {
+	name   string
+	titles map[string]string
+	value  func(flat *domain.SingleFlatResponse) any
+}
+
+var flatExportColumns = []flatExportColumn{
+	{"id", map[string]string{ExportLangRu: "Номер квартиры", ExportLangEn: "Flat number"},
+		func(flat *domain.SingleFlatResponse) any { return flat.ID }},
+	{"house_id", map[string]string{ExportLangRu: "Номер дома", ExportLangEn: "House number"},
+		func(flat *domain.SingleFlatResponse) any { return flat.HouseID }},
+	{"price", map[string]string{ExportLangRu: "Цена", ExportLangEn: "Price"},
+		func(flat *domain.SingleFlatResponse) any { return flat.Price }},
+	{"rooms", map[string]string{ExportLangRu: "Количество комнат", ExportLangEn: "Rooms"},
+		func(flat *domain.SingleFlatResponse) any { return flat.Rooms }},
+	{"status", map[string]string{ExportLangRu: "Статус модерации", ExportLangEn: "Moderation status"},
+		func(flat *domain.SingleFlatResponse) any { return flat.Status }},
+	{"previous_price", map[string]string{ExportLangRu: "Цена до снижения", ExportLangEn: "Price before drop"},
+		func(flat *domain.SingleFlatResponse) any {
+			if flat.PriceDrop == nil {
+				return nil
+			}
+			return flat.PriceDrop.PreviousPrice
+		}},
+	{"price_dropped_at", map[string]string{ExportLangRu: "Дата снижения цены", ExportLangEn: "Price dropped at"},
+		func(flat *domain.SingleFlatResponse) any {
+			if flat.PriceDrop == nil {
+				return nil
+			}
+			return flat.PriceDrop.DroppedAt.Format(time.DateTime)
+		}},
+}
+
+// selectExportColumns returns the columns named in a comma separated list in its order,
+// or all columns for an empty list.
+func selectExportColumns(list string) ([]flatExportColumn, error) {
+	if list == "" {
+		return flatExportColumns, nil
+	}
+
+	var columns []flatExportColumn
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, column := range flatExportColumns {
+			if column.name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s", errBadExportColumn, name)
+		}
+	}
+
+	return columns, nil
+}
+
+// exportLang takes the header language from the lang parameter or else from Accept-Language,
+// falling back to Russian.
+func exportLang(r *http.Request) (string, error) {
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		if strings.HasPrefix(strings.ToLower(r.Header.Get("Accept-Language")), ExportLangEn) {
+			return ExportLangEn, nil
+		}
+		return ExportLangRu, nil
+	}
+
+	if lang != ExportLangRu && lang != ExportLangEn {
+		return "", errBadExportLang
+	}
+
+	return lang, nil
+}
+
+func exportHeader(columns []flatExportColumn, lang string) []any {
+	header := make([]any, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, column.titles[lang])
+	}
+
+	return header
+}
+
+func exportRow(columns []flatExportColumn, flat *domain.SingleFlatResponse) []any {
+	row := make([]any, 0, len(columns))
+	for _, column := range columns {
+		row = append(row, column.value(flat))
+	}
+
+	return row
+}
+
+// rowWriter streams an exported spreadsheet to the client.
+type rowWriter interface {
+	WriteRow(values []any) error
+	Flush() error
+	Close() error
+}
+
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvRowWriter) WriteRow(values []any) error {
+	record := make([]string, 0, len(values))
+	for _, value := range values {
+		if value == nil {
+			record = append(record, "")
+			continue
+		}
+		record = append(record, fmt.Sprint(value))
+	}
+
+	return c.w.Write(record)
+}
+
+func (c *csvRowWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvRowWriter) Close() error {
+	return c.Flush()
+}
+
+func exportContentType(format string) string {
+	if format == ExportFormatXLSX {
+		return pkg.XLSXContentType
+	}
+
+	return CSVExportContentType
+}
+
+func newRowWriter(w io.Writer, format string, sheetName string) (rowWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return nil, err
+		}
+		return &csvRowWriter{w: csv.NewWriter(w)}, nil
+	case ExportFormatXLSX:
+		return pkg.NewXLSXWriter(w, sheetName)
+	}
+
+	return nil, errBadExportFormat
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"avito-test-task/internal/domain"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	XMLContentType = "application/xml; charset=utf-8"
+	// feedMaxAge is how long partners and proxies may reuse a feed without asking again.
+	feedMaxAge = 5 * time.Minute
+)
+
+type FeedHandler struct {
+	uc        domain.FeedUsecase
+	lg        *zap.Logger
+	dbTimeout time.Duration
+}
+
+func NewFeedHandler(uc domain.FeedUsecase, timeout time.Duration, lg *zap.Logger) *FeedHandler {
+	return &FeedHandler{uc, lg, timeout}
+}
+
+// feedRequestFromPath reads /feed/{format}/{scope}/{id}; the id may end with .xml.
+func feedRequestFromPath(path string) (domain.FeedRequest, error) {
+	pathParts := strings.Split(path, "/")
+	if len(pathParts) < 5 {
+		return domain.FeedRequest{}, strconv.ErrSyntax
+	}
+
+	id, err := strconv.Atoi(strings.TrimSuffix(pathParts[4], ".xml"))
+	if err != nil {
+		return domain.FeedRequest{}, err
+	}
+
+	return domain.FeedRequest{Format: pathParts[2], Scope: pathParts[3], ID: id}, nil
+}
+
+func feedETag(req *domain.FeedRequest, version time.Time) string {
+	return fmt.Sprintf(`"%s-%s-%d-%d"`, req.Format, req.Scope, req.ID, version.UnixMicro())
+}
+
+// feedNotModified tells whether the client already has this version of the feed.
+func feedNotModified(r *http.Request, etag string, version time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !version.Truncate(time.Second).After(since)
+}
+
+func (h *FeedHandler) Get(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody []byte
+		started  bool
+		written  int
+	)
+	defer r.Body.Close()
+
+	feedRequest, err := feedRequestFromPath(r.URL.Path)
+	if err != nil {
+		h.lg.Warn("feed handler: get error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	version, err := h.uc.GetVersion(ctx, &feedRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("feed handler: get error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetFeedError, GetFeedErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	etag := feedETag(&feedRequest, version)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", version.UTC().Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedMaxAge.Seconds())))
+	if feedNotModified(r, etag, version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	err = h.uc.Stream(ctx, &feedRequest, func(chunk string) error {
+		if !started {
+			w.Header().Set("Content-Type", XMLContentType)
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		if _, err := io.WriteString(w, chunk); err != nil {
+			return err
+		}
+		written++
+
+		if flusher != nil && written%ndjsonFlushRows == 0 {
+			flusher.Flush()
+		}
+		return nil
+	}, h.lg)
+	if err != nil && !started {
+		h.lg.Warn("feed handler: get error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetFeedError, GetFeedErrorMsg)
+		w.Header().Del("ETag")
+		w.Header().Del("Last-Modified")
+		w.Header().Del("Cache-Control")
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+	if err != nil {
+		h.lg.Warn("feed handler: get error: stream interrupted", zap.Int("written", written), zap.Error(err))
+		return
+	}
+
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"go.uber.org/zap"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -51,7 +53,7 @@ func (h *FlatHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "userID")
+	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "sub")
 	if err != nil {
 		h.lg.Warn("flat handler: create error: extract id", zap.Error(err))
 		respBody = CreateErrorResponse(r.Context(), CreateFlatError, CreateFlatErrorMsg)
@@ -119,7 +121,7 @@ func (h *FlatHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "userID")
+	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "sub")
 	if err != nil {
 		h.lg.Warn("flat handler: create error", zap.Error(err))
 		respBody = CreateErrorResponse(r.Context(), CreateFlatError, CreateFlatErrorMsg)
@@ -142,7 +144,7 @@ func (h *FlatHandler) Update(w http.ResponseWriter, r *http.Request) {
 	flatResponse, err = h.uc.Update(ctx, userUuid, &flatRequest, h.lg)
 	if err != nil {
 		h.lg.Warn("flat handler: update error", zap.Error(err))
-		respBody = CreateErrorResponse(r.Context(), UpdateFlatError, UpdateFlatErrorMsg)
+		respBody = CreateFlatErrorResponse(r.Context(), UpdateFlatError, UpdateFlatErrorMsg, err)
 		w.WriteHeader(GetReturnHTTPCode(w, err))
 		w.Write(respBody)
 		return
@@ -159,3 +161,551 @@ func (h *FlatHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	w.Write(respBody)
 }
+
+func (h *FlatHandler) Search(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody       []byte
+		searchRequest  domain.SearchFlatRequest
+		searchResponse domain.SearchFlatResponse
+	)
+	defer r.Body.Close()
+
+	query := r.URL.Query()
+	params, err := getIntQueryParams(query, "price_min", "price_max", "rooms", "house_id",
+		"year_min", "year_max", "limit", "offset")
+	if err != nil {
+		h.lg.Warn("flat handler: search error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	searchRequest = domain.SearchFlatRequest{
+		MinPrice:  params[0],
+		MaxPrice:  params[1],
+		Rooms:     params[2],
+		HouseID:   params[3],
+		Developer: query.Get("developer"),
+		MinYear:   params[4],
+		MaxYear:   params[5],
+		SortBy:    query.Get("sort"),
+		Order:     query.Get("order"),
+		Limit:     params[6],
+		Offset:    params[7],
+	}
+
+	role, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "role")
+	if err != nil {
+		h.lg.Warn("flat handler: search error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExtractRoleFromTokenError, ExtractRoleFromTokenErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	searchResponse, err = h.uc.Search(ctx, &searchRequest, visibleStatusForRole(role), h.lg)
+	if err != nil {
+		h.lg.Warn("flat handler: search error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), SearchFlatsError, SearchFlatsErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(searchResponse)
+	if err != nil {
+		h.lg.Warn("flat handler: search error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *FlatHandler) Edit(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody     []byte
+		flatRequest  domain.EditFlatRequest
+		flatResponse domain.CreateFlatResponse
+	)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.lg.Warn("flat handler: edit error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ReadHTTPBodyError, ReadHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	err = json.Unmarshal(body, &flatRequest)
+	if err != nil {
+		h.lg.Warn("flat handler: edit error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UnmarshalHTTPBodyError, UnmarshalHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "sub")
+	if err != nil {
+		h.lg.Warn("flat handler: edit error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), EditFlatError, EditFlatErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	userUuid, err := uuid.Parse(userID)
+	if err != nil {
+		h.lg.Warn("flat handler: edit error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), EditFlatError, EditFlatErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	flatResponse, err = h.uc.Edit(ctx, userUuid, &flatRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("flat handler: edit error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), EditFlatError, EditFlatErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(flatResponse)
+	if err != nil {
+		h.lg.Warn("flat handler: edit error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+type flatLifecycleAction func(ctx context.Context, userID uuid.UUID, role string,
+	req *domain.UpdateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error)
+
+func (h *FlatHandler) changeFlatLifecycle(w http.ResponseWriter, r *http.Request, actionName string,
+	action flatLifecycleAction, errCode int, errMsg string) {
+	logMsg := "flat handler: " + actionName + " error"
+
+	var (
+		respBody     []byte
+		flatRequest  domain.UpdateFlatRequest
+		flatResponse domain.CreateFlatResponse
+	)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.lg.Warn(logMsg, zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ReadHTTPBodyError, ReadHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	err = json.Unmarshal(body, &flatRequest)
+	if err != nil {
+		h.lg.Warn(logMsg, zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UnmarshalHTTPBodyError, UnmarshalHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	token := r.Header.Get("authorization")
+	userID, err := pkg.ExtractPayloadFromToken(token, "sub")
+	if err != nil {
+		h.lg.Warn(logMsg+": extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), errCode, errMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	userUuid, err := uuid.Parse(userID)
+	if err != nil {
+		h.lg.Warn(logMsg+": extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), errCode, errMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	role, err := pkg.ExtractPayloadFromToken(token, "role")
+	if err != nil {
+		h.lg.Warn(logMsg+": extract role", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExtractRoleFromTokenError, ExtractRoleFromTokenErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	flatResponse, err = action(ctx, userUuid, role, &flatRequest, h.lg)
+	if err != nil {
+		h.lg.Warn(logMsg, zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), errCode, errMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(flatResponse)
+	if err != nil {
+		h.lg.Warn(logMsg, zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *FlatHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	h.changeFlatLifecycle(w, r, "withdraw", h.uc.Withdraw, WithdrawFlatError, WithdrawFlatErrorMsg)
+}
+
+func (h *FlatHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	h.changeFlatLifecycle(w, r, "restore", h.uc.Restore, RestoreFlatError, RestoreFlatErrorMsg)
+}
+
+func (h *FlatHandler) ClaimNext(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody     []byte
+		flatResponse domain.CreateFlatResponse
+	)
+	defer r.Body.Close()
+
+	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "sub")
+	if err != nil {
+		h.lg.Warn("flat handler: claim next error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ClaimNextFlatError, ClaimNextFlatErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	userUuid, err := uuid.Parse(userID)
+	if err != nil {
+		h.lg.Warn("flat handler: claim next error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ClaimNextFlatError, ClaimNextFlatErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	flatResponse, err = h.uc.ClaimNext(ctx, userUuid, h.lg)
+	if err != nil {
+		h.lg.Warn("flat handler: claim next error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ClaimNextFlatError, ClaimNextFlatErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(flatResponse)
+	if err != nil {
+		h.lg.Warn("flat handler: claim next error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *FlatHandler) ExtendLease(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody     []byte
+		leaseRequest domain.UpdateFlatRequest
+		flatResponse domain.CreateFlatResponse
+	)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.lg.Warn("flat handler: extend lease error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ReadHTTPBodyError, ReadHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	err = json.Unmarshal(body, &leaseRequest)
+	if err != nil {
+		h.lg.Warn("flat handler: extend lease error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UnmarshalHTTPBodyError, UnmarshalHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "sub")
+	if err != nil {
+		h.lg.Warn("flat handler: extend lease error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExtendLeaseError, ExtendLeaseErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	userUuid, err := uuid.Parse(userID)
+	if err != nil {
+		h.lg.Warn("flat handler: extend lease error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExtendLeaseError, ExtendLeaseErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	flatResponse, err = h.uc.ExtendLease(ctx, userUuid, &leaseRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("flat handler: extend lease error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExtendLeaseError, ExtendLeaseErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(flatResponse)
+	if err != nil {
+		h.lg.Warn("flat handler: extend lease error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *FlatHandler) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody        []byte
+		historyResponse domain.FlatStatusHistoryResponse
+	)
+	defer r.Body.Close()
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	if len(pathParts) < 4 {
+		h.lg.Warn("flat handler: get status history error: bad path", zap.String("path", r.URL.Path))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+	houseID, err := strconv.Atoi(pathParts[len(pathParts)-3])
+	if err != nil {
+		h.lg.Warn("flat handler: get status history error: parse house id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+	flatID, err := strconv.Atoi(pathParts[len(pathParts)-2])
+	if err != nil {
+		h.lg.Warn("flat handler: get status history error: parse flat id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	token := r.Header.Get("authorization")
+	role, err := pkg.ExtractPayloadFromToken(token, "role")
+	if err != nil {
+		h.lg.Warn("flat handler: get status history error: extract role", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExtractRoleFromTokenError, ExtractRoleFromTokenErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	userID, err := pkg.ExtractPayloadFromToken(token, "sub")
+	if err != nil {
+		h.lg.Warn("flat handler: get status history error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetFlatStatusHistoryError, GetFlatStatusHistoryErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	userUuid, err := uuid.Parse(userID)
+	if err != nil {
+		h.lg.Warn("flat handler: get status history error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetFlatStatusHistoryError, GetFlatStatusHistoryErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	historyResponse, err = h.uc.GetStatusHistory(ctx, userUuid, role, flatID, houseID, h.lg)
+	if err != nil {
+		h.lg.Warn("flat handler: get status history error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetFlatStatusHistoryError, GetFlatStatusHistoryErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(historyResponse)
+	if err != nil {
+		h.lg.Warn("flat handler: get status history error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *FlatHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody        []byte
+		historyResponse domain.FlatPriceHistoryResponse
+	)
+	defer r.Body.Close()
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	if len(pathParts) < 4 {
+		h.lg.Warn("flat handler: get price history error: bad path", zap.String("path", r.URL.Path))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+	houseID, err := strconv.Atoi(pathParts[len(pathParts)-3])
+	if err != nil {
+		h.lg.Warn("flat handler: get price history error: parse house id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+	flatID, err := strconv.Atoi(pathParts[len(pathParts)-2])
+	if err != nil {
+		h.lg.Warn("flat handler: get price history error: parse flat id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	token := r.Header.Get("authorization")
+	role, err := pkg.ExtractPayloadFromToken(token, "role")
+	if err != nil {
+		h.lg.Warn("flat handler: get price history error: extract role", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExtractRoleFromTokenError, ExtractRoleFromTokenErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	userID, err := pkg.ExtractPayloadFromToken(token, "sub")
+	if err != nil {
+		h.lg.Warn("flat handler: get price history error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetFlatPriceHistoryError, GetFlatPriceHistoryErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	userUuid, err := uuid.Parse(userID)
+	if err != nil {
+		h.lg.Warn("flat handler: get price history error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetFlatPriceHistoryError, GetFlatPriceHistoryErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	historyResponse, err = h.uc.GetPriceHistory(ctx, userUuid, role, flatID, houseID, h.lg)
+	if err != nil {
+		h.lg.Warn("flat handler: get price history error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetFlatPriceHistoryError, GetFlatPriceHistoryErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(historyResponse)
+	if err != nil {
+		h.lg.Warn("flat handler: get price history error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *FlatHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody       []byte
+		importResponse domain.ImportResponse
+	)
+	defer r.Body.Close()
+
+	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "sub")
+	if err != nil {
+		h.lg.Warn("flat handler: import error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ImportFlatsError, ImportFlatsErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	userUuid, err := uuid.Parse(userID)
+	if err != nil {
+		h.lg.Warn("flat handler: import error: extract id", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ImportFlatsError, ImportFlatsErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBodySize)
+	importResponse, err = h.uc.Import(ctx, userUuid, importFormat(r), body, h.lg)
+	if err != nil {
+		h.lg.Warn("flat handler: import error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ImportFlatsError, ImportFlatsErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(importResponse)
+	if err != nil {
+		h.lg.Warn("flat handler: import error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
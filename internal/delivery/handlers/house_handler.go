@@ -76,6 +76,298 @@ func (h *HouseHandler) Create(w http.ResponseWriter, r *http.Request) {
 	w.Write(respBody)
 }
 
+func (h *HouseHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var (
+		houseRequest  domain.UpdateHouseRequest
+		houseResponse domain.CreateHouseResponse
+		respBody      []byte
+	)
+	defer r.Body.Close()
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	id, err := strconv.Atoi(pathParts[len(pathParts)-1])
+	if err != nil {
+		h.lg.Warn("house handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.lg.Warn("house handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ReadHTTPBodyError, ReadHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+	err = json.Unmarshal(body, &houseRequest)
+	if err != nil {
+		h.lg.Warn("house handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UnmarshalHTTPBodyError, UnmarshalHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	houseResponse, err = h.uc.Update(ctx, id, &houseRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("house handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UpdateHouseError, UpdateHouseErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(houseResponse)
+	if err != nil {
+		h.lg.Warn("house handler: update error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *HouseHandler) List(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody       []byte
+		listRequest    domain.ListHousesRequest
+		housesResponse domain.ListHousesResponse
+	)
+	defer r.Body.Close()
+
+	query := r.URL.Query()
+	params, err := getIntQueryParams(query, "year_min", "year_max", "limit")
+	if err != nil {
+		h.lg.Warn("house handler: list error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	listRequest = domain.ListHousesRequest{
+		Developer: query.Get("developer"),
+		Address:   query.Get("address"),
+		MinYear:   params[0],
+		MaxYear:   params[1],
+		SortBy:    query.Get("sort"),
+		Order:     query.Get("order"),
+		Cursor:    query.Get("cursor"),
+		Limit:     params[2],
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	housesResponse, err = h.uc.List(ctx, &listRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("house handler: list error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ListHousesError, ListHousesErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(housesResponse)
+	if err != nil {
+		h.lg.Warn("house handler: list error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *HouseHandler) SearchByAddress(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody       []byte
+		searchResponse domain.SearchHousesResponse
+	)
+	defer r.Body.Close()
+
+	query := r.URL.Query()
+	params, err := getIntQueryParams(query, "limit")
+	if err != nil {
+		h.lg.Warn("house handler: search by address error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	searchResponse, err = h.uc.SearchByAddress(ctx, query.Get("q"), params[0], h.lg)
+	if err != nil {
+		h.lg.Warn("house handler: search by address error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), SearchHousesError, SearchHousesErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(searchResponse)
+	if err != nil {
+		h.lg.Warn("house handler: search by address error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *HouseHandler) GetNearby(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody       []byte
+		nearbyRequest  domain.NearbyHousesRequest
+		nearbyResponse domain.NearbyHousesResponse
+	)
+	defer r.Body.Close()
+
+	query := r.URL.Query()
+	point, err := getFloatQueryParams(query, "lat", "lon", "radius_km")
+	if err != nil {
+		h.lg.Warn("house handler: get nearby error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+	params, err := getIntQueryParams(query, "limit")
+	if err != nil {
+		h.lg.Warn("house handler: get nearby error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	nearbyRequest = domain.NearbyHousesRequest{
+		Latitude:  point[0],
+		Longitude: point[1],
+		RadiusKm:  point[2],
+		Limit:     params[0],
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	nearbyResponse, err = h.uc.GetNearby(ctx, &nearbyRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("house handler: get nearby error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), NearbyHousesError, NearbyHousesErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(nearbyResponse)
+	if err != nil {
+		h.lg.Warn("house handler: get nearby error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *HouseHandler) ExportGeoJSON(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody   []byte
+		collection domain.GeoJSONFeatureCollection
+	)
+	defer r.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	collection, err := h.uc.ExportGeoJSON(ctx, h.lg)
+	if err != nil {
+		h.lg.Warn("house handler: export geojson error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExportGeoJSONError, ExportGeoJSONErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(collection)
+	if err != nil {
+		h.lg.Warn("house handler: export geojson error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Header().Set("Content-Type", GeoJSONContentType)
+	w.Write(respBody)
+}
+
+func (h *HouseHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody      []byte
+		statsResponse domain.HouseStatsResponse
+	)
+	defer r.Body.Close()
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	id, err := strconv.Atoi(pathParts[len(pathParts)-2])
+	if err != nil {
+		h.lg.Warn("house handler: get stats error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	role, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "role")
+	if err != nil {
+		h.lg.Warn("house handler: get stats error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExtractRoleFromTokenError, ExtractRoleFromTokenErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	statsResponse, err = h.uc.GetStats(ctx, id, visibleStatusForRole(role), h.lg)
+	if err != nil {
+		h.lg.Warn("house handler: get stats error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetHouseStatsError, GetHouseStatsErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(statsResponse)
+	if err != nil {
+		h.lg.Warn("house handler: get stats error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
 func (h *HouseHandler) GetFlatsByID(w http.ResponseWriter, r *http.Request) {
 	var (
 		respBody []byte
@@ -93,6 +385,16 @@ func (h *HouseHandler) GetFlatsByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limit, err := getIntQueryParam(r.URL.Query(), "limit")
+	if err != nil {
+		h.lg.Warn("house handler: get flats by id error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+	cursor := r.URL.Query().Get("cursor")
+
 	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
 	defer cancel()
 
@@ -105,14 +407,12 @@ func (h *HouseHandler) GetFlatsByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var status string
-	if role == domain.Moderator {
-		status = domain.AnyStatus
-	} else {
-		status = domain.ApprovedStatus
+	if acceptsNDJSON(r) {
+		h.streamFlatsByID(w, r, id, visibleStatusForRole(role))
+		return
 	}
 
-	flats, err := h.uc.GetFlatsByHouseID(ctx, id, status, h.lg)
+	flats, err := h.uc.GetFlatsByHouseID(ctx, id, visibleStatusForRole(role), cursor, limit, h.lg)
 	if err != nil {
 		h.lg.Warn("house handler: get flats by id error", zap.Error(err))
 		respBody = CreateErrorResponse(r.Context(), GetFlatsByHouseIDError, GetFlatsByHouseIDErrorMsg)
@@ -133,6 +433,177 @@ func (h *HouseHandler) GetFlatsByID(w http.ResponseWriter, r *http.Request) {
 	w.Write(respBody)
 }
 
+func (h *HouseHandler) streamFlatsByID(w http.ResponseWriter, r *http.Request, id int, status string) {
+	var (
+		respBody []byte
+		written  int
+	)
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+
+	err := h.uc.StreamFlatsByHouseID(ctx, id, status, func(flat domain.SingleFlatResponse) error {
+		if written == 0 {
+			w.Header().Set("Content-Type", NDJSONContentType)
+			w.WriteHeader(http.StatusOK)
+		}
+
+		if err := encoder.Encode(flat); err != nil {
+			return err
+		}
+		written++
+
+		if flusher != nil && written%ndjsonFlushRows == 0 {
+			flusher.Flush()
+		}
+		return nil
+	}, h.lg)
+	if err != nil && written == 0 {
+		h.lg.Warn("house handler: stream flats by id error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), GetFlatsByHouseIDError, GetFlatsByHouseIDErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+	if err != nil {
+		h.lg.Warn("house handler: stream flats by id error: stream interrupted",
+			zap.Int("written", written), zap.Error(err))
+		return
+	}
+
+	if written == 0 {
+		w.Header().Set("Content-Type", NDJSONContentType)
+		w.WriteHeader(http.StatusOK)
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+func (h *HouseHandler) Export(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody []byte
+		writer   rowWriter
+		written  int
+	)
+	defer r.Body.Close()
+
+	pathParts := strings.Split(r.URL.Path, "/")
+	id, err := strconv.Atoi(pathParts[len(pathParts)-2])
+	if err != nil {
+		h.lg.Warn("house handler: export error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	if format != ExportFormatCSV && format != ExportFormatXLSX {
+		h.lg.Warn("house handler: export error: bad format", zap.String("format", format))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+	columns, err := selectExportColumns(query.Get("columns"))
+	if err != nil {
+		h.lg.Warn("house handler: export error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+	lang, err := exportLang(r)
+	if err != nil {
+		h.lg.Warn("house handler: export error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	role, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "role")
+	if err != nil {
+		h.lg.Warn("house handler: export error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExtractRoleFromTokenError, ExtractRoleFromTokenErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	flusher, _ := w.(http.Flusher)
+	// The response starts with the first flat, so that errors before it still get a proper status.
+	startExport := func() error {
+		w.Header().Set("Content-Type", exportContentType(format))
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="house-%d-flats.%s"`, id, format))
+		w.WriteHeader(http.StatusOK)
+
+		rowWriter, err := newRowWriter(w, format, fmt.Sprintf("house %d", id))
+		if err != nil {
+			return err
+		}
+		writer = rowWriter
+		return writer.WriteRow(exportHeader(columns, lang))
+	}
+
+	err = h.uc.StreamFlatsByHouseID(ctx, id, visibleStatusForRole(role), func(flat domain.SingleFlatResponse) error {
+		if writer == nil {
+			if err := startExport(); err != nil {
+				return err
+			}
+		}
+
+		if err := writer.WriteRow(exportRow(columns, &flat)); err != nil {
+			return err
+		}
+		written++
+
+		if flusher != nil && written%ndjsonFlushRows == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+		return nil
+	}, h.lg)
+	if err != nil && writer == nil {
+		h.lg.Warn("house handler: export error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ExportHouseFlatsError, ExportHouseFlatsErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+	if err != nil {
+		h.lg.Warn("house handler: export error: stream interrupted", zap.Int("written", written), zap.Error(err))
+		return
+	}
+
+	if writer == nil {
+		if err = startExport(); err != nil {
+			h.lg.Warn("house handler: export error", zap.Error(err))
+			return
+		}
+	}
+	if err = writer.Close(); err != nil {
+		h.lg.Warn("house handler: export error", zap.Error(err))
+		return
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
 func (h *HouseHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
 	var (
 		respBody []byte
@@ -141,7 +612,7 @@ func (h *HouseHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
 
 	fmt.Println(r.URL.Path)
 
-	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "userID")
+	userID, err := pkg.ExtractPayloadFromToken(r.Header.Get("authorization"), "sub")
 	if err != nil {
 		h.lg.Warn("house handler: subscribe error", zap.Error(err))
 		respBody = CreateErrorResponse(r.Context(), SubscribeOnHouseError, SubscribeOnHouseErrorMsg)
@@ -183,3 +654,35 @@ func (h *HouseHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+func (h *HouseHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody       []byte
+		importResponse domain.ImportResponse
+	)
+	defer r.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBodySize)
+	importResponse, err := h.uc.Import(ctx, importFormat(r), body, h.lg)
+	if err != nil {
+		h.lg.Warn("house handler: import error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ImportHousesError, ImportHousesErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(importResponse)
+	if err != nil {
+		h.lg.Warn("house handler: import error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
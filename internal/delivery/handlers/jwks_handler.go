@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"avito-test-task/pkg"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	JWKSContentType = "application/jwk-set+json"
+	// jwksMaxAge lets other services cache the keys; a new key is announced by its kid, which they
+	// do not know yet and fetch the set again for.
+	jwksMaxAge = 5 * time.Minute
+)
+
+type JWKSHandler struct {
+	lg *zap.Logger
+}
+
+func NewJWKSHandler(lg *zap.Logger) *JWKSHandler {
+	return &JWKSHandler{lg}
+}
+
+func (h *JWKSHandler) Get(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	respBody, err := json.Marshal(pkg.JWKS())
+	if err != nil {
+		h.lg.Warn("jwks handler: get error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Header().Set("Content-Type", JWKSContentType)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(jwksMaxAge.Seconds())))
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
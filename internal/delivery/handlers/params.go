@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"avito-test-task/internal/domain"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	NDJSONContentType  = "application/x-ndjson"
+	GeoJSONContentType = "application/geo+json"
+	CSVContentType     = "text/csv"
+	ndjsonFlushRows    = 100
+
+	// maxImportBodySize and importTimeout bound bulk imports, which are far larger than other requests.
+	maxImportBodySize = 64 << 20
+	importTimeout     = 10 * time.Minute
+)
+
+// importFormat takes the import file format from the format parameter or else from Content-Type.
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case CSVContentType:
+		return domain.ImportFormatCSV
+	case NDJSONContentType:
+		return domain.ImportFormatJSONL
+	}
+
+	return ""
+}
+
+func getIntQueryParam(values url.Values, key string) (int, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(raw)
+}
+
+func getIntQueryParams(values url.Values, keys ...string) ([]int, error) {
+	params := make([]int, 0, len(keys))
+	for _, key := range keys {
+		param, err := getIntQueryParam(values, key)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+
+	return params, nil
+}
+
+// getFloatQueryParams parses required float parameters: a missing one is an error.
+func getFloatQueryParams(values url.Values, keys ...string) ([]float64, error) {
+	params := make([]float64, 0, len(keys))
+	for _, key := range keys {
+		param, err := strconv.ParseFloat(values.Get(key), 64)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+
+	return params, nil
+}
+
+func visibleStatusForRole(role string) string {
+	if role == domain.Moderator {
+		return domain.AnyStatus
+	}
+
+	return domain.ApprovedStatus
+}
+
+func acceptsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), NDJSONContentType)
+}
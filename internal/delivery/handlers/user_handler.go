@@ -4,9 +4,13 @@ import (
 	"avito-test-task/internal/domain"
 	"context"
 	"encoding/json"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -24,6 +28,30 @@ func NewUserHandler(uc domain.UserUsecase, timeout time.Duration, lg *zap.Logger
 	}
 }
 
+// clientIP is the address nginx passes in X-Real-IP, or the peer address of a direct request.
+func clientIP(r *http.Request) string {
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// userIDFromPath reads {id} from /user/{id}/unlock.
+func userIDFromPath(path string) (uuid.UUID, error) {
+	pathParts := strings.Split(path, "/")
+	if len(pathParts) < 3 {
+		return uuid.Nil, strconv.ErrSyntax
+	}
+
+	return uuid.Parse(pathParts[2])
+}
+
 func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var (
 		respBody         []byte
@@ -99,13 +127,15 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	loginRequest.ClientIP = clientIP(r)
+
 	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
 	defer cancel()
 
 	loginResponse, err = h.uc.Login(ctx, &loginRequest, h.lg)
 	if err != nil {
 		h.lg.Warn("user handler: login error", zap.Error(err))
-		respBody = CreateErrorResponse(r.Context(), LoginUserError, LoginUserErrorMsg)
+		respBody = CreateLoginErrorResponse(r.Context(), LoginUserError, LoginUserErrorMsg, err)
 		w.WriteHeader(GetReturnHTTPCode(w, err))
 		w.Write(respBody)
 		return
@@ -156,3 +186,124 @@ func (h *UserHandler) DummyLogin(w http.ResponseWriter, r *http.Request) {
 
 	w.Write(respBody)
 }
+
+func (h *UserHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody        []byte
+		refreshRequest  domain.RefreshTokenRequest
+		refreshResponse domain.LoginUserResponse
+	)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.lg.Warn("user handler: refresh error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ReadHTTPBodyError, ReadHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	err = json.Unmarshal(body, &refreshRequest)
+	if err != nil {
+		h.lg.Warn("user handler: refresh error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UnmarshalHTTPBodyError, UnmarshalHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	refreshResponse, err = h.uc.Refresh(ctx, &refreshRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("user handler: refresh error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), RefreshTokenError, RefreshTokenErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	respBody, err = json.Marshal(refreshResponse)
+	if err != nil {
+		h.lg.Warn("user handler: refresh error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), MarshalHTTPBodyError, MarshalHTTPBodyErrorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	w.Write(respBody)
+}
+
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var (
+		respBody      []byte
+		logoutRequest domain.LogoutRequest
+	)
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.lg.Warn("user handler: logout error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ReadHTTPBodyError, ReadHTTPBodyMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(respBody)
+		return
+	}
+
+	// The body is optional: without it only the access token is revoked.
+	if len(body) > 0 {
+		err = json.Unmarshal(body, &logoutRequest)
+		if err != nil {
+			h.lg.Warn("user handler: logout error", zap.Error(err))
+			respBody = CreateErrorResponse(r.Context(), UnmarshalHTTPBodyError, UnmarshalHTTPBodyMsg)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write(respBody)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	err = h.uc.Logout(ctx, r.Header.Get("authorization"), &logoutRequest, h.lg)
+	if err != nil {
+		h.lg.Warn("user handler: logout error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), LogoutError, LogoutErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	var respBody []byte
+	defer r.Body.Close()
+
+	userID, err := userIDFromPath(r.URL.Path)
+	if err != nil {
+		h.lg.Warn("user handler: unlock error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), ParseURLError, ParseURLErrorMsg)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(respBody)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.dbTimeout*time.Second)
+	defer cancel()
+
+	err = h.uc.Unlock(ctx, userID, h.lg)
+	if err != nil {
+		h.lg.Warn("user handler: unlock error", zap.Error(err))
+		respBody = CreateErrorResponse(r.Context(), UnlockUserError, UnlockUserErrorMsg)
+		w.WriteHeader(GetReturnHTTPCode(w, err))
+		w.Write(respBody)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -8,8 +8,15 @@ import (
 	"regexp"
 )
 
-func isModeratorOnly(path string) bool {
-	return path == "/house/create" || path == "/flat/update"
+func isModeratorOnly(method string, path string) bool {
+	houseUpdate, _ := regexp.MatchString("^/house/[0-9]+$", path)
+	developerChange, _ := regexp.MatchString("^/developer/[0-9]+$", path)
+	userUnlock, _ := regexp.MatchString("^/user/[^/]+/unlock$", path)
+	return path == "/house/create" || path == "/flat/update" || userUnlock ||
+		path == "/moderation/next" || path == "/moderation/extend" ||
+		path == "/developer/create" || path == "/house/import" || path == "/flat/import" ||
+		(method == http.MethodPut && houseUpdate) ||
+		((method == http.MethodPut || method == http.MethodDelete) && developerChange)
 }
 
 func isClientOnly(path string) bool {
@@ -30,7 +37,7 @@ func AccessMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 		}
 
 		path := r.URL.Path
-		if isModeratorOnly(path) && role != domain.Moderator {
+		if isModeratorOnly(r.Method, path) && role != domain.Moderator {
 			respBody = handlers.CreateErrorResponse(r.Context(), handlers.NoAccessError, handlers.NoAccessErrorMsg)
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write(respBody)
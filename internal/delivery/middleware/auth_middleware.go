@@ -2,10 +2,14 @@ package middleware
 
 import (
 	"avito-test-task/internal/delivery/handlers"
+	"avito-test-task/internal/domain"
 	"avito-test-task/pkg"
 	"net/http"
 )
 
+// DenyList rejects revoked access tokens; tokens are not checked against it when it is nil.
+var DenyList domain.TokenDenyList
+
 func AuthMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var respBoby []byte
@@ -18,7 +22,7 @@ func AuthMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		_, err := pkg.ValidateJWTToken(token)
+		claims, err := pkg.ValidateJWTToken(token)
 		if err != nil {
 			respBoby = handlers.CreateErrorResponse(r.Context(), handlers.NotAuthorizedError, handlers.NotAuthorizedErrorMsg)
 			w.WriteHeader(http.StatusUnauthorized)
@@ -26,6 +30,14 @@ func AuthMiddleware(handler http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		tokenID, _ := (*claims)["jti"].(string)
+		if DenyList != nil && tokenID != "" && DenyList.IsRevoked(tokenID) {
+			respBoby = handlers.CreateErrorResponse(r.Context(), handlers.NotAuthorizedError, handlers.NotAuthorizedErrorMsg)
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write(respBoby)
+			return
+		}
+
 		handler.ServeHTTP(w, r)
 	})
 }
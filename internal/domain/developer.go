@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+var (
+	ErrDeveloper_BadRequest = errors.New("bad developer request")
+	ErrDeveloper_BadID      = errors.New("bad developer id")
+	ErrDeveloper_BadName    = errors.New("bad developer name")
+	ErrDeveloper_NotFound   = errors.New("developer not found")
+	ErrDeveloper_NameTaken  = errors.New("developer with this name already exists")
+	ErrDeveloper_HasHouses  = errors.New("developer has houses")
+	ErrDeveloper_BadLimit   = errors.New("bad developers page limit")
+	ErrDeveloper_BadCursor  = errors.New("bad developers page cursor")
+)
+
+type Developer struct {
+	DeveloperID int
+	Name        string
+	CreatedAt   time.Time
+}
+
+type DeveloperRequest struct {
+	Name string `json:"name"`
+}
+
+type DeveloperResponse struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+}
+
+type DevelopersResponse struct {
+	Developers []DeveloperResponse `json:"developers"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+type DeveloperHousesResponse struct {
+	Developer  DeveloperResponse     `json:"developer"`
+	Houses     []CreateHouseResponse `json:"houses"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+type DeveloperUsecase interface {
+	Create(ctx context.Context, req *DeveloperRequest, lg *zap.Logger) (DeveloperResponse, error)
+	GetByID(ctx context.Context, id int, lg *zap.Logger) (DeveloperResponse, error)
+	GetAll(ctx context.Context, cursor string, limit int, lg *zap.Logger) (DevelopersResponse, error)
+	Update(ctx context.Context, id int, req *DeveloperRequest, lg *zap.Logger) (DeveloperResponse, error)
+	DeleteByID(ctx context.Context, id int, lg *zap.Logger) error
+	GetHouses(ctx context.Context, id int, cursor string, limit int, lg *zap.Logger) (DeveloperHousesResponse, error)
+}
+
+type DeveloperRepo interface {
+	Create(ctx context.Context, developer *Developer, lg *zap.Logger) (Developer, error)
+	// GetOrCreateByName returns the developer whose name matches ignoring case, quotes and spaces,
+	// creating it when there is none.
+	GetOrCreateByName(ctx context.Context, name string, lg *zap.Logger) (Developer, error)
+	GetByID(ctx context.Context, id int, lg *zap.Logger) (Developer, error)
+	GetAll(ctx context.Context, afterID int, limit int, lg *zap.Logger) ([]Developer, error)
+	// Update renames the developer together with the developer name stored on its houses.
+	Update(ctx context.Context, developer *Developer, lg *zap.Logger) error
+	DeleteByID(ctx context.Context, id int, lg *zap.Logger) error
+}
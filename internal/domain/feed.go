@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	FeedFormatYandex = "yandex"
+	FeedFormatAvito  = "avito"
+)
+
+var FeedFormats = []string{FeedFormatYandex, FeedFormatAvito}
+
+const (
+	FeedScopeHouse     = "house"
+	FeedScopeDeveloper = "developer"
+)
+
+// FeedChangesBatch is how many changed flats one regeneration pass takes from the queue.
+const FeedChangesBatch = 500
+
+var (
+	ErrFeed_BadFormat = errors.New("bad feed format")
+	ErrFeed_BadScope  = errors.New("bad feed scope")
+	ErrFeed_BadID     = errors.New("bad feed owner id")
+	ErrFeed_NotFound  = errors.New("feed not found")
+	ErrFeed_NoField   = errors.New("feed offer misses required field")
+)
+
+// FeedFlat is a flat queued for feed regeneration together with its house. Exists is false
+// when the flat was deleted after it had been queued.
+type FeedFlat struct {
+	FlatID    int
+	HouseID   int
+	Price     int
+	Rooms     int
+	Status    string
+	CreatedAt time.Time
+	House     House
+	Exists    bool
+	// ChangedAt is the queue mark of the change; the change is dequeued only if it was not marked again.
+	ChangedAt time.Time
+}
+
+// FeedOffer is the rendered offer of a flat in one feed format. An empty Offer removes the flat from that feed.
+type FeedOffer struct {
+	FlatID  int
+	HouseID int
+	Format  string
+	Offer   string
+}
+
+type FeedRequest struct {
+	Format string
+	Scope  string
+	ID     int
+}
+
+type FeedUsecase interface {
+	// GetVersion returns when the feed last changed; it is the base of the feed ETag and Last-Modified.
+	GetVersion(ctx context.Context, req *FeedRequest, lg *zap.Logger) (time.Time, error)
+	// Stream passes the feed document to handle in chunks: the header, every offer and the footer.
+	Stream(ctx context.Context, req *FeedRequest, handle func(chunk string) error, lg *zap.Logger) error
+	RegenerateOffers(ctx context.Context, lg *zap.Logger) (int, error)
+}
+
+type FeedRepo interface {
+	GetChangedFlats(ctx context.Context, limit int, lg *zap.Logger) ([]FeedFlat, error)
+	// SaveOffers stores the offers, dequeues the changed flats and moves the versions of their houses forward.
+	SaveOffers(ctx context.Context, flats []FeedFlat, offers []FeedOffer, lg *zap.Logger) error
+	GetVersion(ctx context.Context, scope string, id int, lg *zap.Logger) (time.Time, error)
+	StreamOffers(ctx context.Context, format string, scope string, id int, handle func(offer string) error, lg *zap.Logger) error
+}
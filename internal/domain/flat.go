@@ -3,8 +3,11 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"io"
+	"time"
 )
 
 const (
@@ -12,9 +15,71 @@ const (
 	ApprovedStatus   = "approved"
 	DeclinedStatus   = "declined"
 	ModeratingStatus = "on moderation"
+	ArchivedStatus   = "archived"
+	SoldStatus       = "sold"
 	AnyStatus        = "any"
 )
 
+// FlatStatuses are all statuses a flat can have.
+var FlatStatuses = []string{CreatedStatus, ModeratingStatus, ApprovedStatus, DeclinedStatus, ArchivedStatus, SoldStatus}
+
+// flatStatusTransitions lists the statuses a moderator may move a flat to from each status.
+// Moderation goes created -> on moderation -> approved/declined; a moderator may release a flat
+// from moderation back to created and take approved and declined flats to moderation again.
+// Owner edits return flats to created without this check.
+var flatStatusTransitions = map[string][]string{
+	CreatedStatus:    {ModeratingStatus, ArchivedStatus, SoldStatus},
+	ModeratingStatus: {ApprovedStatus, DeclinedStatus, CreatedStatus, ArchivedStatus, SoldStatus},
+	ApprovedStatus:   {ModeratingStatus, ArchivedStatus, SoldStatus},
+	DeclinedStatus:   {ModeratingStatus, ArchivedStatus, SoldStatus},
+	ArchivedStatus:   {CreatedStatus},
+	SoldStatus:       {CreatedStatus},
+}
+
+func CanChangeFlatStatus(from string, to string) bool {
+	for _, status := range flatStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FlatTransitionError reports a status change that the state machine does not allow.
+type FlatTransitionError struct {
+	CurrentStatus   string
+	RequestedStatus string
+}
+
+func (e *FlatTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrFlat_IllegalTransition, e.CurrentStatus, e.RequestedStatus)
+}
+
+func (e *FlatTransitionError) Unwrap() error {
+	return ErrFlat_IllegalTransition
+}
+
+// RecentPriceDropPeriod is how long a price drop is shown in flat listings.
+const RecentPriceDropPeriod = 14 * 24 * time.Hour
+
+// LeaseExpiredComment is recorded in the status history when the reclaimer returns a flat to the queue.
+const LeaseExpiredComment = "moderation lease expired"
+
+const (
+	SortByPrice     = "price"
+	SortByRooms     = "rooms"
+	SortByHouse     = "house_id"
+	SortByYear      = "year"
+	SortByDeveloper = "developer"
+
+	AscOrder  = "asc"
+	DescOrder = "desc"
+
+	DefaultSearchLimit = 50
+	MaxSearchLimit     = 1000
+)
+
 var (
 	ErrFlat_BadPrice   = errors.New("bad flat price")
 	ErrFlat_BadID      = errors.New("bad flat id")
@@ -23,6 +88,28 @@ var (
 	ErrFlat_BadNewFlat = errors.New("bad new flat for update")
 	ErrFlat_BadStatus  = errors.New("bad flat status")
 	ErrFlat_BadRequest = errors.New("bad request for create")
+
+	ErrFlat_BadPriceRange = errors.New("bad flat price range")
+	ErrFlat_BadYearRange  = errors.New("bad house construct year range")
+	ErrFlat_BadSortField  = errors.New("bad sort field")
+	ErrFlat_BadSortOrder  = errors.New("bad sort order")
+	ErrFlat_BadLimit      = errors.New("bad limit or offset")
+	ErrFlat_BadCursor     = errors.New("bad flats page cursor")
+
+	ErrFlat_NotFound     = errors.New("flat not found")
+	ErrFlat_Exists       = errors.New("flat already exists")
+	ErrFlat_NotOwner     = errors.New("flat is owned by another user")
+	ErrFlat_OnModeration = errors.New("flat is on moderation by another moderator")
+	ErrFlat_Conflict     = errors.New("flat was changed concurrently")
+	ErrFlat_Withdrawn    = errors.New("flat is already withdrawn")
+	ErrFlat_NotWithdrawn = errors.New("flat is not withdrawn")
+	ErrFlat_QueueEmpty   = errors.New("no flats waiting for moderation")
+	ErrFlat_LeaseNotHeld = errors.New("flat moderation lease is not held by moderator")
+
+	ErrFlat_IllegalTransition = errors.New("flat status transition is not allowed")
+
+	ErrFlat_NoDeclineReason  = errors.New("decline reason is required")
+	ErrFlat_BadDeclineReason = errors.New("unknown decline reason code")
 )
 
 type Flat struct {
@@ -32,7 +119,21 @@ type Flat struct {
 	Price       int
 	Rooms       int
 	Status      string
-	ModeratorID int
+	ModeratorID uuid.UUID
+	// LeaseExpiresAt is zero unless the flat is on moderation.
+	LeaseExpiresAt time.Time
+	// StatusComment is stored in the status history with the next status change.
+	StatusComment string
+	// DeclineReason is empty unless the flat is declined.
+	DeclineReason DeclineReason
+	// PreviousPrice and PriceDroppedAt are set only when the last price change lowered the price.
+	PreviousPrice  int
+	PriceDroppedAt time.Time
+}
+
+type DeclineReason struct {
+	Code string `json:"code"`
+	Text string `json:"text,omitempty"`
 }
 
 type CreateFlatRequest struct {
@@ -46,25 +147,153 @@ type UpdateFlatRequest struct {
 	ID      int    `json:"id"`
 	HouseID int    `json:"house_id"`
 	Status  string `json:"status,omitempty"`
+	Comment string `json:"comment,omitempty"`
+	// Reason is required when Status is declined.
+	Reason *DeclineReason `json:"reason,omitempty"`
+}
+
+type EditFlatRequest struct {
+	ID      int  `json:"id"`
+	HouseID int  `json:"house_id"`
+	Price   *int `json:"price,omitempty"`
+	Rooms   *int `json:"rooms,omitempty"`
 }
 
 type CreateFlatResponse struct {
-	ID      int    `json:"id"`
-	HouseID int    `json:"house_id"`
-	Price   int    `json:"price"`
-	Rooms   int    `json:"rooms"`
-	Status  string `json:"status"`
+	ID             int            `json:"id"`
+	HouseID        int            `json:"house_id"`
+	Price          int            `json:"price"`
+	Rooms          int            `json:"rooms"`
+	Status         string         `json:"status"`
+	LeaseExpiresAt *time.Time     `json:"lease_expires_at,omitempty"`
+	DeclineReason  *DeclineReason `json:"decline_reason,omitempty"`
+}
+
+type SearchFlatRequest struct {
+	MinPrice  int    `json:"price_min"`
+	MaxPrice  int    `json:"price_max"`
+	Rooms     int    `json:"rooms"`
+	HouseID   int    `json:"house_id"`
+	Developer string `json:"developer"`
+	MinYear   int    `json:"year_min"`
+	MaxYear   int    `json:"year_max"`
+	SortBy    string `json:"sort"`
+	Order     string `json:"order"`
+	Limit     int    `json:"limit"`
+	Offset    int    `json:"offset"`
+}
+
+type SearchFlatResponse struct {
+	Flats []SingleFlatResponse `json:"flats"`
+}
+
+type FlatFilter struct {
+	MinPrice  int
+	MaxPrice  int
+	Rooms     int
+	HouseID   int
+	Developer string
+	MinYear   int
+	MaxYear   int
+	Status    string
+	SortBy    string
+	Order     string
+	Limit     int
+	Offset    int
+}
+
+type FlatStatusChange struct {
+	ID        int64
+	FlatID    int
+	HouseID   int
+	OldStatus string
+	NewStatus string
+	ActorID   uuid.UUID
+	ChangedAt time.Time
+	Comment   string
+	// DeclineReason is filled only for transitions to declined.
+	DeclineReason DeclineReason
+}
+
+type FlatStatusChangeResponse struct {
+	OldStatus     string         `json:"old_status,omitempty"`
+	NewStatus     string         `json:"new_status"`
+	ActorID       uuid.UUID      `json:"actor_id"`
+	ChangedAt     time.Time      `json:"changed_at"`
+	Comment       string         `json:"comment,omitempty"`
+	DeclineReason *DeclineReason `json:"decline_reason,omitempty"`
+}
+
+type FlatStatusHistoryResponse struct {
+	History []FlatStatusChangeResponse `json:"history"`
+}
+
+type FlatPriceChange struct {
+	ID        int64
+	FlatID    int
+	HouseID   int
+	Price     int
+	ChangedAt time.Time
+}
+
+type FlatPriceChangeResponse struct {
+	Price     int       `json:"price"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type FlatPriceHistoryResponse struct {
+	ID      int                       `json:"id"`
+	HouseID int                       `json:"house_id"`
+	History []FlatPriceChangeResponse `json:"history"`
+}
+
+type FlatKey struct {
+	FlatID  int
+	HouseID int
+}
+
+// FlatImportConflicts are the imported flats that the database would reject.
+type FlatImportConflicts struct {
+	ExistingFlats map[FlatKey]bool
+	MissingHouses map[int]bool
+}
+
+type FlatCursor struct {
+	HouseID int
+	FlatID  int
 }
 
 type FlatUsecase interface {
 	Create(ctx context.Context, userID uuid.UUID, flatReq *CreateFlatRequest, lg *zap.Logger) (CreateFlatResponse, error)
 	Update(ctx context.Context, moderatorID uuid.UUID, newFlatData *UpdateFlatRequest, lg *zap.Logger) (CreateFlatResponse, error)
+	Search(ctx context.Context, req *SearchFlatRequest, status string, lg *zap.Logger) (SearchFlatResponse, error)
+	Edit(ctx context.Context, userID uuid.UUID, editFlatData *EditFlatRequest, lg *zap.Logger) (CreateFlatResponse, error)
+	Withdraw(ctx context.Context, userID uuid.UUID, role string, withdrawData *UpdateFlatRequest, lg *zap.Logger) (CreateFlatResponse, error)
+	Restore(ctx context.Context, userID uuid.UUID, role string, restoreData *UpdateFlatRequest, lg *zap.Logger) (CreateFlatResponse, error)
+	ClaimNext(ctx context.Context, moderatorID uuid.UUID, lg *zap.Logger) (CreateFlatResponse, error)
+	ExtendLease(ctx context.Context, moderatorID uuid.UUID, leaseData *UpdateFlatRequest, lg *zap.Logger) (CreateFlatResponse, error)
+	ReclaimingLeases(done chan bool, frequency time.Duration, timeout time.Duration, lg *zap.Logger)
+	GetStatusHistory(ctx context.Context, userID uuid.UUID, role string, flatID int, houseID int, lg *zap.Logger) (FlatStatusHistoryResponse, error)
+	GetPriceHistory(ctx context.Context, userID uuid.UUID, role string, flatID int, houseID int, lg *zap.Logger) (FlatPriceHistoryResponse, error)
+	// Import creates flats owned by userID from a CSV or JSONL file, skipping and reporting bad rows.
+	Import(ctx context.Context, userID uuid.UUID, format string, file io.Reader, lg *zap.Logger) (ImportResponse, error)
 }
 
 type FlatRepo interface {
 	Create(ctx context.Context, flat *Flat, lg *zap.Logger) (Flat, error)
 	DeleteByID(ctx context.Context, id int, houseID int, lg *zap.Logger) error
-	Update(ctx context.Context, moderatorID uuid.UUID, newFlatData *Flat, lg *zap.Logger) (Flat, error)
+	Update(ctx context.Context, moderatorID uuid.UUID, newFlatData *Flat, expectedStatus string, leaseTTL time.Duration, lg *zap.Logger) (Flat, error)
+	UpdateByOwner(ctx context.Context, ownerID uuid.UUID, newFlatData *Flat, expectedStatus string, lg *zap.Logger) (Flat, error)
+	UpdateStatus(ctx context.Context, actorID uuid.UUID, newFlatData *Flat, expectedStatus string, lg *zap.Logger) (Flat, error)
+	ClaimNext(ctx context.Context, moderatorID uuid.UUID, leaseTTL time.Duration, lg *zap.Logger) (Flat, error)
+	ExtendLease(ctx context.Context, moderatorID uuid.UUID, flat *Flat, leaseTTL time.Duration, lg *zap.Logger) (Flat, error)
+	ReclaimExpiredLeases(ctx context.Context, lg *zap.Logger) (int64, error)
+	GetStatusHistory(ctx context.Context, flatID int, houseID int, lg *zap.Logger) ([]FlatStatusChange, error)
+	GetPriceHistory(ctx context.Context, flatID int, houseID int, lg *zap.Logger) ([]FlatPriceChange, error)
+	FindImportConflicts(ctx context.Context, flats []Flat, lg *zap.Logger) (FlatImportConflicts, error)
+	// CopyFlats inserts flats with COPY in one transaction; insert triggers still fill the outbox and histories.
+	CopyFlats(ctx context.Context, flats []Flat, lg *zap.Logger) (int64, error)
 	GetByID(ctx context.Context, id int, houseID int, lg *zap.Logger) (Flat, error)
-	GetAll(ctx context.Context, offset int, limit int, lg *zap.Logger) ([]Flat, error)
+	GetAll(ctx context.Context, after FlatCursor, limit int, lg *zap.Logger) ([]Flat, error)
+	Search(ctx context.Context, filter *FlatFilter, lg *zap.Logger) ([]Flat, error)
 }
@@ -5,17 +5,48 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"io"
 	"time"
 )
 
 const FlatThreshhold = 10
 
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+const (
+	MaxNearbyRadiusKm = 500
+	EarthRadiusKm     = 6371.0088
+)
+
+const (
+	GeoJSONFeatureCollectionType = "FeatureCollection"
+	GeoJSONFeatureType           = "Feature"
+	GeoJSONPointType             = "Point"
+)
+
+const (
+	HouseSortByCreated = "created_at"
+	HouseSortByUpdated = "update_at"
+)
+
 var (
 	ErrHouse_BadRequest   = errors.New("bad house request for create")
 	ErrHouse_BadID        = errors.New("bad house id")
 	ErrHouse_BadYear      = errors.New("bad house construct year")
 	ErrHouse_BadDeveloper = errors.New("bad house developer")
 	ErrHouse_BadAddress   = errors.New("bad house address")
+	ErrHouse_NotFound     = errors.New("house not found")
+	ErrHouse_BadSortField = errors.New("bad house sort field")
+	ErrHouse_BadSortOrder = errors.New("bad house sort order")
+	ErrHouse_BadYearRange = errors.New("bad house construct year range")
+	ErrHouse_BadLimit     = errors.New("bad houses page limit")
+	ErrHouse_BadCursor    = errors.New("bad houses page cursor")
+	ErrHouse_BadQuery     = errors.New("bad house search query")
+	ErrHouse_BadCoords    = errors.New("bad house coordinates")
+	ErrHouse_BadRadius    = errors.New("bad search radius")
 )
 
 type House struct {
@@ -25,22 +56,174 @@ type House struct {
 	Developer       string
 	CreateHouseDate time.Time
 	UpdateFlatDate  time.Time
+	// Latitude and Longitude are both nil for houses that were not placed on the map.
+	Latitude  *float64
+	Longitude *float64
+	// DeveloperID is zero for houses whose developer was never set; Developer keeps the developer name.
+	DeveloperID int
 }
 
+// CreateHouseRequest references the developer by DeveloperID. Developer is the deprecated
+// way to pass a developer name; it is used only when DeveloperID is not set.
 type CreateHouseRequest struct {
-	HomeID    int    `json:"id"`
-	Address   string `json:"address"`
-	Year      int    `json:"year"`
-	Developer string `json:"developer"`
+	HomeID      int      `json:"id"`
+	Address     string   `json:"address"`
+	Year        int      `json:"year"`
+	DeveloperID int      `json:"developer_id"`
+	Developer   string   `json:"developer"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+}
+
+// UpdateHouseRequest changes only the fields that are set.
+type UpdateHouseRequest struct {
+	Address     *string  `json:"address"`
+	Year        *int     `json:"year"`
+	DeveloperID *int     `json:"developer_id"`
+	Developer   *string  `json:"developer"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
 }
 
 type CreateHouseResponse struct {
-	HomeID    int    `json:"id"`
-	Address   string `json:"address"`
-	Year      int    `json:"year"`
+	HomeID      int      `json:"id"`
+	Address     string   `json:"address"`
+	Year        int      `json:"year"`
+	DeveloperID int      `json:"developer_id,omitempty"`
+	Developer   string   `json:"developer"`
+	CreatedAt   string   `json:"created_at"`
+	UpdateAt    string   `json:"update_at"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+}
+
+type ListHousesRequest struct {
 	Developer string `json:"developer"`
-	CreatedAt string `json:"created_at"`
-	UpdateAt  string `json:"update_at"`
+	Address   string `json:"address"`
+	MinYear   int    `json:"year_min"`
+	MaxYear   int    `json:"year_max"`
+	SortBy    string `json:"sort"`
+	Order     string `json:"order"`
+	Cursor    string `json:"cursor"`
+	Limit     int    `json:"limit"`
+}
+
+type ListHousesResponse struct {
+	Houses     []CreateHouseResponse `json:"houses"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+type HouseFilter struct {
+	Developer string
+	Address   string
+	MinYear   int
+	MaxYear   int
+	SortBy    string
+	Order     string
+	After     HouseCursor
+	Limit     int
+}
+
+// HouseCursor points at the last house of the previous page; zero HouseID means the first page.
+type HouseCursor struct {
+	Date    time.Time
+	HouseID int
+}
+
+// HouseMatch is a house found by fuzzy search; Score is in [0, 1], the higher the closer.
+type HouseMatch struct {
+	House House
+	Score float64
+}
+
+type HouseMatchResponse struct {
+	CreateHouseResponse
+	Score float64 `json:"score"`
+}
+
+type SearchHousesResponse struct {
+	Houses []HouseMatchResponse `json:"houses"`
+}
+
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type NearbyHousesRequest struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	RadiusKm  float64 `json:"radius_km"`
+	Limit     int     `json:"limit"`
+}
+
+// HouseDistance is a house found by radius search with its great-circle distance to the center.
+type HouseDistance struct {
+	House      House
+	DistanceKm float64
+}
+
+type NearbyHouseResponse struct {
+	CreateHouseResponse
+	DistanceKm float64 `json:"distance_km"`
+}
+
+type NearbyHousesResponse struct {
+	Houses []NearbyHouseResponse `json:"houses"`
+}
+
+type HouseFlatsCount struct {
+	House         House
+	ApprovedFlats int
+}
+
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   GeoJSONPoint           `json:"geometry"`
+	Properties HouseFeatureProperties `json:"properties"`
+}
+
+// GeoJSONPoint keeps coordinates in GeoJSON order: longitude first.
+type GeoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type HouseFeatureProperties struct {
+	ID            int    `json:"id"`
+	Address       string `json:"address"`
+	Year          int    `json:"year"`
+	Developer     string `json:"developer"`
+	ApprovedFlats int    `json:"approved_flats"`
+}
+
+type PriceStats struct {
+	Min    float64 `json:"min"`
+	Median float64 `json:"median"`
+	Max    float64 `json:"max"`
+}
+
+// HouseStats describes the flats of a house visible with one status filter.
+type HouseStats struct {
+	Total        int
+	StatusCounts map[string]int
+	Price        PriceStats
+	PricePerRoom PriceStats
+	RoomCounts   map[int]int
+}
+
+type HouseStatsResponse struct {
+	HouseID      int            `json:"house_id"`
+	Total        int            `json:"total"`
+	ByStatus     map[string]int `json:"by_status"`
+	Price        PriceStats     `json:"price"`
+	PricePerRoom PriceStats     `json:"price_per_room"`
+	Rooms        map[int]int    `json:"rooms"`
 }
 
 type FlatsByHouseRequest struct {
@@ -48,7 +231,8 @@ type FlatsByHouseRequest struct {
 }
 
 type FlatsByHouseResponse struct {
-	Flats []SingleFlatResponse `json:"flats"`
+	Flats      []SingleFlatResponse `json:"flats"`
+	NextCursor string               `json:"next_cursor,omitempty"`
 }
 
 type SingleFlatResponse struct {
@@ -57,21 +241,46 @@ type SingleFlatResponse struct {
 	Price   int    `json:"price"`
 	Rooms   int    `json:"rooms"`
 	Status  string `json:"status"`
+	// PriceDrop is set when the price was lowered within RecentPriceDropPeriod.
+	PriceDrop *PriceDropResponse `json:"price_drop,omitempty"`
+}
+
+type PriceDropResponse struct {
+	PreviousPrice int       `json:"previous_price"`
+	DroppedAt     time.Time `json:"dropped_at"`
 }
 
 type HouseUsecase interface {
 	Create(ctx context.Context, req *CreateHouseRequest, lg *zap.Logger) (CreateHouseResponse, error)
-	GetFlatsByHouseID(ctx context.Context, id int, status string, lg *zap.Logger) (FlatsByHouseResponse, error)
+	// Import creates houses from a CSV or JSONL file, skipping and reporting bad rows.
+	Import(ctx context.Context, format string, file io.Reader, lg *zap.Logger) (ImportResponse, error)
+	Update(ctx context.Context, id int, req *UpdateHouseRequest, lg *zap.Logger) (CreateHouseResponse, error)
+	List(ctx context.Context, req *ListHousesRequest, lg *zap.Logger) (ListHousesResponse, error)
+	SearchByAddress(ctx context.Context, query string, limit int, lg *zap.Logger) (SearchHousesResponse, error)
+	GetNearby(ctx context.Context, req *NearbyHousesRequest, lg *zap.Logger) (NearbyHousesResponse, error)
+	ExportGeoJSON(ctx context.Context, lg *zap.Logger) (GeoJSONFeatureCollection, error)
+	GetFlatsByHouseID(ctx context.Context, id int, status string, cursor string, limit int, lg *zap.Logger) (FlatsByHouseResponse, error)
+	GetStats(ctx context.Context, id int, status string, lg *zap.Logger) (HouseStatsResponse, error)
+	StreamFlatsByHouseID(ctx context.Context, id int, status string, handle func(flat SingleFlatResponse) error, lg *zap.Logger) error
 	SubscribeByID(ctx context.Context, id int, userID uuid.UUID, lg *zap.Logger) error
 	Notifying(done chan bool, frequency time.Duration, timeout time.Duration, lg *zap.Logger)
 }
 
 type HouseRepo interface {
 	Create(ctx context.Context, house *House, lg *zap.Logger) (House, error)
+	// CopyHouses inserts houses with COPY in one transaction and returns them with their new ids.
+	CopyHouses(ctx context.Context, houses []House, lg *zap.Logger) ([]House, error)
 	DeleteByID(ctx context.Context, id int, lg *zap.Logger) error
 	Update(ctx context.Context, newHouseData *House, lg *zap.Logger) error
 	GetByID(ctx context.Context, id int, lg *zap.Logger) (House, error)
-	GetAll(ctx context.Context, offset int, limit int, lg *zap.Logger) ([]House, error)
-	GetFlatsByHouseID(ctx context.Context, id int, status string, lg *zap.Logger) ([]Flat, error)
+	GetAll(ctx context.Context, afterID int, limit int, lg *zap.Logger) ([]House, error)
+	Search(ctx context.Context, filter *HouseFilter, lg *zap.Logger) ([]House, error)
+	SearchByAddress(ctx context.Context, query string, altQuery string, limit int, lg *zap.Logger) ([]HouseMatch, error)
+	GetNearby(ctx context.Context, center GeoPoint, radiusKm float64, limit int, lg *zap.Logger) ([]HouseDistance, error)
+	GetWithApprovedFlats(ctx context.Context, lg *zap.Logger) ([]HouseFlatsCount, error)
+	GetByDeveloperID(ctx context.Context, developerID int, afterID int, limit int, lg *zap.Logger) ([]House, error)
+	GetFlatsByHouseID(ctx context.Context, id int, status string, afterFlatID int, limit int, lg *zap.Logger) ([]Flat, error)
+	GetStats(ctx context.Context, id int, status string, lg *zap.Logger) (HouseStats, error)
+	StreamFlatsByHouseID(ctx context.Context, id int, status string, handle func(flat Flat) error, lg *zap.Logger) error
 	SubscribeByID(ctx context.Context, id int, userID uuid.UUID, lg *zap.Logger) error
 }
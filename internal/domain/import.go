@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+)
+
+const (
+	ImportFormatCSV   = "csv"
+	ImportFormatJSONL = "jsonl"
+
+	// MaxImportRows limits the number of data rows in one import file.
+	MaxImportRows = 100000
+)
+
+var (
+	ErrImport_BadFormat    = errors.New("unknown import format")
+	ErrImport_BadHeader    = errors.New("import csv header misses required columns")
+	ErrImport_TooManyRows  = errors.New("too many rows in import file")
+	ErrImport_BadRow       = errors.New("can't parse import row")
+	ErrImport_DuplicateRow = errors.New("row repeats an earlier row of the import file")
+)
+
+// ImportRowError reports why a row of an import file was skipped. Line is the line number in the file.
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type ImportedHouse struct {
+	Line int `json:"line"`
+	ID   int `json:"id"`
+}
+
+type ImportResponse struct {
+	Total    int `json:"total"`
+	Imported int `json:"imported"`
+	// Houses lists the ids given to imported houses.
+	Houses []ImportedHouse  `json:"houses,omitempty"`
+	Errors []ImportRowError `json:"errors"`
+}
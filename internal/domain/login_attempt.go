@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Failed logins are counted separately for the account and for the client IP.
+const (
+	LoginScopeAccount = "account"
+	LoginScopeIP      = "ip"
+)
+
+var (
+	ErrUser_TooManyAttempts = errors.New("too many failed login attempts, retry later")
+	ErrUser_Locked          = errors.New("account is locked after failed login attempts")
+)
+
+// LoginFailures counts failed logins since the last success or since the counter was forgotten.
+// An attempt is counted as a failure until it succeeds.
+type LoginFailures struct {
+	Failures     int
+	LastFailedAt time.Time
+}
+
+// LoginPolicy sets how failed logins slow down the next attempts. The first FreeAttempts failures
+// cost nothing, each next one doubles the delay from BaseDelay up to MaxDelay, and after
+// AccountLockAttempts failures of an account or IPLockAttempts from an IP attempts are refused
+// for LockDuration. Failures older than Window are forgotten.
+type LoginPolicy struct {
+	FreeAttempts        int
+	BaseDelay           time.Duration
+	MaxDelay            time.Duration
+	AccountLockAttempts int
+	IPLockAttempts      int
+	LockDuration        time.Duration
+	Window              time.Duration
+}
+
+// LoginBlockedError reports a login attempt made before the next attempt is allowed.
+type LoginBlockedError struct {
+	Scope      string
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginBlockedError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", e.Unwrap(), e.Scope, e.RetryAfter)
+}
+
+func (e *LoginBlockedError) Unwrap() error {
+	if e.Locked && e.Scope == LoginScopeAccount {
+		return ErrUser_Locked
+	}
+
+	return ErrUser_TooManyAttempts
+}
+
+type LoginAttemptRepo interface {
+	// RegisterAttempt counts one more failure, starting over if the previous one is out of the window,
+	// and returns the failures counted before it. Concurrent attempts are counted one after another.
+	RegisterAttempt(ctx context.Context, scope string, subject string, window time.Duration, lg *zap.Logger) (LoginFailures, error)
+	// ForgiveAttempt takes back the failure counted for an attempt that turned out to succeed.
+	ForgiveAttempt(ctx context.Context, scope string, subject string, lg *zap.Logger) error
+	Reset(ctx context.Context, scope string, subject string, lg *zap.Logger) error
+	DeleteStale(ctx context.Context, window time.Duration, lg *zap.Logger) error
+}
@@ -10,12 +10,20 @@ const (
 	NoSendedNotifyStatus = "no send"
 )
 
+const (
+	NewFlatNotifyKind      = "new flat"
+	FlatDeclinedNotifyKind = "flat declined"
+)
+
 type Notify struct {
 	ID       int
 	FlatID   int
 	HouseID  int
 	UserMail string
 	Status   string
+	Kind     string
+	// DeclineReason is filled only for FlatDeclinedNotifyKind.
+	DeclineReason DeclineReason
 }
 
 type NotifySender interface {
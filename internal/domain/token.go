@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"time"
+)
+
+var (
+	ErrToken_BadRequest = errors.New("bad nil token request")
+	ErrToken_BadRefresh = errors.New("bad refresh token")
+	ErrToken_Reused     = errors.New("refresh token reused")
+	ErrToken_BadAccess  = errors.New("bad access token")
+)
+
+// RefreshToken is stored only by the hash of its value. Tokens issued one from another by
+// refreshing share FamilyID, so a reused token revokes the whole chain.
+type RefreshToken struct {
+	TokenID   uuid.UUID
+	FamilyID  uuid.UUID
+	UserID    uuid.UUID
+	Role      string
+	TokenHash string
+	ExpiresAt time.Time
+	// AccessTokenID and AccessExpiresAt describe the access token issued together with this one.
+	AccessTokenID   string
+	AccessExpiresAt time.Time
+}
+
+type RevokedToken struct {
+	TokenID   string
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LogoutRequest may name the refresh token of the session to end it too.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenDenyList tells whether an access token was revoked before it expired.
+type TokenDenyList interface {
+	IsRevoked(tokenID string) bool
+	Revoke(tokenID string, expiresAt time.Time)
+}
+
+type TokenRepo interface {
+	Create(ctx context.Context, token *RefreshToken, lg *zap.Logger) error
+	// Use marks the token with the hash as used and returns it with the current role of its user.
+	// Using a token for the second time revokes its family and returns ErrToken_Reused.
+	Use(ctx context.Context, tokenHash string, lg *zap.Logger) (RefreshToken, error)
+	// RevokeFamily revokes the refresh tokens of the user in the family of the token with the hash
+	// together with the access tokens issued with them.
+	RevokeFamily(ctx context.Context, tokenHash string, userID uuid.UUID, lg *zap.Logger) error
+	RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time, lg *zap.Logger) error
+	// GetRevokedAccessTokens returns not yet expired access tokens revoked after since.
+	GetRevokedAccessTokens(ctx context.Context, since time.Time, lg *zap.Logger) ([]RevokedToken, error)
+	DeleteExpired(ctx context.Context, lg *zap.Logger) error
+}
@@ -13,8 +13,9 @@ const (
 )
 
 var (
-	DummyMail     = "dummy@mail.ru"
-	DummyPassword = "dummy_password"
+	// DummyMailFormat makes a unique address of a dummy user from its id.
+	DummyMailFormat = "dummy-%s@mail.ru"
+	DummyPassword   = "dummy_password"
 )
 
 var (
@@ -23,6 +24,8 @@ var (
 	ErrUser_BadMail     = errors.New("bad mail")
 	ErrUser_BadPassword = errors.New("bad password")
 	ErrUser_BadId       = errors.New("bad user id ")
+	ErrUser_MailTaken   = errors.New("user with such mail exists")
+	ErrUser_NoLogin     = errors.New("no mail or user id to login")
 )
 
 type User struct {
@@ -42,13 +45,19 @@ type RegisterUserResponse struct {
 	UserID uuid.UUID `json:"user_id"`
 }
 
+// LoginUserRequest identifies the user by Email; ID is the older way to log in and is used
+// only when Email is not set. ClientIP is filled by the handler to count failed attempts.
 type LoginUserRequest struct {
+	Email    string    `json:"email"`
 	ID       uuid.UUID `json:"id"`
 	Password string    `json:"password"`
+	ClientIP string    `json:"-"`
 }
 
+// LoginUserResponse has no refresh token for dummy logins.
 type LoginUserResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
 type DummyLoginRequest struct {
@@ -59,6 +68,12 @@ type UserUsecase interface {
 	Register(ctx context.Context, userReq *RegisterUserRequest, lg *zap.Logger) (RegisterUserResponse, error)
 	Login(ctx context.Context, userReq *LoginUserRequest, lg *zap.Logger) (LoginUserResponse, error)
 	DummyLogin(ctx context.Context, userType string, lg *zap.Logger) (LoginUserResponse, error)
+	// Refresh exchanges a refresh token for a new pair of tokens; the old refresh token stops working.
+	Refresh(ctx context.Context, req *RefreshTokenRequest, lg *zap.Logger) (LoginUserResponse, error)
+	// Logout revokes the access token and, if it is passed, the refresh token of the session.
+	Logout(ctx context.Context, accessToken string, req *LogoutRequest, lg *zap.Logger) error
+	// Unlock forgets the failed logins of the account, ending its lockout.
+	Unlock(ctx context.Context, userID uuid.UUID, lg *zap.Logger) error
 }
 
 type UserRepo interface {
@@ -66,5 +81,7 @@ type UserRepo interface {
 	DeleteByID(ctx context.Context, id uuid.UUID, lg *zap.Logger) error
 	Update(ctx context.Context, newUserData *User, lg *zap.Logger) error
 	GetByID(ctx context.Context, id uuid.UUID, lg *zap.Logger) (User, error)
+	// GetByMail finds the user ignoring the case of the address.
+	GetByMail(ctx context.Context, mail string, lg *zap.Logger) (User, error)
 	GetAll(ctx context.Context, offset int, limit int, lg *zap.Logger) ([]User, error)
 }
@@ -0,0 +1,176 @@
+package repo
+
+import (
+	"avito-test-task/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+const (
+	foreignKeyViolationCode = "23503"
+	uniqueViolationCode     = "23505"
+)
+
+func isPgError(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+type PostgresDeveloperRepo struct {
+	db           IPool
+	retryAdapter IPostgresRetryAdapter
+}
+
+func NewPostgresDeveloperRepo(db IPool, retryAdapter IPostgresRetryAdapter) *PostgresDeveloperRepo {
+	return &PostgresDeveloperRepo{
+		db:           db,
+		retryAdapter: retryAdapter,
+	}
+}
+
+func (p *PostgresDeveloperRepo) Create(ctx context.Context, developer *domain.Developer, lg *zap.Logger) (domain.Developer, error) {
+	lg.Info("postgres developer repo: create", zap.String("name", developer.Name))
+
+	var createdDeveloper domain.Developer
+	query := `insert into developers(name) values ($1) returning developer_id, name, created_at`
+	err := p.db.QueryRow(ctx, query, developer.Name).Scan(&createdDeveloper.DeveloperID,
+		&createdDeveloper.Name, &createdDeveloper.CreatedAt)
+	if isPgError(err, uniqueViolationCode) {
+		lg.Warn("postgres developer repo: create error", zap.Error(err))
+		return domain.Developer{}, fmt.Errorf("postgres developer repo: create error: %w", domain.ErrDeveloper_NameTaken)
+	}
+	if err != nil {
+		lg.Warn("postgres developer repo: create error", zap.Error(err))
+		return domain.Developer{}, fmt.Errorf("postgres developer repo: create error: %v", err.Error())
+	}
+
+	return createdDeveloper, nil
+}
+
+func (p *PostgresDeveloperRepo) GetOrCreateByName(ctx context.Context, name string, lg *zap.Logger) (domain.Developer, error) {
+	lg.Info("postgres developer repo: get or create by name", zap.String("name", name))
+
+	var developer domain.Developer
+	query := `with inserted as (
+		insert into developers(name) values ($1)
+		on conflict (normalize_developer(name)) do nothing
+		returning developer_id, name, created_at
+	)
+	select developer_id, name, created_at from inserted
+	union all
+	select developer_id, name, created_at from developers
+	where normalize_developer(name) = normalize_developer($1)
+	limit 1`
+	err := p.db.QueryRow(ctx, query, name).Scan(&developer.DeveloperID, &developer.Name, &developer.CreatedAt)
+	if err != nil {
+		lg.Warn("postgres developer repo: get or create by name error", zap.Error(err))
+		return domain.Developer{}, fmt.Errorf("postgres developer repo: get or create by name error: %v", err.Error())
+	}
+
+	return developer, nil
+}
+
+func (p *PostgresDeveloperRepo) GetByID(ctx context.Context, id int, lg *zap.Logger) (domain.Developer, error) {
+	lg.Info("postgres developer repo: get by id", zap.Int("developer_id", id))
+
+	var developer domain.Developer
+	query := `select developer_id, name, created_at from developers where developer_id=$1`
+	err := p.db.QueryRow(ctx, query, id).Scan(&developer.DeveloperID, &developer.Name, &developer.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres developer repo: get by id error", zap.Error(err))
+		return domain.Developer{}, fmt.Errorf("postgres developer repo: get by id error: %w", domain.ErrDeveloper_NotFound)
+	}
+	if err != nil {
+		lg.Warn("postgres developer repo: get by id error", zap.Error(err))
+		return domain.Developer{}, fmt.Errorf("postgres developer repo: get by id error: %v", err.Error())
+	}
+
+	return developer, nil
+}
+
+func (p *PostgresDeveloperRepo) GetAll(ctx context.Context, afterID int, limit int, lg *zap.Logger) ([]domain.Developer, error) {
+	lg.Info("postgres developer repo: get all", zap.Int("after_id", afterID), zap.Int("limit", limit))
+
+	query := `select developer_id, name, created_at from developers
+	where developer_id > $1 order by developer_id limit $2`
+	rows, err := p.db.Query(ctx, query, afterID, limit)
+	if err != nil {
+		lg.Warn("postgres developer repo: get all error", zap.Error(err))
+		return nil, fmt.Errorf("postgres developer repo: get all error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		developers []domain.Developer
+		developer  domain.Developer
+	)
+	for rows.Next() {
+		err = rows.Scan(&developer.DeveloperID, &developer.Name, &developer.CreatedAt)
+		if err != nil {
+			lg.Warn("postgres developer repo: get all error: scan developer error", zap.Error(err))
+			return nil, fmt.Errorf("postgres developer repo: get all error: %v", err.Error())
+		}
+		developers = append(developers, developer)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres developer repo: get all error", zap.Error(err))
+		return nil, fmt.Errorf("postgres developer repo: get all error: %v", err.Error())
+	}
+
+	return developers, nil
+}
+
+func (p *PostgresDeveloperRepo) Update(ctx context.Context, developer *domain.Developer, lg *zap.Logger) error {
+	lg.Info("postgres developer repo: update", zap.Int("developer_id", developer.DeveloperID))
+
+	var renamed int
+	query := `with renamed as (
+		update developers set name=$2 where developer_id=$1
+		returning developer_id, name
+	), synced as (
+		update houses h set developer=r.name
+		from renamed r
+		where h.developer_id = r.developer_id
+	)
+	select count(*) from renamed`
+	err := p.db.QueryRow(ctx, query, developer.DeveloperID, developer.Name).Scan(&renamed)
+	if isPgError(err, uniqueViolationCode) {
+		lg.Warn("postgres developer repo: update error", zap.Error(err))
+		return fmt.Errorf("postgres developer repo: update error: %w", domain.ErrDeveloper_NameTaken)
+	}
+	if err != nil {
+		lg.Warn("postgres developer repo: update error", zap.Error(err))
+		return fmt.Errorf("postgres developer repo: update error: %v", err.Error())
+	}
+	if renamed == 0 {
+		lg.Warn("postgres developer repo: update error: developer not found")
+		return fmt.Errorf("postgres developer repo: update error: %w", domain.ErrDeveloper_NotFound)
+	}
+
+	return nil
+}
+
+func (p *PostgresDeveloperRepo) DeleteByID(ctx context.Context, id int, lg *zap.Logger) error {
+	lg.Info("postgres developer repo: delete by id", zap.Int("developer_id", id))
+
+	query := `delete from developers where developer_id=$1`
+	tag, err := p.db.Exec(ctx, query, id)
+	if isPgError(err, foreignKeyViolationCode) {
+		lg.Warn("postgres developer repo: delete by id error", zap.Error(err))
+		return fmt.Errorf("postgres developer repo: delete by id error: %w", domain.ErrDeveloper_HasHouses)
+	}
+	if err != nil {
+		lg.Warn("postgres developer repo: delete by id error", zap.Error(err))
+		return fmt.Errorf("postgres developer repo: delete by id error: %v", err.Error())
+	}
+	if tag.RowsAffected() == 0 {
+		lg.Warn("postgres developer repo: delete by id error: developer not found")
+		return fmt.Errorf("postgres developer repo: delete by id error: %w", domain.ErrDeveloper_NotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package repo
+
+import (
+	"avito-test-task/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+type PostgresFeedRepo struct {
+	db           IPool
+	retryAdapter IPostgresRetryAdapter
+}
+
+func NewPostgresFeedRepo(db IPool, retryAdapter IPostgresRetryAdapter) *PostgresFeedRepo {
+	return &PostgresFeedRepo{
+		db:           db,
+		retryAdapter: retryAdapter,
+	}
+}
+
+func (p *PostgresFeedRepo) GetChangedFlats(ctx context.Context, limit int, lg *zap.Logger) ([]domain.FeedFlat, error) {
+	lg.Info("postgres feed repo: get changed flats", zap.Int("limit", limit))
+
+	query := `select h.*, c.flat_id, c.changed_at, f.flat_id is not null,
+			coalesce(f.price, 0), coalesce(f.rooms, 0), coalesce(f.status::text, ''),
+			coalesce(f.created_at, now())
+		from feed_changes c
+		join (select ` + houseColumns + ` from houses) h on h.house_id = c.house_id
+		left join flats f on f.flat_id = c.flat_id and f.house_id = c.house_id
+		order by c.changed_at
+		limit $1`
+	rows, err := p.db.Query(ctx, query, limit)
+	if err != nil {
+		lg.Warn("postgres feed repo: get changed flats error", zap.Error(err))
+		return nil, fmt.Errorf("postgres feed repo: get changed flats error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var flats []domain.FeedFlat
+	for rows.Next() {
+		var flat domain.FeedFlat
+		err = scanHouse(rows, &flat.House, &flat.FlatID, &flat.ChangedAt, &flat.Exists,
+			&flat.Price, &flat.Rooms, &flat.Status, &flat.CreatedAt)
+		if err != nil {
+			lg.Warn("postgres feed repo: get changed flats error: scan flat error", zap.Error(err))
+			return nil, fmt.Errorf("postgres feed repo: get changed flats error: %v", err.Error())
+		}
+		flat.HouseID = flat.House.HouseID
+		flats = append(flats, flat)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres feed repo: get changed flats error", zap.Error(err))
+		return nil, fmt.Errorf("postgres feed repo: get changed flats error: %v", err.Error())
+	}
+
+	return flats, nil
+}
+
+func (p *PostgresFeedRepo) SaveOffers(ctx context.Context, flats []domain.FeedFlat, offers []domain.FeedOffer, lg *zap.Logger) error {
+	lg.Info("postgres feed repo: save offers", zap.Int("flats", len(flats)), zap.Int("offers", len(offers)))
+
+	batch := &pgx.Batch{}
+	for _, offer := range offers {
+		if offer.Offer == "" {
+			batch.Queue(`delete from feed_offers where house_id=$1 and flat_id=$2 and format=$3`,
+				offer.HouseID, offer.FlatID, offer.Format)
+			continue
+		}
+		batch.Queue(`insert into feed_offers(flat_id, house_id, format, offer) values ($1, $2, $3, $4)
+			on conflict (house_id, flat_id, format) do update set offer = excluded.offer`,
+			offer.FlatID, offer.HouseID, offer.Format, offer.Offer)
+	}
+
+	// A flat changed again after it was read stays in the queue for the next pass.
+	houses := make(map[int]bool)
+	for _, flat := range flats {
+		batch.Queue(`delete from feed_changes where house_id=$1 and flat_id=$2 and changed_at=$3`,
+			flat.HouseID, flat.FlatID, flat.ChangedAt)
+		if !houses[flat.HouseID] {
+			houses[flat.HouseID] = true
+			batch.Queue(`insert into feed_versions(house_id, updated_at) values ($1, now())
+				on conflict (house_id) do update set updated_at = now()`, flat.HouseID)
+		}
+	}
+
+	tx, err := p.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		lg.Warn("postgres feed repo: save offers error", zap.Error(err))
+		return fmt.Errorf("postgres feed repo: save offers error: %v", err.Error())
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("postgres feed repo: save offers error: %v", err.Error())
+			}
+		}
+	}()
+
+	err = tx.SendBatch(ctx, batch).Close()
+	if err != nil {
+		lg.Warn("postgres feed repo: save offers error", zap.Error(err))
+		return fmt.Errorf("postgres feed repo: save offers error: %v", err.Error())
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		lg.Warn("postgres feed repo: save offers error", zap.Error(err))
+		return fmt.Errorf("postgres feed repo: save offers error: %v", err.Error())
+	}
+
+	return nil
+}
+
+// GetVersion returns the latest version among the houses of the feed. Houses without
+// generated offers yet count as changed at the start of the epoch. A developer feed also
+// changes when the developer loses a house.
+func (p *PostgresFeedRepo) GetVersion(ctx context.Context, scope string, id int, lg *zap.Logger) (time.Time, error) {
+	lg.Info("postgres feed repo: get version", zap.String("scope", scope), zap.Int("id", id))
+
+	query := `select coalesce(max(v.updated_at), to_timestamp(0))
+		from houses h
+		left join feed_versions v on v.house_id = h.house_id
+		where h.house_id = $1
+		having count(h.house_id) > 0`
+	if scope == domain.FeedScopeDeveloper {
+		query = `select greatest(coalesce(max(v.updated_at), to_timestamp(0)),
+				coalesce(max(dv.updated_at), to_timestamp(0)))
+			from developers d
+			left join developer_feed_versions dv on dv.developer_id = d.developer_id
+			left join houses h on h.developer_id = d.developer_id
+			left join feed_versions v on v.house_id = h.house_id
+			where d.developer_id = $1
+			having count(d.developer_id) > 0`
+	}
+
+	var version time.Time
+	err := p.db.QueryRow(ctx, query, id).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres feed repo: get version error", zap.Error(err))
+		return time.Time{}, fmt.Errorf("postgres feed repo: get version error: %w", domain.ErrFeed_NotFound)
+	}
+	if err != nil {
+		lg.Warn("postgres feed repo: get version error", zap.Error(err))
+		return time.Time{}, fmt.Errorf("postgres feed repo: get version error: %v", err.Error())
+	}
+
+	return version, nil
+}
+
+func (p *PostgresFeedRepo) StreamOffers(ctx context.Context, format string, scope string, id int,
+	handle func(offer string) error, lg *zap.Logger) error {
+	lg.Info("postgres feed repo: stream offers", zap.String("format", format),
+		zap.String("scope", scope), zap.Int("id", id))
+
+	query := `select offer from feed_offers
+		where house_id = $2 and format = $1
+		order by flat_id`
+	if scope == domain.FeedScopeDeveloper {
+		query = `select o.offer from feed_offers o
+			join houses h on h.house_id = o.house_id
+			where h.developer_id = $2 and o.format = $1
+			order by o.house_id, o.flat_id`
+	}
+	rows, err := p.db.Query(ctx, query, format, id)
+	if err != nil {
+		lg.Warn("postgres feed repo: stream offers error", zap.Error(err))
+		return fmt.Errorf("postgres feed repo: stream offers error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var offer string
+	for rows.Next() {
+		if err = rows.Scan(&offer); err != nil {
+			lg.Warn("postgres feed repo: stream offers error: scan offer error", zap.Error(err))
+			return fmt.Errorf("postgres feed repo: stream offers error: %v", err.Error())
+		}
+
+		if err = handle(offer); err != nil {
+			lg.Warn("postgres feed repo: stream offers error: handle offer error", zap.Error(err))
+			return fmt.Errorf("postgres feed repo: stream offers error: %v", err.Error())
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres feed repo: stream offers error", zap.Error(err))
+		return fmt.Errorf("postgres feed repo: stream offers error: %v", err.Error())
+	}
+
+	return nil
+}
@@ -3,13 +3,39 @@ package repo
 import (
 	"avito-test-task/internal/domain"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
+	"strings"
 	"time"
 )
 
+var flatSortColumns = map[string]string{
+	domain.SortByPrice:     "f.price",
+	domain.SortByRooms:     "f.rooms",
+	domain.SortByHouse:     "f.house_id",
+	domain.SortByYear:      "h.construct_year",
+	domain.SortByDeveloper: "h.developer",
+}
+
+// scanListedFlat reads the columns flat listings select: flat_id, house_id, user_id, price, rooms,
+// status, previous_price and price_dropped_at.
+func scanListedFlat(row Row, flat *domain.Flat) error {
+	var priceDroppedAt *time.Time
+	err := row.Scan(&flat.ID, &flat.HouseID, &flat.UserID, &flat.Price, &flat.Rooms, &flat.Status,
+		&flat.PreviousPrice, &priceDroppedAt)
+	if err != nil {
+		return err
+	}
+	if priceDroppedAt != nil {
+		flat.PriceDroppedAt = *priceDroppedAt
+	}
+
+	return nil
+}
+
 type PostgresFlatRepo struct {
 	db           IPool
 	retryAdapter IPostgresRetryAdapter
@@ -72,6 +98,124 @@ func (p *PostgresFlatRepo) Create(ctx context.Context, flat *domain.Flat, lg *za
 	return createdFlat, nil
 }
 
+func (p *PostgresFlatRepo) FindImportConflicts(ctx context.Context, flats []domain.Flat, lg *zap.Logger) (domain.FlatImportConflicts, error) {
+	lg.Info("postgres flat repo: find import conflicts", zap.Int("flats", len(flats)))
+
+	flatIDs := make([]int, 0, len(flats))
+	houseIDs := make([]int, 0, len(flats))
+	for _, flat := range flats {
+		flatIDs = append(flatIDs, flat.ID)
+		houseIDs = append(houseIDs, flat.HouseID)
+	}
+
+	conflicts := domain.FlatImportConflicts{
+		ExistingFlats: make(map[domain.FlatKey]bool),
+		MissingHouses: make(map[int]bool),
+	}
+
+	query := `select f.flat_id, f.house_id from flats f
+	join unnest($1::int[], $2::int[]) as k(flat_id, house_id)
+		on f.flat_id = k.flat_id and f.house_id = k.house_id`
+	rows, err := p.db.Query(ctx, query, flatIDs, houseIDs)
+	if err != nil {
+		lg.Warn("postgres flat repo: find import conflicts error", zap.Error(err))
+		return domain.FlatImportConflicts{}, fmt.Errorf("postgres flat repo: find import conflicts error: %v", err.Error())
+	}
+	var key domain.FlatKey
+	for rows.Next() {
+		err = rows.Scan(&key.FlatID, &key.HouseID)
+		if err != nil {
+			rows.Close()
+			lg.Warn("postgres flat repo: find import conflicts error: scan flat", zap.Error(err))
+			return domain.FlatImportConflicts{}, fmt.Errorf("postgres flat repo: find import conflicts error: %v", err.Error())
+		}
+		conflicts.ExistingFlats[key] = true
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres flat repo: find import conflicts error", zap.Error(err))
+		return domain.FlatImportConflicts{}, fmt.Errorf("postgres flat repo: find import conflicts error: %v", err.Error())
+	}
+
+	query = `select distinct k.house_id from unnest($1::int[]) as k(house_id)
+	where not exists (select 1 from houses h where h.house_id = k.house_id)`
+	rows, err = p.db.Query(ctx, query, houseIDs)
+	if err != nil {
+		lg.Warn("postgres flat repo: find import conflicts error", zap.Error(err))
+		return domain.FlatImportConflicts{}, fmt.Errorf("postgres flat repo: find import conflicts error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var houseID int
+	for rows.Next() {
+		err = rows.Scan(&houseID)
+		if err != nil {
+			lg.Warn("postgres flat repo: find import conflicts error: scan house", zap.Error(err))
+			return domain.FlatImportConflicts{}, fmt.Errorf("postgres flat repo: find import conflicts error: %v", err.Error())
+		}
+		conflicts.MissingHouses[houseID] = true
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres flat repo: find import conflicts error", zap.Error(err))
+		return domain.FlatImportConflicts{}, fmt.Errorf("postgres flat repo: find import conflicts error: %v", err.Error())
+	}
+
+	return conflicts, nil
+}
+
+func (p *PostgresFlatRepo) CopyFlats(ctx context.Context, flats []domain.Flat, lg *zap.Logger) (int64, error) {
+	lg.Info("postgres flat repo: copy flats", zap.Int("flats", len(flats)))
+
+	tx, err := p.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		lg.Warn("postgres flat repo: copy flats error", zap.Error(err))
+		return 0, fmt.Errorf("postgres flat repo: copy flats error: %v", err.Error())
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("postgres flat repo: copy flats error: %v", err.Error())
+			}
+		}
+	}()
+
+	// status is left to its column default: COPY can't encode the flat_status enum.
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{"flats"},
+		[]string{"flat_id", "house_id", "user_id", "price", "rooms"},
+		pgx.CopyFromSlice(len(flats), func(i int) ([]any, error) {
+			return []any{flats[i].ID, flats[i].HouseID, flats[i].UserID, flats[i].Price, flats[i].Rooms}, nil
+		}))
+	if err != nil {
+		lg.Warn("postgres flat repo: copy flats error", zap.Error(err))
+		return 0, fmt.Errorf("postgres flat repo: copy flats error: %v", err.Error())
+	}
+
+	seen := make(map[int]bool)
+	houseIDs := make([]int, 0)
+	for _, flat := range flats {
+		if !seen[flat.HouseID] {
+			seen[flat.HouseID] = true
+			houseIDs = append(houseIDs, flat.HouseID)
+		}
+	}
+
+	query := `update houses set update_flat_date=$1 where house_id = any($2)`
+	_, err = tx.Exec(ctx, query, time.Now(), houseIDs)
+	if err != nil {
+		lg.Warn("postgres flat repo: copy flats error", zap.Error(err))
+		return 0, fmt.Errorf("postgres flat repo: copy flats error: %v", err.Error())
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		lg.Error("postgres flat repo: copy flats error", zap.Error(err))
+		return 0, fmt.Errorf("postgres flat repo: copy flats error: %v", err.Error())
+	}
+
+	return copied, nil
+}
+
 func (p *PostgresFlatRepo) DeleteByID(ctx context.Context, flatID int, houseID int, lg *zap.Logger) error {
 	lg.Info("postgres flat repo: delete by id")
 
@@ -85,52 +229,278 @@ func (p *PostgresFlatRepo) DeleteByID(ctx context.Context, flatID int, houseID i
 	return nil
 }
 
-func (p *PostgresFlatRepo) Update(ctx context.Context, moderatorID uuid.UUID, newFlatData *domain.Flat, lg *zap.Logger) (domain.Flat, error) {
+func (p *PostgresFlatRepo) Update(ctx context.Context, moderatorID uuid.UUID, newFlatData *domain.Flat, expectedStatus string, leaseTTL time.Duration, lg *zap.Logger) (domain.Flat, error) {
 	lg.Info("postgres flat repo: update")
 
 	var (
-		flat domain.Flat
+		flat           domain.Flat
+		leaseExpiresAt *time.Time
 	)
 
-	query := `select flat_id, house_id, user_id, price, rooms, status from update_status($1, $2, $3, $4)`
+	query := `select flat_id, house_id, user_id, price, rooms, status, lease_expires_at,
+		coalesce(decline_reason_code, ''), coalesce(decline_reason_text, '')
+	from update_status($1, $2, $3, $4, $5, nullif($6, ''), nullif($7, ''), nullif($8, ''), $9)`
 
 	rows := p.db.QueryRow(ctx, query, newFlatData.Status,
-		newFlatData.ID, newFlatData.HouseID, moderatorID)
+		newFlatData.ID, newFlatData.HouseID, moderatorID, int(leaseTTL.Seconds()), newFlatData.StatusComment,
+		newFlatData.DeclineReason.Code, newFlatData.DeclineReason.Text, expectedStatus)
 	//defer rows.Close()
 
 	err := rows.Scan(&flat.ID, &flat.HouseID, &flat.UserID,
-		&flat.Price, &flat.Rooms, &flat.Status)
+		&flat.Price, &flat.Rooms, &flat.Status, &leaseExpiresAt,
+		&flat.DeclineReason.Code, &flat.DeclineReason.Text)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres flat repo: update error: flat was changed concurrently", zap.Error(err))
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: update error: %w", domain.ErrFlat_Conflict)
+	}
 	if err != nil {
 		lg.Warn("postgres flat repo: update error", zap.Error(err))
 		return domain.Flat{}, fmt.Errorf("postgres flat repo: update error: %v", err.Error())
 	}
+	if leaseExpiresAt != nil {
+		flat.LeaseExpiresAt = *leaseExpiresAt
+	}
 
 	return flat, nil
 }
 
 func (p *PostgresFlatRepo) GetByID(ctx context.Context, flatID int, houseID int, lg *zap.Logger) (domain.Flat, error) {
-	var flat domain.Flat
+	var (
+		flat        domain.Flat
+		moderatorID *uuid.UUID
+	)
 	lg.Info("postgres flat repo: get by id")
 
-	query := `select flat_id, house_id, user_id, price, rooms, status
+	query := `select flat_id, house_id, user_id, price, rooms, status, moderator_id
 	from flats where flat_id=$1 and house_id=$2`
 	rows := p.db.QueryRow(ctx, query, flatID, houseID)
 
 	err := rows.Scan(&flat.ID, &flat.HouseID, &flat.UserID,
-		&flat.Price, &flat.Rooms, &flat.Status)
+		&flat.Price, &flat.Rooms, &flat.Status, &moderatorID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres flat repo: get by id error", zap.Error(err))
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: get by id error: %w", domain.ErrFlat_NotFound)
+	}
 	if err != nil {
 		lg.Warn("postgres flat repo: get by id error", zap.Error(err))
 		return domain.Flat{}, fmt.Errorf("postgres flat repo: get by id error: %v", err.Error())
 	}
+	if moderatorID != nil {
+		flat.ModeratorID = *moderatorID
+	}
+
+	return flat, nil
+}
+
+func (p *PostgresFlatRepo) UpdateByOwner(ctx context.Context, ownerID uuid.UUID, newFlatData *domain.Flat, expectedStatus string, lg *zap.Logger) (domain.Flat, error) {
+	lg.Info("postgres flat repo: update by owner")
+
+	var flat domain.Flat
+
+	query := `update flats set price=$1, rooms=$2, status=$3, status_actor_id=$6, status_comment=null
+	where flat_id=$4 and house_id=$5 and user_id=$6 and status=$7
+	returning flat_id, house_id, user_id, price, rooms, status`
+	rows := p.db.QueryRow(ctx, query, newFlatData.Price, newFlatData.Rooms, newFlatData.Status,
+		newFlatData.ID, newFlatData.HouseID, ownerID, expectedStatus)
+
+	err := rows.Scan(&flat.ID, &flat.HouseID, &flat.UserID,
+		&flat.Price, &flat.Rooms, &flat.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres flat repo: update by owner error: flat was changed concurrently", zap.Error(err))
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: update by owner error: %w", domain.ErrFlat_Conflict)
+	}
+	if err != nil {
+		lg.Warn("postgres flat repo: update by owner error", zap.Error(err))
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: update by owner error: %v", err.Error())
+	}
 
 	return flat, nil
 }
 
-func (p *PostgresFlatRepo) GetAll(ctx context.Context, offset int, limit int, lg *zap.Logger) ([]domain.Flat, error) {
+func (p *PostgresFlatRepo) UpdateStatus(ctx context.Context, actorID uuid.UUID, newFlatData *domain.Flat, expectedStatus string, lg *zap.Logger) (domain.Flat, error) {
+	lg.Info("postgres flat repo: update status")
+
+	var flat domain.Flat
+
+	query := `update flats set status=$1, moderator_id=null, lease_expires_at=null,
+		status_actor_id=$5, status_comment=nullif($6, '')
+	where flat_id=$2 and house_id=$3 and status=$4
+	returning flat_id, house_id, user_id, price, rooms, status`
+	rows := p.db.QueryRow(ctx, query, newFlatData.Status,
+		newFlatData.ID, newFlatData.HouseID, expectedStatus, actorID, newFlatData.StatusComment)
+
+	err := rows.Scan(&flat.ID, &flat.HouseID, &flat.UserID,
+		&flat.Price, &flat.Rooms, &flat.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres flat repo: update status error: flat was changed concurrently", zap.Error(err))
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: update status error: %w", domain.ErrFlat_Conflict)
+	}
+	if err != nil {
+		lg.Warn("postgres flat repo: update status error", zap.Error(err))
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: update status error: %v", err.Error())
+	}
+
+	return flat, nil
+}
+
+func (p *PostgresFlatRepo) ClaimNext(ctx context.Context, moderatorID uuid.UUID, leaseTTL time.Duration, lg *zap.Logger) (domain.Flat, error) {
+	lg.Info("postgres flat repo: claim next", zap.String("moderator_id", moderatorID.String()))
+
+	var flat domain.Flat
+
+	// The created status is written into the query rather than passed as a parameter:
+	// the planner matches only a literal to the predicate of the flats_moderation_queue index.
+	query := `with next_flat as (
+		select flat_id, house_id from flats
+		where status='created'
+		order by created_at, house_id, flat_id
+		limit 1
+		for update skip locked
+	)
+	update flats f set status=$1, moderator_id=$2,
+		lease_expires_at=now() + make_interval(secs => $3),
+		status_actor_id=$2, status_comment=null
+	from next_flat n
+	where f.flat_id=n.flat_id and f.house_id=n.house_id
+	returning f.flat_id, f.house_id, f.user_id, f.price, f.rooms, f.status, f.lease_expires_at`
+	rows := p.db.QueryRow(ctx, query, domain.ModeratingStatus, moderatorID, int(leaseTTL.Seconds()))
+
+	err := rows.Scan(&flat.ID, &flat.HouseID, &flat.UserID,
+		&flat.Price, &flat.Rooms, &flat.Status, &flat.LeaseExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Info("postgres flat repo: claim next: moderation queue is empty")
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: claim next error: %w", domain.ErrFlat_QueueEmpty)
+	}
+	if err != nil {
+		lg.Warn("postgres flat repo: claim next error", zap.Error(err))
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: claim next error: %v", err.Error())
+	}
+	flat.ModeratorID = moderatorID
+
+	return flat, nil
+}
+
+func (p *PostgresFlatRepo) ExtendLease(ctx context.Context, moderatorID uuid.UUID, flat *domain.Flat, leaseTTL time.Duration, lg *zap.Logger) (domain.Flat, error) {
+	lg.Info("postgres flat repo: extend lease", zap.Int("flat_id", flat.ID), zap.Int("house_id", flat.HouseID))
+
+	var extendedFlat domain.Flat
+
+	query := `update flats set lease_expires_at=now() + make_interval(secs => $1)
+	where flat_id=$2 and house_id=$3 and status=$4 and moderator_id=$5
+	returning flat_id, house_id, user_id, price, rooms, status, lease_expires_at`
+	rows := p.db.QueryRow(ctx, query, int(leaseTTL.Seconds()), flat.ID, flat.HouseID,
+		domain.ModeratingStatus, moderatorID)
+
+	err := rows.Scan(&extendedFlat.ID, &extendedFlat.HouseID, &extendedFlat.UserID,
+		&extendedFlat.Price, &extendedFlat.Rooms, &extendedFlat.Status, &extendedFlat.LeaseExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres flat repo: extend lease error: lease is not held", zap.Error(err))
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: extend lease error: %w", domain.ErrFlat_LeaseNotHeld)
+	}
+	if err != nil {
+		lg.Warn("postgres flat repo: extend lease error", zap.Error(err))
+		return domain.Flat{}, fmt.Errorf("postgres flat repo: extend lease error: %v", err.Error())
+	}
+	extendedFlat.ModeratorID = moderatorID
+
+	return extendedFlat, nil
+}
+
+func (p *PostgresFlatRepo) ReclaimExpiredLeases(ctx context.Context, lg *zap.Logger) (int64, error) {
+	lg.Info("postgres flat repo: reclaim expired leases")
+
+	query := `update flats set status=$1, moderator_id=null, lease_expires_at=null,
+		status_actor_id=null, status_comment=$3
+	where status=$2 and lease_expires_at < now()`
+	tag, err := p.db.Exec(ctx, query, domain.CreatedStatus, domain.ModeratingStatus, domain.LeaseExpiredComment)
+	if err != nil {
+		lg.Warn("postgres flat repo: reclaim expired leases error", zap.Error(err))
+		return 0, fmt.Errorf("postgres flat repo: reclaim expired leases error: %v", err.Error())
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (p *PostgresFlatRepo) GetStatusHistory(ctx context.Context, flatID int, houseID int, lg *zap.Logger) ([]domain.FlatStatusChange, error) {
+	lg.Info("postgres flat repo: get status history", zap.Int("flat_id", flatID), zap.Int("house_id", houseID))
+
+	query := `select id, flat_id, house_id, coalesce(old_status::text, ''), new_status,
+		actor_id, changed_at, coalesce(comment, ''),
+		coalesce(decline_reason_code, ''), coalesce(decline_reason_text, '')
+	from flat_status_history where flat_id=$1 and house_id=$2 order by id`
+	rows, err := p.db.Query(ctx, query, flatID, houseID)
+	if err != nil {
+		lg.Warn("postgres flat repo: get status history error", zap.Error(err))
+		return nil, fmt.Errorf("postgres flat repo: get status history error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		change  domain.FlatStatusChange
+		actorID *uuid.UUID
+		history []domain.FlatStatusChange
+	)
+	for rows.Next() {
+		actorID = nil
+		err = rows.Scan(&change.ID, &change.FlatID, &change.HouseID, &change.OldStatus, &change.NewStatus,
+			&actorID, &change.ChangedAt, &change.Comment,
+			&change.DeclineReason.Code, &change.DeclineReason.Text)
+		if err != nil {
+			lg.Warn("postgres flat repo: get status history error: scan", zap.Error(err))
+			return nil, fmt.Errorf("postgres flat repo: get status history error: %v", err.Error())
+		}
+		change.ActorID = uuid.Nil
+		if actorID != nil {
+			change.ActorID = *actorID
+		}
+		history = append(history, change)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres flat repo: get status history error: rows", zap.Error(err))
+		return nil, fmt.Errorf("postgres flat repo: get status history error: %v", err.Error())
+	}
+
+	return history, nil
+}
+
+func (p *PostgresFlatRepo) GetPriceHistory(ctx context.Context, flatID int, houseID int, lg *zap.Logger) ([]domain.FlatPriceChange, error) {
+	lg.Info("postgres flat repo: get price history", zap.Int("flat_id", flatID), zap.Int("house_id", houseID))
+
+	query := `select id, flat_id, house_id, price, changed_at
+	from flat_price_history where flat_id=$1 and house_id=$2 order by id`
+	rows, err := p.db.Query(ctx, query, flatID, houseID)
+	if err != nil {
+		lg.Warn("postgres flat repo: get price history error", zap.Error(err))
+		return nil, fmt.Errorf("postgres flat repo: get price history error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		change  domain.FlatPriceChange
+		history []domain.FlatPriceChange
+	)
+	for rows.Next() {
+		err = rows.Scan(&change.ID, &change.FlatID, &change.HouseID, &change.Price, &change.ChangedAt)
+		if err != nil {
+			lg.Warn("postgres flat repo: get price history error: scan", zap.Error(err))
+			return nil, fmt.Errorf("postgres flat repo: get price history error: %v", err.Error())
+		}
+		history = append(history, change)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres flat repo: get price history error: rows", zap.Error(err))
+		return nil, fmt.Errorf("postgres flat repo: get price history error: %v", err.Error())
+	}
+
+	return history, nil
+}
+
+func (p *PostgresFlatRepo) GetAll(ctx context.Context, after domain.FlatCursor, limit int, lg *zap.Logger) ([]domain.Flat, error) {
 	lg.Info("postgres flat repo: get all")
 
-	query := `select flat_id, house_id, user_id, price, rooms, status from flats limit $1 offset $2`
-	rows, err := p.db.Query(ctx, query, limit, offset)
+	query := `select flat_id, house_id, user_id, price, rooms, status from flats
+	where (house_id, flat_id) > ($1, $2) order by house_id, flat_id limit $3`
+	rows, err := p.db.Query(ctx, query, after.HouseID, after.FlatID, limit)
 	defer rows.Close()
 	if err != nil {
 		lg.Warn("postgres flat repo: get all error", zap.Error(err))
@@ -153,3 +523,87 @@ func (p *PostgresFlatRepo) GetAll(ctx context.Context, offset int, limit int, lg
 
 	return flats, err
 }
+
+func buildFlatSearchQuery(filter *domain.FlatFilter) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.Status != domain.AnyStatus {
+		addCond("f.status=$%d", filter.Status)
+	}
+	if filter.MinPrice > 0 {
+		addCond("f.price>=$%d", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		addCond("f.price<=$%d", filter.MaxPrice)
+	}
+	if filter.Rooms > 0 {
+		addCond("f.rooms=$%d", filter.Rooms)
+	}
+	if filter.HouseID > 0 {
+		addCond("f.house_id=$%d", filter.HouseID)
+	}
+	if filter.Developer != "" {
+		addCond("h.developer=$%d", filter.Developer)
+	}
+	if filter.MinYear > 0 {
+		addCond("h.construct_year>=$%d", filter.MinYear)
+	}
+	if filter.MaxYear > 0 {
+		addCond("h.construct_year<=$%d", filter.MaxYear)
+	}
+
+	query := `select f.flat_id, f.house_id, f.user_id, f.price, f.rooms, f.status,
+		coalesce(f.previous_price, 0), f.price_dropped_at
+	from flats f join houses h on f.house_id = h.house_id`
+	if len(conds) > 0 {
+		query += " where " + strings.Join(conds, " and ")
+	}
+
+	sortColumn, ok := flatSortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = flatSortColumns[domain.SortByPrice]
+	}
+	order := "asc"
+	if filter.Order == domain.DescOrder {
+		order = "desc"
+	}
+	query += fmt.Sprintf(" order by %s %s, f.house_id, f.flat_id", sortColumn, order)
+
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" limit $%d offset $%d", len(args)-1, len(args))
+
+	return query, args
+}
+
+func (p *PostgresFlatRepo) Search(ctx context.Context, filter *domain.FlatFilter, lg *zap.Logger) ([]domain.Flat, error) {
+	lg.Info("postgres flat repo: search")
+
+	query, args := buildFlatSearchQuery(filter)
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		lg.Warn("postgres flat repo: search error", zap.Error(err))
+		return nil, fmt.Errorf("postgres flat repo: search error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var flats []domain.Flat
+	for rows.Next() {
+		flat := domain.Flat{}
+		err = scanListedFlat(rows, &flat)
+		if err != nil {
+			lg.Warn("postgres flat repo: search error: scan flat error", zap.Error(err))
+			continue
+		}
+		flats = append(flats, flat)
+	}
+
+	return flats, rows.Err()
+}
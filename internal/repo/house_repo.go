@@ -3,12 +3,34 @@ package repo
 import (
 	"avito-test-task/internal/domain"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
+	"math"
+	"strings"
 )
 
+var houseSortColumns = map[string]string{
+	domain.HouseSortByCreated: "create_house_date",
+	domain.HouseSortByUpdated: "update_flat_date",
+}
+
+// houseColumns are the columns scanHouse reads, in its order.
+const houseColumns = `house_id, address, construct_year, developer, create_house_date, update_flat_date,
+	latitude, longitude, coalesce(developer_id, 0) as developer_id`
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// scanHouse reads houseColumns into house and then the rest of the row into extra.
+func scanHouse(row Row, house *domain.House, extra ...any) error {
+	dest := []any{&house.HouseID, &house.Address, &house.ConstructYear, &house.Developer,
+		&house.CreateHouseDate, &house.UpdateFlatDate, &house.Latitude, &house.Longitude, &house.DeveloperID}
+
+	return row.Scan(append(dest, extra...)...)
+}
+
 type PostgresHouseRepo struct {
 	db           IPool
 	retryAdapter IPostgresRetryAdapter
@@ -25,16 +47,15 @@ func (p *PostgresHouseRepo) Create(ctx context.Context, house *domain.House, lg
 	lg.Info("create house", zap.Int("house_id", house.HouseID))
 
 	var createdHouse domain.House
-	query := `insert into houses(address, construct_year, developer, create_house_date, update_flat_date)
-	values ($1, $2, $3, $4, $5) returning *`
+	query := `insert into houses(address, construct_year, developer, create_house_date, update_flat_date,
+		latitude, longitude, developer_id)
+	values ($1, $2, $3, $4, $5, $6, $7, nullif($8, 0)) returning ` + houseColumns
 	rows := p.db.QueryRow(ctx, query,
 		house.Address, house.ConstructYear,
 		house.Developer, house.CreateHouseDate,
-		house.UpdateFlatDate)
+		house.UpdateFlatDate, house.Latitude, house.Longitude, house.DeveloperID)
 
-	err := rows.Scan(&createdHouse.HouseID, &createdHouse.Address,
-		&createdHouse.ConstructYear, &createdHouse.Developer,
-		&createdHouse.CreateHouseDate, &createdHouse.UpdateFlatDate)
+	err := scanHouse(rows, &createdHouse)
 	if err != nil {
 		lg.Warn("postgres house create error", zap.Error(err))
 		return domain.House{}, err
@@ -43,6 +64,68 @@ func (p *PostgresHouseRepo) Create(ctx context.Context, house *domain.House, lg
 	return createdHouse, nil
 }
 
+func (p *PostgresHouseRepo) CopyHouses(ctx context.Context, houses []domain.House, lg *zap.Logger) ([]domain.House, error) {
+	lg.Info("postgres house repo: copy houses", zap.Int("houses", len(houses)))
+
+	tx, err := p.db.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		lg.Warn("postgres house repo: copy houses error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: copy houses error: %v", err.Error())
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("postgres house repo: copy houses error: %v", err.Error())
+			}
+		}
+	}()
+
+	// COPY returns no ids, so they are taken from the houses sequence beforehand.
+	query := `select nextval(pg_get_serial_sequence('houses', 'house_id')) from generate_series(1, $1)`
+	rows, err := tx.Query(ctx, query, len(houses))
+	if err != nil {
+		lg.Warn("postgres house repo: copy houses error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: copy houses error: %v", err.Error())
+	}
+	copiedHouses := make([]domain.House, len(houses))
+	copy(copiedHouses, houses)
+	for i := 0; rows.Next() && i < len(copiedHouses); i++ {
+		err = rows.Scan(&copiedHouses[i].HouseID)
+		if err != nil {
+			rows.Close()
+			lg.Warn("postgres house repo: copy houses error: scan house id", zap.Error(err))
+			return nil, fmt.Errorf("postgres house repo: copy houses error: %v", err.Error())
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres house repo: copy houses error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: copy houses error: %v", err.Error())
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"houses"},
+		[]string{"house_id", "address", "construct_year", "developer", "create_house_date", "update_flat_date",
+			"latitude", "longitude", "developer_id"},
+		pgx.CopyFromSlice(len(copiedHouses), func(i int) ([]any, error) {
+			house := copiedHouses[i]
+			return []any{house.HouseID, house.Address, house.ConstructYear, house.Developer, house.CreateHouseDate,
+				house.UpdateFlatDate, house.Latitude, house.Longitude, house.DeveloperID}, nil
+		}))
+	if err != nil {
+		lg.Warn("postgres house repo: copy houses error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: copy houses error: %v", err.Error())
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		lg.Error("postgres house repo: copy houses error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: copy houses error: %v", err.Error())
+	}
+
+	return copiedHouses, nil
+}
+
 func (p *PostgresHouseRepo) DeleteByID(ctx context.Context, id int, lg *zap.Logger) error {
 	lg.Info("delete house", zap.Int("house_id", id))
 
@@ -64,11 +147,15 @@ func (p *PostgresHouseRepo) Update(ctx context.Context, newHouseData *domain.Hou
                   				construct_year=$3,
                   				developer=$4,
                   				create_house_date=$5,
-                  				update_flat_date=$6
+                  				update_flat_date=$6,
+                  				latitude=$7,
+                  				longitude=$8,
+                  				developer_id=nullif($9, 0)
                   				where house_id=$1`
 	_, err := p.db.Exec(ctx, query, newHouseData.HouseID, newHouseData.Address,
 		newHouseData.ConstructYear, newHouseData.Developer,
-		newHouseData.CreateHouseDate, newHouseData.UpdateFlatDate)
+		newHouseData.CreateHouseDate, newHouseData.UpdateFlatDate,
+		newHouseData.Latitude, newHouseData.Longitude, newHouseData.DeveloperID)
 	if err != nil {
 		lg.Warn("postgres house update error", zap.Error(err))
 		return err
@@ -81,12 +168,14 @@ func (p *PostgresHouseRepo) GetByID(ctx context.Context, id int, lg *zap.Logger)
 	lg.Info("get house by id", zap.Int("id", id))
 	var house domain.House
 
-	query := `select * from houses where house_id=$1`
+	query := `select ` + houseColumns + ` from houses where house_id=$1`
 	rows := p.db.QueryRow(ctx, query, id)
 
-	err := rows.Scan(&house.HouseID, &house.Address,
-		&house.ConstructYear, &house.Developer,
-		&house.CreateHouseDate, &house.UpdateFlatDate)
+	err := scanHouse(rows, &house)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres house get by id error", zap.Error(err))
+		return domain.House{}, fmt.Errorf("postgres house get by id error: %w", domain.ErrHouse_NotFound)
+	}
 	if err != nil {
 		lg.Warn("postgres house get by id error", zap.Error(err))
 		return domain.House{}, err
@@ -95,11 +184,11 @@ func (p *PostgresHouseRepo) GetByID(ctx context.Context, id int, lg *zap.Logger)
 	return house, nil
 }
 
-func (p *PostgresHouseRepo) GetAll(ctx context.Context, offset int, limit int, lg *zap.Logger) ([]domain.House, error) {
-	lg.Info("get houses", zap.Int("offset", offset), zap.Int("limit", limit))
+func (p *PostgresHouseRepo) GetAll(ctx context.Context, afterID int, limit int, lg *zap.Logger) ([]domain.House, error) {
+	lg.Info("get houses", zap.Int("after_id", afterID), zap.Int("limit", limit))
 
-	query := `select * from houses order by house_id limit $1 offset $2`
-	rows, err := p.db.Query(ctx, query, limit, offset)
+	query := `select ` + houseColumns + ` from houses where house_id > $1 order by house_id limit $2`
+	rows, err := p.db.Query(ctx, query, afterID, limit)
 	defer rows.Close()
 	if err != nil {
 		lg.Warn("postgres house get all error", zap.Error(err))
@@ -111,8 +200,7 @@ func (p *PostgresHouseRepo) GetAll(ctx context.Context, offset int, limit int, l
 		house  domain.House
 	)
 	for rows.Next() {
-		err = rows.Scan(&house.HouseID, &house.Address, &house.ConstructYear,
-			&house.Developer, &house.CreateHouseDate, &house.UpdateFlatDate)
+		err = scanHouse(rows, &house)
 		if err != nil {
 			lg.Warn("postgres house get all error: scan house error")
 			continue
@@ -123,26 +211,336 @@ func (p *PostgresHouseRepo) GetAll(ctx context.Context, offset int, limit int, l
 	return houses, err
 }
 
-func (p *PostgresHouseRepo) GetFlatsByHouseID(ctx context.Context, id int, status string, lg *zap.Logger) ([]domain.Flat, error) {
-	lg.Info("get flats by house id", zap.Int("house_id", id))
+func buildHouseSearchQuery(filter *domain.HouseFilter) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.Developer != "" {
+		addCond("developer=$%d", filter.Developer)
+	}
+	if filter.MinYear > 0 {
+		addCond("construct_year>=$%d", filter.MinYear)
+	}
+	if filter.MaxYear > 0 {
+		addCond("construct_year<=$%d", filter.MaxYear)
+	}
+	if filter.Address != "" {
+		addCond("address ilike $%d", "%"+likeEscaper.Replace(filter.Address)+"%")
+	}
+
+	sortColumn, ok := houseSortColumns[filter.SortBy]
+	if !ok {
+		sortColumn = houseSortColumns[domain.HouseSortByCreated]
+	}
+	order, cmp := "asc", ">"
+	if filter.Order == domain.DescOrder {
+		order, cmp = "desc", "<"
+	}
+
+	if filter.After.HouseID > 0 {
+		args = append(args, filter.After.Date, filter.After.HouseID)
+		conds = append(conds, fmt.Sprintf("(%s, house_id) %s ($%d, $%d)", sortColumn, cmp, len(args)-1, len(args)))
+	}
+
+	query := `select ` + houseColumns + ` from houses`
+	if len(conds) > 0 {
+		query += " where " + strings.Join(conds, " and ")
+	}
+	query += fmt.Sprintf(" order by %s %s, house_id %s", sortColumn, order, order)
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" limit $%d", len(args))
+
+	return query, args
+}
+
+func (p *PostgresHouseRepo) Search(ctx context.Context, filter *domain.HouseFilter, lg *zap.Logger) ([]domain.House, error) {
+	lg.Info("postgres house repo: search")
+
+	query, args := buildHouseSearchQuery(filter)
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		lg.Warn("postgres house repo: search error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: search error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		houses []domain.House
+		house  domain.House
+	)
+	for rows.Next() {
+		err = scanHouse(rows, &house)
+		if err != nil {
+			lg.Warn("postgres house repo: search error: scan house error", zap.Error(err))
+			return nil, fmt.Errorf("postgres house repo: search error: %v", err.Error())
+		}
+		houses = append(houses, house)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres house repo: search error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: search error: %v", err.Error())
+	}
+
+	return houses, nil
+}
+
+// SearchByAddress matches both queries against the normalized address and the developer
+// using the trigram indexes; altQuery is an alternative spelling of query, e.g. transliterated.
+func (p *PostgresHouseRepo) SearchByAddress(ctx context.Context, query string, altQuery string, limit int, lg *zap.Logger) ([]domain.HouseMatch, error) {
+	lg.Info("postgres house repo: search by address", zap.String("query", query))
+
+	sqlQuery := `select ` + houseColumns + `,
+		greatest(word_similarity(normalize_address($1), normalize_address(address)),
+			word_similarity(normalize_address($2), normalize_address(address)),
+			word_similarity(lower($1), lower(developer)),
+			word_similarity(lower($2), lower(developer))) as score
+	from houses
+	where normalize_address($1) <% normalize_address(address)
+		or normalize_address($2) <% normalize_address(address)
+		or lower($1) <% lower(developer)
+		or lower($2) <% lower(developer)
+	order by score desc, house_id
+	limit $3`
+	rows, err := p.db.Query(ctx, sqlQuery, query, altQuery, limit)
+	if err != nil {
+		lg.Warn("postgres house repo: search by address error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: search by address error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		matches []domain.HouseMatch
+		match   domain.HouseMatch
+	)
+	for rows.Next() {
+		err = scanHouse(rows, &match.House, &match.Score)
+		if err != nil {
+			lg.Warn("postgres house repo: search by address error: scan house error", zap.Error(err))
+			return nil, fmt.Errorf("postgres house repo: search by address error: %v", err.Error())
+		}
+		matches = append(matches, match)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres house repo: search by address error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: search by address error: %v", err.Error())
+	}
+
+	return matches, nil
+}
+
+// boundingBox returns the latitude and longitude ranges that contain the circle around center.
+// Near the poles and across the antimeridian it falls back to the whole longitude range.
+func boundingBox(center domain.GeoPoint, radiusKm float64) (minLat, maxLat, minLon, maxLon float64) {
+	deltaLat := radiusKm / domain.EarthRadiusKm * 180 / math.Pi
+	minLat, maxLat = center.Latitude-deltaLat, center.Latitude+deltaLat
+	minLon, maxLon = -180, 180
+	if minLat <= -90 || maxLat >= 90 {
+		return math.Max(minLat, -90), math.Min(maxLat, 90), minLon, maxLon
+	}
+
+	// The widest point of the circle is not due east but closer to the pole, hence asin rather than
+	// dividing deltaLat by the cosine of the latitude.
+	sinDeltaLon := math.Sin(radiusKm/domain.EarthRadiusKm) / math.Cos(center.Latitude*math.Pi/180)
+	if sinDeltaLon >= 1 {
+		return minLat, maxLat, minLon, maxLon
+	}
+
+	deltaLon := math.Asin(sinDeltaLon) * 180 / math.Pi
+	if center.Longitude-deltaLon >= -180 && center.Longitude+deltaLon <= 180 {
+		minLon, maxLon = center.Longitude-deltaLon, center.Longitude+deltaLon
+	}
+
+	return minLat, maxLat, minLon, maxLon
+}
+
+func (p *PostgresHouseRepo) GetNearby(ctx context.Context, center domain.GeoPoint, radiusKm float64, limit int, lg *zap.Logger) ([]domain.HouseDistance, error) {
+	lg.Info("postgres house repo: get nearby",
+		zap.Float64("lat", center.Latitude), zap.Float64("lon", center.Longitude), zap.Float64("radius_km", radiusKm))
+
+	minLat, maxLat, minLon, maxLon := boundingBox(center, radiusKm)
+	query := `select ` + houseColumns + `, distance_km
+	from (
+		select ` + houseColumns + `,
+			2 * $3 * asin(least(1, sqrt(
+				power(sin(radians(latitude - $1) / 2), 2) +
+				cos(radians($1)) * cos(radians(latitude)) * power(sin(radians(longitude - $2) / 2), 2)
+			))) as distance_km
+		from houses
+		where latitude between $4 and $5 and longitude between $6 and $7
+	) nearby
+	where distance_km <= $8
+	order by distance_km, house_id
+	limit $9`
+	rows, err := p.db.Query(ctx, query, center.Latitude, center.Longitude, domain.EarthRadiusKm,
+		minLat, maxLat, minLon, maxLon, radiusKm, limit)
+	if err != nil {
+		lg.Warn("postgres house repo: get nearby error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: get nearby error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		houses []domain.HouseDistance
+		house  domain.HouseDistance
+	)
+	for rows.Next() {
+		err = scanHouse(rows, &house.House, &house.DistanceKm)
+		if err != nil {
+			lg.Warn("postgres house repo: get nearby error: scan house error", zap.Error(err))
+			return nil, fmt.Errorf("postgres house repo: get nearby error: %v", err.Error())
+		}
+		houses = append(houses, house)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres house repo: get nearby error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: get nearby error: %v", err.Error())
+	}
+
+	return houses, nil
+}
+
+func (p *PostgresHouseRepo) GetWithApprovedFlats(ctx context.Context, lg *zap.Logger) ([]domain.HouseFlatsCount, error) {
+	lg.Info("postgres house repo: get with approved flats")
+
+	query := `select h.house_id, h.address, h.construct_year, h.developer, h.create_house_date, h.update_flat_date,
+		h.latitude, h.longitude, coalesce(h.developer_id, 0), count(f.flat_id) filter (where f.status = 'approved')
+	from houses h left join flats f on f.house_id = h.house_id
+	where h.latitude is not null
+	group by h.house_id
+	order by h.house_id`
+	rows, err := p.db.Query(ctx, query)
+	if err != nil {
+		lg.Warn("postgres house repo: get with approved flats error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: get with approved flats error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		houses []domain.HouseFlatsCount
+		house  domain.HouseFlatsCount
+	)
+	for rows.Next() {
+		err = scanHouse(rows, &house.House, &house.ApprovedFlats)
+		if err != nil {
+			lg.Warn("postgres house repo: get with approved flats error: scan house error", zap.Error(err))
+			return nil, fmt.Errorf("postgres house repo: get with approved flats error: %v", err.Error())
+		}
+		houses = append(houses, house)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres house repo: get with approved flats error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: get with approved flats error: %v", err.Error())
+	}
+
+	return houses, nil
+}
+
+func (p *PostgresHouseRepo) GetByDeveloperID(ctx context.Context, developerID int, afterID int, limit int, lg *zap.Logger) ([]domain.House, error) {
+	lg.Info("postgres house repo: get by developer id",
+		zap.Int("developer_id", developerID), zap.Int("after_id", afterID), zap.Int("limit", limit))
+
+	query := `select ` + houseColumns + ` from houses
+	where developer_id=$1 and house_id > $2
+	order by house_id
+	limit $3`
+	rows, err := p.db.Query(ctx, query, developerID, afterID, limit)
+	if err != nil {
+		lg.Warn("postgres house repo: get by developer id error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: get by developer id error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		houses []domain.House
+		house  domain.House
+	)
+	for rows.Next() {
+		err = scanHouse(rows, &house)
+		if err != nil {
+			lg.Warn("postgres house repo: get by developer id error: scan house error", zap.Error(err))
+			return nil, fmt.Errorf("postgres house repo: get by developer id error: %v", err.Error())
+		}
+		houses = append(houses, house)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres house repo: get by developer id error", zap.Error(err))
+		return nil, fmt.Errorf("postgres house repo: get by developer id error: %v", err.Error())
+	}
+
+	return houses, nil
+}
+
+func (p *PostgresHouseRepo) GetStats(ctx context.Context, id int, status string, lg *zap.Logger) (domain.HouseStats, error) {
+	lg.Info("postgres house repo: get stats", zap.Int("house_id", id), zap.String("status", status))
+
+	args := []any{id}
+	statusCond := ""
+	if status != domain.AnyStatus {
+		args = append(args, status)
+		statusCond = " and status=$2"
+	}
+
+	query := `with visible as (
+		select price, rooms, status, price::float8 / nullif(rooms, 0) as price_per_room
+		from flats
+		where house_id=$1` + statusCond + `
+	)
+	select count(*),
+		coalesce((select json_object_agg(status, flats)
+			from (select status, count(*) as flats from visible group by status) by_status), '{}'),
+		coalesce(min(price)::float8, 0),
+		coalesce(percentile_cont(0.5) within group (order by price), 0),
+		coalesce(max(price)::float8, 0),
+		coalesce(min(price_per_room), 0),
+		coalesce(percentile_cont(0.5) within group (order by price_per_room), 0),
+		coalesce(max(price_per_room), 0),
+		coalesce((select json_object_agg(rooms, flats)
+			from (select rooms, count(*) as flats from visible group by rooms) by_rooms), '{}')
+	from visible`
+
+	var stats domain.HouseStats
+	err := p.db.QueryRow(ctx, query, args...).Scan(&stats.Total, &stats.StatusCounts,
+		&stats.Price.Min, &stats.Price.Median, &stats.Price.Max,
+		&stats.PricePerRoom.Min, &stats.PricePerRoom.Median, &stats.PricePerRoom.Max,
+		&stats.RoomCounts)
+	if err != nil {
+		lg.Warn("postgres house repo: get stats error", zap.Error(err))
+		return domain.HouseStats{}, fmt.Errorf("postgres house repo: get stats error: %v", err.Error())
+	}
+
+	return stats, nil
+}
+
+func (p *PostgresHouseRepo) GetFlatsByHouseID(ctx context.Context, id int, status string, afterFlatID int, limit int, lg *zap.Logger) ([]domain.Flat, error) {
+	lg.Info("get flats by house id", zap.Int("house_id", id), zap.Int("after_flat_id", afterFlatID))
 
 	query := ``
 	var (
 		rows pgx.Rows
 		err  error
 	)
-	if status == domain.ModeratingStatus {
-		query = `select flat_id, houses.house_id, user_id, price, rooms, status 
-			from flats join houses
-			on flats.house_id = houses.house_id
-			where houses.house_id=$1 and flats.status=$2`
-		rows, err = p.db.Query(ctx, query, id, status)
+	if status != domain.AnyStatus {
+		query = `select flat_id, house_id, user_id, price, rooms, status,
+				coalesce(previous_price, 0), price_dropped_at
+			from flats
+			where house_id=$1 and flat_id > $2 and status=$3
+			order by flat_id limit $4`
+		rows, err = p.db.Query(ctx, query, id, afterFlatID, status, limit)
 	} else {
-		query = `select flat_id, houses.house_id, user_id, price, rooms, status 
-			from flats join houses
-			on flats.house_id = houses.house_id
-			where houses.house_id=$1`
-		rows, err = p.db.Query(ctx, query, id)
+		query = `select flat_id, house_id, user_id, price, rooms, status,
+				coalesce(previous_price, 0), price_dropped_at
+			from flats
+			where house_id=$1 and flat_id > $2
+			order by flat_id limit $3`
+		rows, err = p.db.Query(ctx, query, id, afterFlatID, limit)
 	}
 
 	defer rows.Close()
@@ -154,7 +552,7 @@ func (p *PostgresHouseRepo) GetFlatsByHouseID(ctx context.Context, id int, statu
 	var flats []domain.Flat
 	for rows.Next() {
 		flat := domain.Flat{}
-		err = rows.Scan(&flat.ID, &flat.HouseID, &flat.UserID, &flat.Price, &flat.Rooms, &flat.Status)
+		err = scanListedFlat(rows, &flat)
 		if err != nil {
 			lg.Warn("postgres house repo: get all error: scan house error", zap.Error(err))
 			continue
@@ -165,6 +563,43 @@ func (p *PostgresHouseRepo) GetFlatsByHouseID(ctx context.Context, id int, statu
 	return flats, err
 }
 
+func (p *PostgresHouseRepo) StreamFlatsByHouseID(ctx context.Context, id int, status string, handle func(flat domain.Flat) error, lg *zap.Logger) error {
+	lg.Info("stream flats by house id", zap.Int("house_id", id))
+
+	query := `select flat_id, house_id, user_id, price, rooms, status,
+			coalesce(previous_price, 0), price_dropped_at
+		from flats
+		where house_id=$1 and ($2::text = 'any' or status::text = $2)
+		order by flat_id`
+	rows, err := p.db.Query(ctx, query, id, status)
+	if err != nil {
+		lg.Warn("postgres house repo: stream flats by house id error", zap.Error(err))
+		return fmt.Errorf("postgres house repo: stream flats by house id error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		flat := domain.Flat{}
+		err = scanListedFlat(rows, &flat)
+		if err != nil {
+			lg.Warn("postgres house repo: stream flats by house id error: scan flat error", zap.Error(err))
+			return fmt.Errorf("postgres house repo: stream flats by house id error: %v", err.Error())
+		}
+
+		if err = handle(flat); err != nil {
+			lg.Warn("postgres house repo: stream flats by house id error: handle flat error", zap.Error(err))
+			return fmt.Errorf("postgres house repo: stream flats by house id error: %v", err.Error())
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres house repo: stream flats by house id error", zap.Error(err))
+		return fmt.Errorf("postgres house repo: stream flats by house id error: %v", err.Error())
+	}
+
+	return nil
+}
+
 func (p *PostgresHouseRepo) SubscribeByID(ctx context.Context, houseID int, userID uuid.UUID, lg *zap.Logger) error {
 	lg.Info("postgres house repo: subscribe by id")
 
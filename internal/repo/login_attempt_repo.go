@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"avito-test-task/internal/domain"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"time"
+)
+
+type PostgresLoginAttemptRepo struct {
+	db           IPool
+	retryAdapter IPostgresRetryAdapter
+}
+
+func NewPostgresLoginAttemptRepo(db IPool, retryAdapter IPostgresRetryAdapter) *PostgresLoginAttemptRepo {
+	return &PostgresLoginAttemptRepo{
+		db:           db,
+		retryAdapter: retryAdapter,
+	}
+}
+
+// RegisterAttempt relies on the row lock of the upsert: a concurrent attempt waits for this one
+// and then reads the counter it has left.
+func (p *PostgresLoginAttemptRepo) RegisterAttempt(ctx context.Context, scope string, subject string, window time.Duration,
+	lg *zap.Logger) (domain.LoginFailures, error) {
+	lg.Info("postgres login attempt repo: register attempt", zap.String("scope", scope), zap.String("subject", subject))
+
+	var failures domain.LoginFailures
+	query := `insert into login_failures (scope, subject, failures, last_failed_at, previous_failed_at)
+		values ($1, $2, 1, now(), now())
+		on conflict (scope, subject) do update set
+			failures = case when login_failures.last_failed_at > now() - make_interval(secs => $3)
+				then login_failures.failures + 1 else 1 end,
+			previous_failed_at = login_failures.last_failed_at,
+			last_failed_at = now()
+		returning failures - 1, previous_failed_at`
+	err := p.db.QueryRow(ctx, query, scope, subject, window.Seconds()).Scan(&failures.Failures, &failures.LastFailedAt)
+	if err != nil {
+		lg.Warn("postgres login attempt repo: register attempt error", zap.Error(err))
+		return domain.LoginFailures{}, fmt.Errorf("postgres login attempt repo: register attempt error: %v", err.Error())
+	}
+
+	return failures, nil
+}
+
+func (p *PostgresLoginAttemptRepo) ForgiveAttempt(ctx context.Context, scope string, subject string, lg *zap.Logger) error {
+	lg.Info("postgres login attempt repo: forgive attempt", zap.String("scope", scope), zap.String("subject", subject))
+
+	query := `update login_failures set failures = failures - 1, last_failed_at = previous_failed_at
+		where scope = $1 and subject = $2 and failures > 0`
+	_, err := p.db.Exec(ctx, query, scope, subject)
+	if err != nil {
+		lg.Warn("postgres login attempt repo: forgive attempt error", zap.Error(err))
+		return fmt.Errorf("postgres login attempt repo: forgive attempt error: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (p *PostgresLoginAttemptRepo) Reset(ctx context.Context, scope string, subject string, lg *zap.Logger) error {
+	lg.Info("postgres login attempt repo: reset", zap.String("scope", scope), zap.String("subject", subject))
+
+	query := `delete from login_failures where scope = $1 and subject = $2`
+	_, err := p.db.Exec(ctx, query, scope, subject)
+	if err != nil {
+		lg.Warn("postgres login attempt repo: reset error", zap.Error(err))
+		return fmt.Errorf("postgres login attempt repo: reset error: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (p *PostgresLoginAttemptRepo) DeleteStale(ctx context.Context, window time.Duration, lg *zap.Logger) error {
+	lg.Info("postgres login attempt repo: delete stale")
+
+	query := `delete from login_failures where last_failed_at < now() - make_interval(secs => $1)`
+	_, err := p.db.Exec(ctx, query, window.Seconds())
+	if err != nil {
+		lg.Warn("postgres login attempt repo: delete stale error", zap.Error(err))
+		return fmt.Errorf("postgres login attempt repo: delete stale error: %v", err.Error())
+	}
+
+	return nil
+}
@@ -22,7 +22,9 @@ func NewPostgresNotifyRepo(pg IPool, retryAdapter IPostgresRetryAdapter) *Postgr
 func (p *PostgresNotifyRepo) GetNoSendNotifies(ctx context.Context, lg *zap.Logger) ([]domain.Notify, error) {
 	lg.Info("postgres notify repo: get no send notifies")
 
-	query := `select * from new_flats_outbox where status=$1`
+	query := `select id, flat_id, house_id, mail, status, kind,
+		coalesce(decline_reason_code, ''), coalesce(decline_reason_text, '')
+	from new_flats_outbox where status=$1`
 	rows, err := p.db.Query(ctx, query, domain.NoSendedNotifyStatus)
 	defer rows.Close()
 	if err != nil {
@@ -36,7 +38,8 @@ func (p *PostgresNotifyRepo) GetNoSendNotifies(ctx context.Context, lg *zap.Logg
 	)
 
 	for rows.Next() {
-		err = rows.Scan(&notify.ID, &notify.FlatID, &notify.HouseID, &notify.UserMail, &notify.Status)
+		err = rows.Scan(&notify.ID, &notify.FlatID, &notify.HouseID, &notify.UserMail, &notify.Status,
+			&notify.Kind, &notify.DeclineReason.Code, &notify.DeclineReason.Text)
 		if err != nil {
 			lg.Warn("postgres notify repo: get no send notify error: scan notify error")
 			continue
@@ -50,8 +53,8 @@ func (p *PostgresNotifyRepo) GetNoSendNotifies(ctx context.Context, lg *zap.Logg
 func (p *PostgresNotifyRepo) SendNotifyByID(ctx context.Context, id int, lg *zap.Logger) error {
 	lg.Info("postgres notify repo: send notify by id")
 
-	query := `update new_flats_outbox set status=$1`
-	_, err := p.db.Exec(ctx, query, domain.SendedNotifyStatus)
+	query := `update new_flats_outbox set status=$1 where id=$2`
+	_, err := p.db.Exec(ctx, query, domain.SendedNotifyStatus, id)
 	if err != nil {
 		lg.Warn("postgres notify repo: send notify by id error", zap.Error(err))
 		return fmt.Errorf("postgres notify repo: send notify by id error: %v", err.Error())
@@ -0,0 +1,188 @@
+package repo
+
+import (
+	"avito-test-task/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"time"
+)
+
+// revokeFamilyQuery revokes the refresh tokens of family $1 that are still active and
+// deny-lists the access tokens issued with them that have not expired yet.
+const revokeFamilyQuery = `with revoked as (
+		update refresh_tokens set revoked_at = now()
+		where family_id = $1 and revoked_at is null
+		returning access_token_id, access_expires_at
+	)
+	insert into revoked_access_tokens (token_id, expires_at)
+	select access_token_id, access_expires_at from revoked
+	where access_expires_at > now()
+	on conflict (token_id) do nothing`
+
+type PostgresTokenRepo struct {
+	db           IPool
+	retryAdapter IPostgresRetryAdapter
+}
+
+func NewPostgresTokenRepo(db IPool, retryAdapter IPostgresRetryAdapter) *PostgresTokenRepo {
+	return &PostgresTokenRepo{
+		db:           db,
+		retryAdapter: retryAdapter,
+	}
+}
+
+func (p *PostgresTokenRepo) Create(ctx context.Context, token *domain.RefreshToken, lg *zap.Logger) error {
+	lg.Info("postgres token repo: create", zap.String("user_id", token.UserID.String()))
+
+	query := `insert into refresh_tokens(token_id, family_id, user_id, token_hash, expires_at,
+			access_token_id, access_expires_at)
+		values ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := p.db.Exec(ctx, query, token.TokenID, token.FamilyID, token.UserID, token.TokenHash,
+		token.ExpiresAt, token.AccessTokenID, token.AccessExpiresAt)
+	if err != nil {
+		lg.Warn("postgres token repo: create error", zap.Error(err))
+		return fmt.Errorf("postgres token repo: create error: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (p *PostgresTokenRepo) Use(ctx context.Context, tokenHash string, lg *zap.Logger) (domain.RefreshToken, error) {
+	lg.Info("postgres token repo: use")
+
+	var token domain.RefreshToken
+	query := `with used as (
+			update refresh_tokens set used_at = now()
+			where token_hash = $1 and used_at is null and revoked_at is null and expires_at > now()
+			returning token_id, family_id, user_id, expires_at
+		)
+		select t.token_id, t.family_id, t.user_id, t.expires_at, u.role
+		from used t
+		join users u on u.user_id = t.user_id`
+	err := p.db.QueryRow(ctx, query, tokenHash).Scan(&token.TokenID, &token.FamilyID, &token.UserID,
+		&token.ExpiresAt, &token.Role)
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres token repo: use error", zap.Error(err))
+		return domain.RefreshToken{}, fmt.Errorf("postgres token repo: use error: %v", err.Error())
+	}
+
+	// The token could not be used: it is unknown, expired, revoked or was used before.
+	var (
+		familyID uuid.UUID
+		reused   bool
+	)
+	query = `select family_id, used_at is not null and revoked_at is null
+		from refresh_tokens where token_hash = $1`
+	err = p.db.QueryRow(ctx, query, tokenHash).Scan(&familyID, &reused)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !reused) {
+		lg.Warn("postgres token repo: use error: token is not active")
+		return domain.RefreshToken{}, fmt.Errorf("postgres token repo: use error: %w", domain.ErrToken_BadRefresh)
+	}
+	if err != nil {
+		lg.Warn("postgres token repo: use error", zap.Error(err))
+		return domain.RefreshToken{}, fmt.Errorf("postgres token repo: use error: %v", err.Error())
+	}
+
+	lg.Warn("postgres token repo: use error: token reused, revoking family", zap.String("family_id", familyID.String()))
+	_, err = p.db.Exec(ctx, revokeFamilyQuery, familyID)
+	if err != nil {
+		lg.Warn("postgres token repo: use error", zap.Error(err))
+		return domain.RefreshToken{}, fmt.Errorf("postgres token repo: use error: %v", err.Error())
+	}
+
+	return domain.RefreshToken{}, fmt.Errorf("postgres token repo: use error: %w", domain.ErrToken_Reused)
+}
+
+func (p *PostgresTokenRepo) RevokeFamily(ctx context.Context, tokenHash string, userID uuid.UUID, lg *zap.Logger) error {
+	lg.Info("postgres token repo: revoke family", zap.String("user_id", userID.String()))
+
+	var familyID uuid.UUID
+	query := `select family_id from refresh_tokens where token_hash = $1 and user_id = $2`
+	err := p.db.QueryRow(ctx, query, tokenHash, userID).Scan(&familyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		lg.Warn("postgres token repo: revoke family error", zap.Error(err))
+		return fmt.Errorf("postgres token repo: revoke family error: %w", domain.ErrToken_BadRefresh)
+	}
+	if err != nil {
+		lg.Warn("postgres token repo: revoke family error", zap.Error(err))
+		return fmt.Errorf("postgres token repo: revoke family error: %v", err.Error())
+	}
+
+	_, err = p.db.Exec(ctx, revokeFamilyQuery, familyID)
+	if err != nil {
+		lg.Warn("postgres token repo: revoke family error", zap.Error(err))
+		return fmt.Errorf("postgres token repo: revoke family error: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (p *PostgresTokenRepo) RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time, lg *zap.Logger) error {
+	lg.Info("postgres token repo: revoke access token", zap.String("token_id", tokenID))
+
+	query := `insert into revoked_access_tokens (token_id, expires_at) values ($1, $2)
+		on conflict (token_id) do nothing`
+	_, err := p.db.Exec(ctx, query, tokenID, expiresAt)
+	if err != nil {
+		lg.Warn("postgres token repo: revoke access token error", zap.Error(err))
+		return fmt.Errorf("postgres token repo: revoke access token error: %v", err.Error())
+	}
+
+	return nil
+}
+
+func (p *PostgresTokenRepo) GetRevokedAccessTokens(ctx context.Context, since time.Time, lg *zap.Logger) ([]domain.RevokedToken, error) {
+	lg.Info("postgres token repo: get revoked access tokens", zap.Time("since", since))
+
+	query := `select token_id, expires_at, revoked_at from revoked_access_tokens
+		where revoked_at > $1 and expires_at > now()
+		order by revoked_at`
+	rows, err := p.db.Query(ctx, query, since)
+	if err != nil {
+		lg.Warn("postgres token repo: get revoked access tokens error", zap.Error(err))
+		return nil, fmt.Errorf("postgres token repo: get revoked access tokens error: %v", err.Error())
+	}
+	defer rows.Close()
+
+	var (
+		tokens []domain.RevokedToken
+		token  domain.RevokedToken
+	)
+	for rows.Next() {
+		err = rows.Scan(&token.TokenID, &token.ExpiresAt, &token.RevokedAt)
+		if err != nil {
+			lg.Warn("postgres token repo: get revoked access tokens error: scan token error", zap.Error(err))
+			return nil, fmt.Errorf("postgres token repo: get revoked access tokens error: %v", err.Error())
+		}
+		tokens = append(tokens, token)
+	}
+	if err = rows.Err(); err != nil {
+		lg.Warn("postgres token repo: get revoked access tokens error", zap.Error(err))
+		return nil, fmt.Errorf("postgres token repo: get revoked access tokens error: %v", err.Error())
+	}
+
+	return tokens, nil
+}
+
+func (p *PostgresTokenRepo) DeleteExpired(ctx context.Context, lg *zap.Logger) error {
+	lg.Info("postgres token repo: delete expired")
+
+	query := `with expired_refresh as (
+			delete from refresh_tokens where expires_at < now()
+		)
+		delete from revoked_access_tokens where expires_at < now()`
+	_, err := p.db.Exec(ctx, query)
+	if err != nil {
+		lg.Warn("postgres token repo: delete expired error", zap.Error(err))
+		return fmt.Errorf("postgres token repo: delete expired error: %v", err.Error())
+	}
+
+	return nil
+}
@@ -25,6 +25,10 @@ func (p *PostgresUserRepo) Create(ctx context.Context, user *domain.User, lg *za
 
 	query := `insert into users(user_id, mail, password, role) values ($1, $2, $3, $4)`
 	_, err := p.db.Exec(ctx, query, user.UserID, user.Mail, user.Password, user.Role)
+	if isPgError(err, uniqueViolationCode) {
+		lg.Warn("postgres create user error", zap.Error(err))
+		return fmt.Errorf("postgres create user error: %w", domain.ErrUser_MailTaken)
+	}
 	if err != nil {
 		lg.Warn("postgres create user error", zap.Error(err))
 		return err
@@ -79,6 +83,22 @@ func (p *PostgresUserRepo) GetByID(ctx context.Context, id uuid.UUID, lg *zap.Lo
 	return user, nil
 }
 
+func (p *PostgresUserRepo) GetByMail(ctx context.Context, mail string, lg *zap.Logger) (domain.User, error) {
+	var user domain.User
+	lg.Info("get user by mail", zap.String("mail", mail))
+
+	query := `select user_id, mail, password, role from users where lower(mail)=lower($1)`
+	rows := p.db.QueryRow(ctx, query, mail)
+
+	err := rows.Scan(&user.UserID, &user.Mail, &user.Password, &user.Role)
+	if err != nil {
+		lg.Warn("postgres get by mail user error", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
 func (p *PostgresUserRepo) GetAll(ctx context.Context, offset int, limit int, lg *zap.Logger) ([]domain.User, error) {
 	lg.Info("get users", zap.Int("offset", offset), zap.Int("limit", limit))
 
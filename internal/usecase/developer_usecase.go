@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"avito-test-task/internal/domain"
+	"avito-test-task/pkg"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+type DeveloperUsecase struct {
+	developerRepo domain.DeveloperRepo
+	houseRepo     domain.HouseRepo
+}
+
+func NewDeveloperUsecase(developerRepo domain.DeveloperRepo, houseRepo domain.HouseRepo) *DeveloperUsecase {
+	return &DeveloperUsecase{
+		developerRepo: developerRepo,
+		houseRepo:     houseRepo,
+	}
+}
+
+func newDeveloperResponse(developer *domain.Developer) domain.DeveloperResponse {
+	return domain.DeveloperResponse{
+		ID:        developer.DeveloperID,
+		Name:      developer.Name,
+		CreatedAt: developer.CreatedAt.Format(time.DateTime),
+	}
+}
+
+func (u *DeveloperUsecase) Create(ctx context.Context, req *domain.DeveloperRequest, lg *zap.Logger) (domain.DeveloperResponse, error) {
+	lg.Info("developer usecase: create")
+
+	if req == nil {
+		lg.Warn("developer usecase: create error: bad request = nil")
+		return domain.DeveloperResponse{},
+			fmt.Errorf("developer usecase: create error: %w", domain.ErrDeveloper_BadRequest)
+	}
+
+	developer := domain.Developer{Name: strings.TrimSpace(req.Name)}
+	if developer.Name == "" {
+		lg.Warn("developer usecase: create error: empty developer name")
+		return domain.DeveloperResponse{},
+			fmt.Errorf("developer usecase: create error: %w", domain.ErrDeveloper_BadName)
+	}
+
+	developer, err := u.developerRepo.Create(ctx, &developer, lg)
+	if err != nil {
+		lg.Warn("developer usecase: create error", zap.Error(err))
+		return domain.DeveloperResponse{}, fmt.Errorf("developer usecase: create error: %w", err)
+	}
+
+	return newDeveloperResponse(&developer), nil
+}
+
+func (u *DeveloperUsecase) GetByID(ctx context.Context, id int, lg *zap.Logger) (domain.DeveloperResponse, error) {
+	lg.Info("developer usecase: get by id", zap.Int("developer_id", id))
+
+	if id < 1 {
+		lg.Warn("developer usecase: get by id error: bad developer id", zap.Int("developer_id", id))
+		return domain.DeveloperResponse{},
+			fmt.Errorf("developer usecase: get by id error: %w", domain.ErrDeveloper_BadID)
+	}
+
+	developer, err := u.developerRepo.GetByID(ctx, id, lg)
+	if err != nil {
+		lg.Warn("developer usecase: get by id error", zap.Error(err))
+		return domain.DeveloperResponse{}, fmt.Errorf("developer usecase: get by id error: %w", err)
+	}
+
+	return newDeveloperResponse(&developer), nil
+}
+
+func (u *DeveloperUsecase) GetAll(ctx context.Context, cursor string, limit int, lg *zap.Logger) (domain.DevelopersResponse, error) {
+	lg.Info("developer usecase: get all")
+
+	if limit == 0 {
+		limit = domain.DefaultPageLimit
+	}
+	if limit < 0 || limit > domain.MaxPageLimit {
+		lg.Warn("developer usecase: get all error: bad limit", zap.Int("limit", limit))
+		return domain.DevelopersResponse{},
+			fmt.Errorf("developer usecase: get all error: %w", domain.ErrDeveloper_BadLimit)
+	}
+
+	keys, err := pkg.DecodeCursor(cursor, 1)
+	if err != nil {
+		lg.Warn("developer usecase: get all error: bad cursor", zap.String("cursor", cursor))
+		return domain.DevelopersResponse{},
+			fmt.Errorf("developer usecase: get all error: %w", domain.ErrDeveloper_BadCursor)
+	}
+
+	developers, err := u.developerRepo.GetAll(ctx, keys[0], limit+1, lg)
+	if err != nil {
+		lg.Warn("developer usecase: get all error", zap.Error(err))
+		return domain.DevelopersResponse{}, fmt.Errorf("developer usecase: get all error: %w", err)
+	}
+
+	var developersResponse domain.DevelopersResponse
+	if len(developers) > limit {
+		developers = developers[:limit]
+		developersResponse.NextCursor = pkg.EncodeCursor(developers[len(developers)-1].DeveloperID)
+	}
+
+	developersResponse.Developers = make([]domain.DeveloperResponse, 0, len(developers))
+	for _, developer := range developers {
+		developersResponse.Developers = append(developersResponse.Developers, newDeveloperResponse(&developer))
+	}
+
+	return developersResponse, nil
+}
+
+func (u *DeveloperUsecase) Update(ctx context.Context, id int, req *domain.DeveloperRequest, lg *zap.Logger) (domain.DeveloperResponse, error) {
+	lg.Info("developer usecase: update", zap.Int("developer_id", id))
+
+	if req == nil {
+		lg.Warn("developer usecase: update error: bad request = nil")
+		return domain.DeveloperResponse{},
+			fmt.Errorf("developer usecase: update error: %w", domain.ErrDeveloper_BadRequest)
+	}
+
+	if id < 1 {
+		lg.Warn("developer usecase: update error: bad developer id", zap.Int("developer_id", id))
+		return domain.DeveloperResponse{},
+			fmt.Errorf("developer usecase: update error: %w", domain.ErrDeveloper_BadID)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		lg.Warn("developer usecase: update error: empty developer name")
+		return domain.DeveloperResponse{},
+			fmt.Errorf("developer usecase: update error: %w", domain.ErrDeveloper_BadName)
+	}
+
+	developer, err := u.developerRepo.GetByID(ctx, id, lg)
+	if err != nil {
+		lg.Warn("developer usecase: update error", zap.Error(err))
+		return domain.DeveloperResponse{}, fmt.Errorf("developer usecase: update error: %w", err)
+	}
+
+	developer.Name = name
+	err = u.developerRepo.Update(ctx, &developer, lg)
+	if err != nil {
+		lg.Warn("developer usecase: update error", zap.Error(err))
+		return domain.DeveloperResponse{}, fmt.Errorf("developer usecase: update error: %w", err)
+	}
+
+	return newDeveloperResponse(&developer), nil
+}
+
+func (u *DeveloperUsecase) DeleteByID(ctx context.Context, id int, lg *zap.Logger) error {
+	lg.Info("developer usecase: delete by id", zap.Int("developer_id", id))
+
+	if id < 1 {
+		lg.Warn("developer usecase: delete by id error: bad developer id", zap.Int("developer_id", id))
+		return fmt.Errorf("developer usecase: delete by id error: %w", domain.ErrDeveloper_BadID)
+	}
+
+	err := u.developerRepo.DeleteByID(ctx, id, lg)
+	if err != nil {
+		lg.Warn("developer usecase: delete by id error", zap.Error(err))
+		return fmt.Errorf("developer usecase: delete by id error: %w", err)
+	}
+
+	return nil
+}
+
+func (u *DeveloperUsecase) GetHouses(ctx context.Context, id int, cursor string, limit int, lg *zap.Logger) (domain.DeveloperHousesResponse, error) {
+	lg.Info("developer usecase: get houses", zap.Int("developer_id", id))
+
+	if id < 1 {
+		lg.Warn("developer usecase: get houses error: bad developer id", zap.Int("developer_id", id))
+		return domain.DeveloperHousesResponse{},
+			fmt.Errorf("developer usecase: get houses error: %w", domain.ErrDeveloper_BadID)
+	}
+
+	if limit == 0 {
+		limit = domain.DefaultPageLimit
+	}
+	if limit < 0 || limit > domain.MaxPageLimit {
+		lg.Warn("developer usecase: get houses error: bad limit", zap.Int("limit", limit))
+		return domain.DeveloperHousesResponse{},
+			fmt.Errorf("developer usecase: get houses error: %w", domain.ErrDeveloper_BadLimit)
+	}
+
+	keys, err := pkg.DecodeCursor(cursor, 1)
+	if err != nil {
+		lg.Warn("developer usecase: get houses error: bad cursor", zap.String("cursor", cursor))
+		return domain.DeveloperHousesResponse{},
+			fmt.Errorf("developer usecase: get houses error: %w", domain.ErrDeveloper_BadCursor)
+	}
+
+	developer, err := u.developerRepo.GetByID(ctx, id, lg)
+	if err != nil {
+		lg.Warn("developer usecase: get houses error", zap.Error(err))
+		return domain.DeveloperHousesResponse{}, fmt.Errorf("developer usecase: get houses error: %w", err)
+	}
+
+	houses, err := u.houseRepo.GetByDeveloperID(ctx, id, keys[0], limit+1, lg)
+	if err != nil {
+		lg.Warn("developer usecase: get houses error", zap.Error(err))
+		return domain.DeveloperHousesResponse{}, fmt.Errorf("developer usecase: get houses error: %w", err)
+	}
+
+	housesResponse := domain.DeveloperHousesResponse{Developer: newDeveloperResponse(&developer)}
+	if len(houses) > limit {
+		houses = houses[:limit]
+		housesResponse.NextCursor = pkg.EncodeCursor(houses[len(houses)-1].HouseID)
+	}
+
+	housesResponse.Houses = make([]domain.CreateHouseResponse, 0, len(houses))
+	for _, house := range houses {
+		housesResponse.Houses = append(housesResponse.Houses, newHouseResponse(&house))
+	}
+
+	return housesResponse, nil
+}
@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"avito-test-task/internal/domain"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FeedUsecase struct {
+	feedRepo domain.FeedRepo
+}
+
+func NewFeedUsecase(feedRepo domain.FeedRepo, done chan bool, freq time.Duration, timeout time.Duration, lg *zap.Logger) *FeedUsecase {
+	feedUsecase := FeedUsecase{
+		feedRepo: feedRepo,
+	}
+
+	go feedUsecase.Regenerating(done, freq, timeout, lg)
+
+	return &feedUsecase
+}
+
+func validateFeedRequest(req *domain.FeedRequest) error {
+	if req == nil {
+		return domain.ErrFeed_BadFormat
+	}
+
+	if _, ok := feedFormats[req.Format]; !ok {
+		return domain.ErrFeed_BadFormat
+	}
+
+	if req.Scope != domain.FeedScopeHouse && req.Scope != domain.FeedScopeDeveloper {
+		return domain.ErrFeed_BadScope
+	}
+
+	if req.ID < 1 {
+		return domain.ErrFeed_BadID
+	}
+
+	return nil
+}
+
+func (u *FeedUsecase) GetVersion(ctx context.Context, req *domain.FeedRequest, lg *zap.Logger) (time.Time, error) {
+	lg.Info("feed usecase: get version")
+
+	if err := validateFeedRequest(req); err != nil {
+		lg.Warn("feed usecase: get version error: bad feed request", zap.Error(err))
+		return time.Time{}, fmt.Errorf("feed usecase: get version error: %w", err)
+	}
+
+	version, err := u.feedRepo.GetVersion(ctx, req.Scope, req.ID, lg)
+	if err != nil {
+		lg.Warn("feed usecase: get version error", zap.Error(err))
+		return time.Time{}, fmt.Errorf("feed usecase: get version error: %w", err)
+	}
+
+	return version, nil
+}
+
+func (u *FeedUsecase) Stream(ctx context.Context, req *domain.FeedRequest, handle func(chunk string) error, lg *zap.Logger) error {
+	lg.Info("feed usecase: stream")
+
+	if err := validateFeedRequest(req); err != nil {
+		lg.Warn("feed usecase: stream error: bad feed request", zap.Error(err))
+		return fmt.Errorf("feed usecase: stream error: %w", err)
+	}
+	format := feedFormats[req.Format]
+
+	version, err := u.feedRepo.GetVersion(ctx, req.Scope, req.ID, lg)
+	if err != nil {
+		lg.Warn("feed usecase: stream error", zap.Error(err))
+		return fmt.Errorf("feed usecase: stream error: %w", err)
+	}
+
+	if err = handle(format.header(version)); err != nil {
+		lg.Warn("feed usecase: stream error", zap.Error(err))
+		return fmt.Errorf("feed usecase: stream error: %w", err)
+	}
+
+	err = u.feedRepo.StreamOffers(ctx, req.Format, req.Scope, req.ID, handle, lg)
+	if err != nil {
+		lg.Warn("feed usecase: stream error", zap.Error(err))
+		return fmt.Errorf("feed usecase: stream error: %w", err)
+	}
+
+	if err = handle(format.footer); err != nil {
+		lg.Warn("feed usecase: stream error", zap.Error(err))
+		return fmt.Errorf("feed usecase: stream error: %w", err)
+	}
+
+	return nil
+}
+
+// RegenerateOffers renders offers of one batch of changed flats in every format and returns
+// how many flats it took. Flats that are not approved any more or miss a field the format
+// requires are removed from that feed.
+func (u *FeedUsecase) RegenerateOffers(ctx context.Context, lg *zap.Logger) (int, error) {
+	lg.Info("feed usecase: regenerate offers")
+
+	flats, err := u.feedRepo.GetChangedFlats(ctx, domain.FeedChangesBatch, lg)
+	if err != nil {
+		lg.Warn("feed usecase: regenerate offers error", zap.Error(err))
+		return 0, fmt.Errorf("feed usecase: regenerate offers error: %w", err)
+	}
+	if len(flats) == 0 {
+		return 0, nil
+	}
+
+	offers := make([]domain.FeedOffer, 0, len(flats)*len(domain.FeedFormats))
+	for i := range flats {
+		flat := &flats[i]
+		for _, name := range domain.FeedFormats {
+			offer := domain.FeedOffer{FlatID: flat.FlatID, HouseID: flat.HouseID, Format: name}
+			if flat.Exists && flat.Status == domain.ApprovedStatus {
+				offer.Offer, err = feedFormats[name].render(flat)
+				if err != nil {
+					lg.Warn("feed usecase: regenerate offers: flat left out of feed", zap.Int("flat_id", flat.FlatID),
+						zap.Int("house_id", flat.HouseID), zap.String("format", name), zap.Error(err))
+				}
+			}
+			offers = append(offers, offer)
+		}
+	}
+
+	err = u.feedRepo.SaveOffers(ctx, flats, offers, lg)
+	if err != nil {
+		lg.Warn("feed usecase: regenerate offers error", zap.Error(err))
+		return 0, fmt.Errorf("feed usecase: regenerate offers error: %w", err)
+	}
+
+	return len(flats), nil
+}
+
+func (u *FeedUsecase) Regenerating(done chan bool, frequency time.Duration, timeout time.Duration, lg *zap.Logger) {
+	for {
+		select {
+		case <-done:
+			lg.Warn("feed usecase: regenerating goroutine exited")
+			return
+		default:
+			lg.Info("feed usecase: regenerating goroutine working")
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+			regenerated, err := u.RegenerateOffers(ctx, lg)
+			cancel()
+			if err != nil {
+				lg.Warn("feed usecase: regenerating error", zap.Error(err))
+			}
+
+			// A full batch means more flats are queued, so the next one is taken right away.
+			if regenerated < domain.FeedChangesBatch {
+				time.Sleep(frequency)
+			}
+		}
+	}
+}
+
+const feedCountry = "Россия"
+
+// feedFormat renders one classified-ads XML format: offers are stored rendered and
+// wrapped into the header and the footer when a feed is served.
+type feedFormat struct {
+	header func(version time.Time) string
+	footer string
+	render func(flat *domain.FeedFlat) (string, error)
+}
+
+var feedFormats = map[string]feedFormat{
+	domain.FeedFormatYandex: {
+		header: func(version time.Time) string {
+			return xml.Header + `<realty-feed xmlns="http://webmaster.yandex.ru/schemas/feed/realty/2010-06">` + "\n" +
+				"<generation-date>" + version.Format(time.RFC3339) + "</generation-date>\n"
+		},
+		footer: "</realty-feed>\n",
+		render: renderYandexOffer,
+	},
+	domain.FeedFormatAvito: {
+		header: func(version time.Time) string {
+			return xml.Header + `<Ads formatVersion="3" target="Avito.ru">` + "\n"
+		},
+		footer: "</Ads>\n",
+		render: renderAvitoOffer,
+	},
+}
+
+type yandexOffer struct {
+	XMLName      xml.Name         `xml:"offer"`
+	InternalID   string           `xml:"internal-id,attr"`
+	Type         string           `xml:"type"`
+	PropertyType string           `xml:"property-type"`
+	Category     string           `xml:"category"`
+	CreationDate string           `xml:"creation-date"`
+	Location     yandexLocation   `xml:"location"`
+	SalesAgent   yandexSalesAgent `xml:"sales-agent"`
+	Price        yandexPrice      `xml:"price"`
+	Rooms        int              `xml:"rooms"`
+	BuiltYear    int              `xml:"built-year,omitempty"`
+}
+
+type yandexLocation struct {
+	Country   string   `xml:"country"`
+	Address   string   `xml:"address"`
+	Latitude  *float64 `xml:"latitude,omitempty"`
+	Longitude *float64 `xml:"longitude,omitempty"`
+}
+
+type yandexSalesAgent struct {
+	Category     string `xml:"category"`
+	Organization string `xml:"organization,omitempty"`
+}
+
+type yandexPrice struct {
+	Value    int    `xml:"value"`
+	Currency string `xml:"currency"`
+}
+
+type avitoAd struct {
+	XMLName       xml.Name `xml:"Ad"`
+	ID            string   `xml:"Id"`
+	Category      string   `xml:"Category"`
+	OperationType string   `xml:"OperationType"`
+	MarketType    string   `xml:"MarketType"`
+	Address       string   `xml:"Address"`
+	Latitude      *float64 `xml:"Latitude,omitempty"`
+	Longitude     *float64 `xml:"Longitude,omitempty"`
+	Description   string   `xml:"Description"`
+	Price         int      `xml:"Price"`
+	Rooms         int      `xml:"Rooms"`
+	CompanyName   string   `xml:"CompanyName,omitempty"`
+}
+
+// feedField is a field the format requires; an empty value makes the offer invalid.
+type feedField struct {
+	name  string
+	value string
+}
+
+func checkRequiredFeedFields(fields []feedField) error {
+	for _, field := range fields {
+		if strings.TrimSpace(field.value) == "" {
+			return fmt.Errorf("%w: %s", domain.ErrFeed_NoField, field.name)
+		}
+	}
+
+	return nil
+}
+
+// positiveField formats a number that the format requires to be positive; other numbers count as missing.
+func positiveField(number int) string {
+	if number < 1 {
+		return ""
+	}
+
+	return strconv.Itoa(number)
+}
+
+func feedOfferID(flat *domain.FeedFlat) string {
+	return fmt.Sprintf("%d-%d", flat.HouseID, flat.FlatID)
+}
+
+func marshalFeedOffer(offer any) (string, error) {
+	data, err := xml.MarshalIndent(offer, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
+
+// Houses are sold by their developers, so in both formats a flat is offered by the developer of its
+// house as a new build.
+func renderYandexOffer(flat *domain.FeedFlat) (string, error) {
+	offer := yandexOffer{
+		InternalID:   feedOfferID(flat),
+		Type:         "продажа",
+		PropertyType: "жилая",
+		Category:     "квартира",
+		CreationDate: flat.CreatedAt.Format(time.RFC3339),
+		Location: yandexLocation{
+			Country:   feedCountry,
+			Address:   flat.House.Address,
+			Latitude:  flat.House.Latitude,
+			Longitude: flat.House.Longitude,
+		},
+		SalesAgent: yandexSalesAgent{
+			Category:     "застройщик",
+			Organization: flat.House.Developer,
+		},
+		Price:     yandexPrice{Value: flat.Price, Currency: "RUR"},
+		Rooms:     flat.Rooms,
+		BuiltYear: flat.House.ConstructYear,
+	}
+
+	err := checkRequiredFeedFields([]feedField{
+		{"location/address", offer.Location.Address},
+		{"sales-agent/organization", offer.SalesAgent.Organization},
+		{"price/value", positiveField(offer.Price.Value)},
+		{"rooms", positiveField(offer.Rooms)},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return marshalFeedOffer(offer)
+}
+
+func renderAvitoOffer(flat *domain.FeedFlat) (string, error) {
+	ad := avitoAd{
+		ID:            feedOfferID(flat),
+		Category:      "Квартиры",
+		OperationType: "Продам",
+		MarketType:    "Новостройка",
+		Address:       flat.House.Address,
+		Latitude:      flat.House.Latitude,
+		Longitude:     flat.House.Longitude,
+		Description: fmt.Sprintf("Квартира %d, комнат: %d. Дом %d, %s", flat.FlatID, flat.Rooms,
+			flat.HouseID, flat.House.Address),
+		Price:       flat.Price,
+		Rooms:       flat.Rooms,
+		CompanyName: flat.House.Developer,
+	}
+
+	err := checkRequiredFeedFields([]feedField{
+		{"Address", ad.Address},
+		{"Price", positiveField(ad.Price)},
+		{"Rooms", positiveField(ad.Rooms)},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return marshalFeedOffer(ad)
+}
@@ -6,14 +6,27 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"io"
+	"time"
 )
 
 type FlatUsecase struct {
-	flatRepo domain.FlatRepo
+	flatRepo       domain.FlatRepo
+	leaseTTL       time.Duration
+	declineReasons map[string]string
 }
 
-func NewFlatUsecase(flatRepo domain.FlatRepo) *FlatUsecase {
-	return &FlatUsecase{flatRepo: flatRepo}
+func NewFlatUsecase(flatRepo domain.FlatRepo, leaseTTL time.Duration, declineReasons map[string]string,
+	done chan bool, freq time.Duration, timeout time.Duration, lg *zap.Logger) *FlatUsecase {
+	flatUsecase := FlatUsecase{
+		flatRepo:       flatRepo,
+		leaseTTL:       leaseTTL,
+		declineReasons: declineReasons,
+	}
+
+	go flatUsecase.ReclaimingLeases(done, freq, timeout, lg)
+
+	return &flatUsecase
 }
 
 func IsCorrectFlatStatus(status string) bool {
@@ -21,37 +34,37 @@ func IsCorrectFlatStatus(status string) bool {
 		status == domain.DeclinedStatus || status == domain.AnyStatus || status == domain.ModeratingStatus
 }
 
-func (u *FlatUsecase) Create(ctx context.Context, userID uuid.UUID, flatReq *domain.CreateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
-	lg.Info("flat usecase: create")
-
+// validateCreateFlatRequest holds the rules for new flats shared by Create and Import.
+func validateCreateFlatRequest(flatReq *domain.CreateFlatRequest) error {
 	if flatReq == nil {
-		lg.Warn("flat usecase: create error: bad flat request: nil")
-		return domain.CreateFlatResponse{},
-			fmt.Errorf("flat usecase: create error: %w", domain.ErrFlat_BadRequest)
+		return domain.ErrFlat_BadRequest
 	}
 
 	if flatReq.FlatID < 1 {
-		lg.Warn("flat usecase: create error: bad flat id", zap.Int("flat_id", flatReq.FlatID))
-		return domain.CreateFlatResponse{},
-			fmt.Errorf("flat usecase: create error: %w", domain.ErrFlat_BadID)
+		return domain.ErrFlat_BadID
 	}
 
 	if flatReq.HouseID < 1 {
-		lg.Warn("flat usecase: create error: bad house id", zap.Int("house_id", flatReq.HouseID))
-		return domain.CreateFlatResponse{},
-			fmt.Errorf("flat usecase: create error: %w", domain.ErrFlat_BadHouseID)
+		return domain.ErrFlat_BadHouseID
 	}
 
 	if flatReq.Rooms < 1 {
-		lg.Warn("flat usecase: create error: bad rooms", zap.Int("rooms", flatReq.Rooms))
-		return domain.CreateFlatResponse{},
-			fmt.Errorf("flat usecase: create error: %w", domain.ErrFlat_BadRooms)
+		return domain.ErrFlat_BadRooms
 	}
 
 	if flatReq.Price < 0 {
-		lg.Warn("flat usecase: create error: bad price", zap.Int("price", flatReq.Price))
-		return domain.CreateFlatResponse{},
-			fmt.Errorf("flat usecase: create error: %w", domain.ErrFlat_BadPrice)
+		return domain.ErrFlat_BadPrice
+	}
+
+	return nil
+}
+
+func (u *FlatUsecase) Create(ctx context.Context, userID uuid.UUID, flatReq *domain.CreateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	lg.Info("flat usecase: create")
+
+	if err := validateCreateFlatRequest(flatReq); err != nil {
+		lg.Warn("flat usecase: create error: bad flat request", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: create error: %w", err)
 	}
 
 	flat := domain.Flat{
@@ -101,23 +114,51 @@ func (u *FlatUsecase) Update(ctx context.Context, moderatorID uuid.UUID, newFlat
 			fmt.Errorf("flat usecase: update error: %w", domain.ErrFlat_BadHouseID)
 	}
 
-	if newFlatData.Status != domain.AnyStatus && !IsCorrectFlatStatus(newFlatData.Status) {
+	if newFlatData.Status == domain.AnyStatus || !IsCorrectFlatStatus(newFlatData.Status) {
 		lg.Warn("flat usecase: update error: bad status", zap.String("status", newFlatData.Status))
 		return domain.CreateFlatResponse{},
 			fmt.Errorf("flat usecase: update error: %w", domain.ErrFlat_BadStatus)
 	}
 
 	flat := domain.Flat{
-		ID:      newFlatData.ID,
-		HouseID: newFlatData.HouseID,
-		Status:  newFlatData.Status,
+		ID:            newFlatData.ID,
+		HouseID:       newFlatData.HouseID,
+		Status:        newFlatData.Status,
+		StatusComment: newFlatData.Comment,
+	}
+
+	if newFlatData.Status == domain.DeclinedStatus {
+		if err := u.checkDeclineReason(newFlatData.Reason); err != nil {
+			lg.Warn("flat usecase: update error: bad decline reason", zap.Error(err))
+			return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: update error: %w", err)
+		}
+		flat.DeclineReason = *newFlatData.Reason
 	}
 
-	updatedFlat, err := u.flatRepo.Update(ctx, moderatorID, &flat, lg)
+	currentFlat, err := u.flatRepo.GetByID(ctx, flat.ID, flat.HouseID, lg)
 	if err != nil {
 		lg.Warn("flat usecase: update error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: update error: %w", err)
+	}
+
+	if !domain.CanChangeFlatStatus(currentFlat.Status, flat.Status) {
+		lg.Warn("flat usecase: update error: illegal transition",
+			zap.String("current_status", currentFlat.Status), zap.String("status", flat.Status))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: update error: %w",
+			&domain.FlatTransitionError{CurrentStatus: currentFlat.Status, RequestedStatus: flat.Status})
+	}
+
+	if currentFlat.Status == domain.ModeratingStatus && currentFlat.ModeratorID != uuid.Nil &&
+		currentFlat.ModeratorID != moderatorID {
+		lg.Warn("flat usecase: update error: flat is on moderation by another moderator")
 		return domain.CreateFlatResponse{},
-			fmt.Errorf("flat usecase: update error: %v", err.Error())
+			fmt.Errorf("flat usecase: update error: %w", domain.ErrFlat_OnModeration)
+	}
+
+	updatedFlat, err := u.flatRepo.Update(ctx, moderatorID, &flat, currentFlat.Status, u.leaseTTL, lg)
+	if err != nil {
+		lg.Warn("flat usecase: update error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: update error: %w", err)
 	}
 
 	updatedFlatResponse := domain.CreateFlatResponse{
@@ -127,6 +168,658 @@ func (u *FlatUsecase) Update(ctx context.Context, moderatorID uuid.UUID, newFlat
 		Rooms:   updatedFlat.Rooms,
 		Status:  updatedFlat.Status,
 	}
+	if updatedFlat.DeclineReason.Code != "" {
+		updatedFlatResponse.DeclineReason = &updatedFlat.DeclineReason
+	}
 
 	return updatedFlatResponse, nil
 }
+
+func (u *FlatUsecase) checkDeclineReason(reason *domain.DeclineReason) error {
+	if reason == nil || reason.Code == "" {
+		return domain.ErrFlat_NoDeclineReason
+	}
+
+	if _, ok := u.declineReasons[reason.Code]; !ok {
+		return domain.ErrFlat_BadDeclineReason
+	}
+
+	return nil
+}
+
+// newSingleFlatResponse builds a flat listing entry, marking price drops made within RecentPriceDropPeriod.
+func newSingleFlatResponse(flat *domain.Flat) domain.SingleFlatResponse {
+	flatResponse := domain.SingleFlatResponse{
+		ID:      flat.ID,
+		HouseID: flat.HouseID,
+		Price:   flat.Price,
+		Rooms:   flat.Rooms,
+		Status:  flat.Status,
+	}
+	if flat.PreviousPrice > flat.Price && time.Since(flat.PriceDroppedAt) <= domain.RecentPriceDropPeriod {
+		flatResponse.PriceDrop = &domain.PriceDropResponse{
+			PreviousPrice: flat.PreviousPrice,
+			DroppedAt:     flat.PriceDroppedAt,
+		}
+	}
+
+	return flatResponse
+}
+
+func isCorrectSortField(field string) bool {
+	return field == domain.SortByPrice || field == domain.SortByRooms || field == domain.SortByHouse ||
+		field == domain.SortByYear || field == domain.SortByDeveloper
+}
+
+func isCorrectSortOrder(order string) bool {
+	return order == domain.AscOrder || order == domain.DescOrder
+}
+
+func (u *FlatUsecase) Search(ctx context.Context, req *domain.SearchFlatRequest, status string, lg *zap.Logger) (domain.SearchFlatResponse, error) {
+	lg.Info("flat usecase: search")
+
+	if req == nil {
+		lg.Warn("flat usecase: search error: bad search request: nil")
+		return domain.SearchFlatResponse{},
+			fmt.Errorf("flat usecase: search error: %w", domain.ErrFlat_BadRequest)
+	}
+
+	if req.MinPrice < 0 || req.MaxPrice < 0 || (req.MaxPrice > 0 && req.MinPrice > req.MaxPrice) {
+		lg.Warn("flat usecase: search error: bad price range",
+			zap.Int("price_min", req.MinPrice), zap.Int("price_max", req.MaxPrice))
+		return domain.SearchFlatResponse{},
+			fmt.Errorf("flat usecase: search error: %w", domain.ErrFlat_BadPriceRange)
+	}
+
+	if req.MinYear < 0 || req.MaxYear < 0 || (req.MaxYear > 0 && req.MinYear > req.MaxYear) {
+		lg.Warn("flat usecase: search error: bad year range",
+			zap.Int("year_min", req.MinYear), zap.Int("year_max", req.MaxYear))
+		return domain.SearchFlatResponse{},
+			fmt.Errorf("flat usecase: search error: %w", domain.ErrFlat_BadYearRange)
+	}
+
+	if req.Rooms < 0 {
+		lg.Warn("flat usecase: search error: bad rooms", zap.Int("rooms", req.Rooms))
+		return domain.SearchFlatResponse{},
+			fmt.Errorf("flat usecase: search error: %w", domain.ErrFlat_BadRooms)
+	}
+
+	if req.HouseID < 0 {
+		lg.Warn("flat usecase: search error: bad house id", zap.Int("house_id", req.HouseID))
+		return domain.SearchFlatResponse{},
+			fmt.Errorf("flat usecase: search error: %w", domain.ErrFlat_BadHouseID)
+	}
+
+	if req.SortBy == "" {
+		req.SortBy = domain.SortByPrice
+	}
+	if !isCorrectSortField(req.SortBy) {
+		lg.Warn("flat usecase: search error: bad sort field", zap.String("sort", req.SortBy))
+		return domain.SearchFlatResponse{},
+			fmt.Errorf("flat usecase: search error: %w", domain.ErrFlat_BadSortField)
+	}
+
+	if req.Order == "" {
+		req.Order = domain.AscOrder
+	}
+	if !isCorrectSortOrder(req.Order) {
+		lg.Warn("flat usecase: search error: bad sort order", zap.String("order", req.Order))
+		return domain.SearchFlatResponse{},
+			fmt.Errorf("flat usecase: search error: %w", domain.ErrFlat_BadSortOrder)
+	}
+
+	if req.Limit == 0 {
+		req.Limit = domain.DefaultSearchLimit
+	}
+	if req.Limit < 0 || req.Limit > domain.MaxSearchLimit || req.Offset < 0 {
+		lg.Warn("flat usecase: search error: bad limit or offset",
+			zap.Int("limit", req.Limit), zap.Int("offset", req.Offset))
+		return domain.SearchFlatResponse{},
+			fmt.Errorf("flat usecase: search error: %w", domain.ErrFlat_BadLimit)
+	}
+
+	if !IsCorrectFlatStatus(status) {
+		lg.Warn("flat usecase: search error: bad status", zap.String("status", status))
+		return domain.SearchFlatResponse{},
+			fmt.Errorf("flat usecase: search error: %w", domain.ErrFlat_BadStatus)
+	}
+
+	filter := domain.FlatFilter{
+		MinPrice:  req.MinPrice,
+		MaxPrice:  req.MaxPrice,
+		Rooms:     req.Rooms,
+		HouseID:   req.HouseID,
+		Developer: req.Developer,
+		MinYear:   req.MinYear,
+		MaxYear:   req.MaxYear,
+		Status:    status,
+		SortBy:    req.SortBy,
+		Order:     req.Order,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+	}
+
+	flats, err := u.flatRepo.Search(ctx, &filter, lg)
+	if err != nil {
+		lg.Warn("flat usecase: search error", zap.Error(err))
+		return domain.SearchFlatResponse{}, fmt.Errorf("flat usecase: search error: %v", err.Error())
+	}
+
+	searchResponse := domain.SearchFlatResponse{
+		Flats: make([]domain.SingleFlatResponse, 0, len(flats)),
+	}
+	for _, flat := range flats {
+		searchResponse.Flats = append(searchResponse.Flats, newSingleFlatResponse(&flat))
+	}
+
+	return searchResponse, nil
+}
+
+func statusAfterOwnerEdit(status string) string {
+	if status == domain.ApprovedStatus || status == domain.DeclinedStatus {
+		return domain.CreatedStatus
+	}
+
+	return status
+}
+
+func (u *FlatUsecase) Edit(ctx context.Context, userID uuid.UUID, editFlatData *domain.EditFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	lg.Info("flat usecase: edit")
+
+	if editFlatData == nil || (editFlatData.Price == nil && editFlatData.Rooms == nil) {
+		lg.Warn("flat usecase: edit error: nothing to edit")
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: edit error: %w", domain.ErrFlat_BadNewFlat)
+	}
+
+	if editFlatData.ID < 1 {
+		lg.Warn("flat usecase: edit error: bad flat id", zap.Int("flat_id", editFlatData.ID))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: edit error: %w", domain.ErrFlat_BadID)
+	}
+
+	if editFlatData.HouseID < 1 {
+		lg.Warn("flat usecase: edit error: bad house id", zap.Int("house_id", editFlatData.HouseID))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: edit error: %w", domain.ErrFlat_BadHouseID)
+	}
+
+	if editFlatData.Rooms != nil && *editFlatData.Rooms < 1 {
+		lg.Warn("flat usecase: edit error: bad rooms", zap.Int("rooms", *editFlatData.Rooms))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: edit error: %w", domain.ErrFlat_BadRooms)
+	}
+
+	if editFlatData.Price != nil && *editFlatData.Price < 0 {
+		lg.Warn("flat usecase: edit error: bad price", zap.Int("price", *editFlatData.Price))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: edit error: %w", domain.ErrFlat_BadPrice)
+	}
+
+	flat, err := u.flatRepo.GetByID(ctx, editFlatData.ID, editFlatData.HouseID, lg)
+	if err != nil {
+		lg.Warn("flat usecase: edit error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: edit error: %w", err)
+	}
+
+	if flat.UserID != userID {
+		lg.Warn("flat usecase: edit error: not owner", zap.String("user_id", userID.String()))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: edit error: %w", domain.ErrFlat_NotOwner)
+	}
+
+	if flat.Status == domain.ModeratingStatus && flat.ModeratorID != userID {
+		lg.Warn("flat usecase: edit error: flat on moderation",
+			zap.String("moderator_id", flat.ModeratorID.String()))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: edit error: %w", domain.ErrFlat_OnModeration)
+	}
+
+	expectedStatus := flat.Status
+	if editFlatData.Price != nil {
+		flat.Price = *editFlatData.Price
+	}
+	if editFlatData.Rooms != nil {
+		flat.Rooms = *editFlatData.Rooms
+	}
+	flat.Status = statusAfterOwnerEdit(flat.Status)
+
+	editedFlat, err := u.flatRepo.UpdateByOwner(ctx, userID, &flat, expectedStatus, lg)
+	if err != nil {
+		lg.Warn("flat usecase: edit error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: edit error: %w", err)
+	}
+
+	editedFlatResponse := domain.CreateFlatResponse{
+		ID:      editedFlat.ID,
+		HouseID: editedFlat.HouseID,
+		Price:   editedFlat.Price,
+		Rooms:   editedFlat.Rooms,
+		Status:  editedFlat.Status,
+	}
+
+	return editedFlatResponse, nil
+}
+
+func isWithdrawnStatus(status string) bool {
+	return status == domain.ArchivedStatus || status == domain.SoldStatus
+}
+
+func checkFlatManageAccess(flat *domain.Flat, userID uuid.UUID, role string) error {
+	if flat.UserID != userID && role != domain.Moderator {
+		return domain.ErrFlat_NotOwner
+	}
+
+	if flat.Status == domain.ModeratingStatus && flat.ModeratorID != userID {
+		return domain.ErrFlat_OnModeration
+	}
+
+	return nil
+}
+
+func (u *FlatUsecase) Withdraw(ctx context.Context, userID uuid.UUID, role string, withdrawData *domain.UpdateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	lg.Info("flat usecase: withdraw")
+
+	if withdrawData == nil {
+		lg.Warn("flat usecase: withdraw error: bad withdraw request = nil")
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: withdraw error: %w", domain.ErrFlat_BadNewFlat)
+	}
+
+	if withdrawData.ID < 1 {
+		lg.Warn("flat usecase: withdraw error: bad flat id", zap.Int("flat_id", withdrawData.ID))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: withdraw error: %w", domain.ErrFlat_BadID)
+	}
+
+	if withdrawData.HouseID < 1 {
+		lg.Warn("flat usecase: withdraw error: bad house id", zap.Int("house_id", withdrawData.HouseID))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: withdraw error: %w", domain.ErrFlat_BadHouseID)
+	}
+
+	if withdrawData.Status == "" {
+		withdrawData.Status = domain.ArchivedStatus
+	}
+	if !isWithdrawnStatus(withdrawData.Status) {
+		lg.Warn("flat usecase: withdraw error: bad status", zap.String("status", withdrawData.Status))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: withdraw error: %w", domain.ErrFlat_BadStatus)
+	}
+
+	flat, err := u.flatRepo.GetByID(ctx, withdrawData.ID, withdrawData.HouseID, lg)
+	if err != nil {
+		lg.Warn("flat usecase: withdraw error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: withdraw error: %w", err)
+	}
+
+	if err = checkFlatManageAccess(&flat, userID, role); err != nil {
+		lg.Warn("flat usecase: withdraw error: no access", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: withdraw error: %w", err)
+	}
+
+	if isWithdrawnStatus(flat.Status) {
+		lg.Warn("flat usecase: withdraw error: already withdrawn", zap.String("status", flat.Status))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: withdraw error: %w", domain.ErrFlat_Withdrawn)
+	}
+
+	expectedStatus := flat.Status
+	flat.Status = withdrawData.Status
+	flat.StatusComment = withdrawData.Comment
+
+	withdrawnFlat, err := u.flatRepo.UpdateStatus(ctx, userID, &flat, expectedStatus, lg)
+	if err != nil {
+		lg.Warn("flat usecase: withdraw error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: withdraw error: %w", err)
+	}
+
+	withdrawnFlatResponse := domain.CreateFlatResponse{
+		ID:      withdrawnFlat.ID,
+		HouseID: withdrawnFlat.HouseID,
+		Price:   withdrawnFlat.Price,
+		Rooms:   withdrawnFlat.Rooms,
+		Status:  withdrawnFlat.Status,
+	}
+
+	return withdrawnFlatResponse, nil
+}
+
+func (u *FlatUsecase) Restore(ctx context.Context, userID uuid.UUID, role string, restoreData *domain.UpdateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	lg.Info("flat usecase: restore")
+
+	if restoreData == nil {
+		lg.Warn("flat usecase: restore error: bad restore request = nil")
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: restore error: %w", domain.ErrFlat_BadNewFlat)
+	}
+
+	if restoreData.ID < 1 {
+		lg.Warn("flat usecase: restore error: bad flat id", zap.Int("flat_id", restoreData.ID))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: restore error: %w", domain.ErrFlat_BadID)
+	}
+
+	if restoreData.HouseID < 1 {
+		lg.Warn("flat usecase: restore error: bad house id", zap.Int("house_id", restoreData.HouseID))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: restore error: %w", domain.ErrFlat_BadHouseID)
+	}
+
+	flat, err := u.flatRepo.GetByID(ctx, restoreData.ID, restoreData.HouseID, lg)
+	if err != nil {
+		lg.Warn("flat usecase: restore error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: restore error: %w", err)
+	}
+
+	if err = checkFlatManageAccess(&flat, userID, role); err != nil {
+		lg.Warn("flat usecase: restore error: no access", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: restore error: %w", err)
+	}
+
+	if !isWithdrawnStatus(flat.Status) {
+		lg.Warn("flat usecase: restore error: not withdrawn", zap.String("status", flat.Status))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: restore error: %w", domain.ErrFlat_NotWithdrawn)
+	}
+
+	expectedStatus := flat.Status
+	flat.Status = domain.CreatedStatus
+	flat.StatusComment = restoreData.Comment
+
+	restoredFlat, err := u.flatRepo.UpdateStatus(ctx, userID, &flat, expectedStatus, lg)
+	if err != nil {
+		lg.Warn("flat usecase: restore error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: restore error: %w", err)
+	}
+
+	restoredFlatResponse := domain.CreateFlatResponse{
+		ID:      restoredFlat.ID,
+		HouseID: restoredFlat.HouseID,
+		Price:   restoredFlat.Price,
+		Rooms:   restoredFlat.Rooms,
+		Status:  restoredFlat.Status,
+	}
+
+	return restoredFlatResponse, nil
+}
+
+func (u *FlatUsecase) ClaimNext(ctx context.Context, moderatorID uuid.UUID, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	lg.Info("flat usecase: claim next")
+
+	if moderatorID == uuid.Nil {
+		lg.Warn("flat usecase: claim next error: bad moderator id")
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: claim next error: %w", domain.ErrUser_BadId)
+	}
+
+	claimedFlat, err := u.flatRepo.ClaimNext(ctx, moderatorID, u.leaseTTL, lg)
+	if err != nil {
+		lg.Warn("flat usecase: claim next error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: claim next error: %w", err)
+	}
+
+	claimedFlatResponse := domain.CreateFlatResponse{
+		ID:             claimedFlat.ID,
+		HouseID:        claimedFlat.HouseID,
+		Price:          claimedFlat.Price,
+		Rooms:          claimedFlat.Rooms,
+		Status:         claimedFlat.Status,
+		LeaseExpiresAt: &claimedFlat.LeaseExpiresAt,
+	}
+
+	return claimedFlatResponse, nil
+}
+
+func (u *FlatUsecase) ExtendLease(ctx context.Context, moderatorID uuid.UUID, leaseData *domain.UpdateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	lg.Info("flat usecase: extend lease")
+
+	if leaseData == nil {
+		lg.Warn("flat usecase: extend lease error: bad lease request = nil")
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: extend lease error: %w", domain.ErrFlat_BadNewFlat)
+	}
+
+	if leaseData.ID < 1 {
+		lg.Warn("flat usecase: extend lease error: bad flat id", zap.Int("flat_id", leaseData.ID))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: extend lease error: %w", domain.ErrFlat_BadID)
+	}
+
+	if leaseData.HouseID < 1 {
+		lg.Warn("flat usecase: extend lease error: bad house id", zap.Int("house_id", leaseData.HouseID))
+		return domain.CreateFlatResponse{},
+			fmt.Errorf("flat usecase: extend lease error: %w", domain.ErrFlat_BadHouseID)
+	}
+
+	flat := domain.Flat{
+		ID:      leaseData.ID,
+		HouseID: leaseData.HouseID,
+	}
+
+	extendedFlat, err := u.flatRepo.ExtendLease(ctx, moderatorID, &flat, u.leaseTTL, lg)
+	if err != nil {
+		lg.Warn("flat usecase: extend lease error", zap.Error(err))
+		return domain.CreateFlatResponse{}, fmt.Errorf("flat usecase: extend lease error: %w", err)
+	}
+
+	extendedFlatResponse := domain.CreateFlatResponse{
+		ID:             extendedFlat.ID,
+		HouseID:        extendedFlat.HouseID,
+		Price:          extendedFlat.Price,
+		Rooms:          extendedFlat.Rooms,
+		Status:         extendedFlat.Status,
+		LeaseExpiresAt: &extendedFlat.LeaseExpiresAt,
+	}
+
+	return extendedFlatResponse, nil
+}
+
+func (u *FlatUsecase) ReclaimingLeases(done chan bool, frequency time.Duration, timeout time.Duration, lg *zap.Logger) {
+	for {
+		select {
+		case <-done:
+			lg.Warn("flat usecase: reclaiming goroutine exited")
+			return
+		default:
+			lg.Info("flat usecase: reclaiming goroutine working")
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+			reclaimed, err := u.flatRepo.ReclaimExpiredLeases(ctx, lg)
+			cancel()
+			if err != nil {
+				lg.Warn("flat usecase: reclaiming error", zap.Error(err))
+			} else if reclaimed > 0 {
+				lg.Info("flat usecase: returned flats with expired leases to queue", zap.Int64("count", reclaimed))
+			}
+
+			time.Sleep(frequency)
+		}
+	}
+}
+
+func (u *FlatUsecase) GetStatusHistory(ctx context.Context, userID uuid.UUID, role string, flatID int, houseID int, lg *zap.Logger) (domain.FlatStatusHistoryResponse, error) {
+	lg.Info("flat usecase: get status history")
+
+	if flatID < 1 {
+		lg.Warn("flat usecase: get status history error: bad flat id", zap.Int("flat_id", flatID))
+		return domain.FlatStatusHistoryResponse{},
+			fmt.Errorf("flat usecase: get status history error: %w", domain.ErrFlat_BadID)
+	}
+
+	if houseID < 1 {
+		lg.Warn("flat usecase: get status history error: bad house id", zap.Int("house_id", houseID))
+		return domain.FlatStatusHistoryResponse{},
+			fmt.Errorf("flat usecase: get status history error: %w", domain.ErrFlat_BadHouseID)
+	}
+
+	flat, err := u.flatRepo.GetByID(ctx, flatID, houseID, lg)
+	if err != nil {
+		lg.Warn("flat usecase: get status history error", zap.Error(err))
+		return domain.FlatStatusHistoryResponse{}, fmt.Errorf("flat usecase: get status history error: %w", err)
+	}
+
+	if role != domain.Moderator && flat.UserID != userID {
+		lg.Warn("flat usecase: get status history error: not owner")
+		return domain.FlatStatusHistoryResponse{},
+			fmt.Errorf("flat usecase: get status history error: %w", domain.ErrFlat_NotOwner)
+	}
+
+	history, err := u.flatRepo.GetStatusHistory(ctx, flatID, houseID, lg)
+	if err != nil {
+		lg.Warn("flat usecase: get status history error", zap.Error(err))
+		return domain.FlatStatusHistoryResponse{}, fmt.Errorf("flat usecase: get status history error: %w", err)
+	}
+
+	historyResponse := domain.FlatStatusHistoryResponse{
+		History: make([]domain.FlatStatusChangeResponse, 0, len(history)),
+	}
+	for _, change := range history {
+		changeResponse := domain.FlatStatusChangeResponse{
+			OldStatus: change.OldStatus,
+			NewStatus: change.NewStatus,
+			ActorID:   change.ActorID,
+			ChangedAt: change.ChangedAt,
+			Comment:   change.Comment,
+		}
+		if change.DeclineReason.Code != "" {
+			reason := change.DeclineReason
+			changeResponse.DeclineReason = &reason
+		}
+		historyResponse.History = append(historyResponse.History, changeResponse)
+	}
+
+	return historyResponse, nil
+}
+
+func (u *FlatUsecase) GetPriceHistory(ctx context.Context, userID uuid.UUID, role string, flatID int, houseID int, lg *zap.Logger) (domain.FlatPriceHistoryResponse, error) {
+	lg.Info("flat usecase: get price history")
+
+	if flatID < 1 {
+		lg.Warn("flat usecase: get price history error: bad flat id", zap.Int("flat_id", flatID))
+		return domain.FlatPriceHistoryResponse{},
+			fmt.Errorf("flat usecase: get price history error: %w", domain.ErrFlat_BadID)
+	}
+
+	if houseID < 1 {
+		lg.Warn("flat usecase: get price history error: bad house id", zap.Int("house_id", houseID))
+		return domain.FlatPriceHistoryResponse{},
+			fmt.Errorf("flat usecase: get price history error: %w", domain.ErrFlat_BadHouseID)
+	}
+
+	flat, err := u.flatRepo.GetByID(ctx, flatID, houseID, lg)
+	if err != nil {
+		lg.Warn("flat usecase: get price history error", zap.Error(err))
+		return domain.FlatPriceHistoryResponse{}, fmt.Errorf("flat usecase: get price history error: %w", err)
+	}
+
+	// Like flat listings, only approved flats are public; the rest are seen by owners and moderators.
+	if flat.Status != domain.ApprovedStatus && role != domain.Moderator && flat.UserID != userID {
+		lg.Warn("flat usecase: get price history error: flat is not visible", zap.String("status", flat.Status))
+		return domain.FlatPriceHistoryResponse{},
+			fmt.Errorf("flat usecase: get price history error: %w", domain.ErrFlat_NotFound)
+	}
+
+	history, err := u.flatRepo.GetPriceHistory(ctx, flatID, houseID, lg)
+	if err != nil {
+		lg.Warn("flat usecase: get price history error", zap.Error(err))
+		return domain.FlatPriceHistoryResponse{}, fmt.Errorf("flat usecase: get price history error: %w", err)
+	}
+
+	historyResponse := domain.FlatPriceHistoryResponse{
+		ID:      flat.ID,
+		HouseID: flat.HouseID,
+		History: make([]domain.FlatPriceChangeResponse, 0, len(history)),
+	}
+	for _, change := range history {
+		historyResponse.History = append(historyResponse.History, domain.FlatPriceChangeResponse{
+			Price:     change.Price,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+
+	return historyResponse, nil
+}
+
+func (u *FlatUsecase) Import(ctx context.Context, userID uuid.UUID, format string, file io.Reader, lg *zap.Logger) (domain.ImportResponse, error) {
+	lg.Info("flat usecase: import", zap.String("format", format))
+
+	var (
+		flats []domain.Flat
+		lines []int
+	)
+	report := domain.ImportResponse{Errors: []domain.ImportRowError{}}
+	seen := make(map[domain.FlatKey]bool)
+	err := readImportRows(format, file, flatImportColumns, func(row importRow, parseErr error) error {
+		report.Total++
+		if parseErr != nil {
+			addImportError(&report, row.line, parseErr)
+			return nil
+		}
+
+		flatReq, err := flatRequestFromRow(row)
+		if err == nil {
+			err = validateCreateFlatRequest(&flatReq)
+		}
+		key := domain.FlatKey{FlatID: flatReq.FlatID, HouseID: flatReq.HouseID}
+		if err == nil && seen[key] {
+			err = domain.ErrImport_DuplicateRow
+		}
+		if err != nil {
+			addImportError(&report, row.line, err)
+			return nil
+		}
+
+		seen[key] = true
+		flats = append(flats, domain.Flat{
+			ID:      flatReq.FlatID,
+			HouseID: flatReq.HouseID,
+			UserID:  userID,
+			Price:   flatReq.Price,
+			Rooms:   flatReq.Rooms,
+			Status:  domain.CreatedStatus,
+		})
+		lines = append(lines, row.line)
+		return nil
+	})
+	if err != nil {
+		lg.Warn("flat usecase: import error", zap.Error(err))
+		return domain.ImportResponse{}, fmt.Errorf("flat usecase: import error: %w", err)
+	}
+
+	if len(flats) == 0 {
+		return report, nil
+	}
+
+	conflicts, err := u.flatRepo.FindImportConflicts(ctx, flats, lg)
+	if err != nil {
+		lg.Warn("flat usecase: import error", zap.Error(err))
+		return domain.ImportResponse{}, fmt.Errorf("flat usecase: import error: %w", err)
+	}
+
+	validFlats := make([]domain.Flat, 0, len(flats))
+	for i, flat := range flats {
+		switch {
+		case conflicts.MissingHouses[flat.HouseID]:
+			addImportError(&report, lines[i], domain.ErrHouse_NotFound)
+		case conflicts.ExistingFlats[domain.FlatKey{FlatID: flat.ID, HouseID: flat.HouseID}]:
+			addImportError(&report, lines[i], domain.ErrFlat_Exists)
+		default:
+			validFlats = append(validFlats, flat)
+		}
+	}
+	sortImportErrors(&report)
+
+	if len(validFlats) == 0 {
+		return report, nil
+	}
+
+	copied, err := u.flatRepo.CopyFlats(ctx, validFlats, lg)
+	if err != nil {
+		lg.Warn("flat usecase: import error", zap.Error(err))
+		return domain.ImportResponse{}, fmt.Errorf("flat usecase: import error: %w", err)
+	}
+	report.Imported = int(copied)
+
+	return report, nil
+}
@@ -2,26 +2,33 @@ package usecase
 
 import (
 	"avito-test-task/internal/domain"
+	"avito-test-task/pkg"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"io"
+	"math"
+	"strings"
 	"sync"
 	"time"
 )
 
 type HouseUsecase struct {
-	houseRepo    domain.HouseRepo
-	notifySender domain.NotifySender
-	notifyRepo   domain.NotifyRepo
+	houseRepo     domain.HouseRepo
+	developerRepo domain.DeveloperRepo
+	notifySender  domain.NotifySender
+	notifyRepo    domain.NotifyRepo
 }
 
-func NewHouseUsecase(houseRepo domain.HouseRepo, notifySender domain.NotifySender,
+func NewHouseUsecase(houseRepo domain.HouseRepo, developerRepo domain.DeveloperRepo, notifySender domain.NotifySender,
 	notifyRepo domain.NotifyRepo, done chan bool, freq time.Duration, timeout time.Duration, lg *zap.Logger) *HouseUsecase {
 	houseUsecase := HouseUsecase{
-		houseRepo:    houseRepo,
-		notifySender: notifySender,
-		notifyRepo:   notifyRepo,
+		houseRepo:     houseRepo,
+		developerRepo: developerRepo,
+		notifySender:  notifySender,
+		notifyRepo:    notifyRepo,
 	}
 
 	go houseUsecase.Notifying(done, freq, timeout, lg)
@@ -29,31 +36,84 @@ func NewHouseUsecase(houseRepo domain.HouseRepo, notifySender domain.NotifySende
 	return &houseUsecase
 }
 
-func (u *HouseUsecase) Create(ctx context.Context, req *domain.CreateHouseRequest, lg *zap.Logger) (domain.CreateHouseResponse, error) {
-	lg.Info("house usecase: create")
+func newHouseResponse(house *domain.House) domain.CreateHouseResponse {
+	return domain.CreateHouseResponse{
+		HomeID:      house.HouseID,
+		Address:     house.Address,
+		Year:        house.ConstructYear,
+		DeveloperID: house.DeveloperID,
+		Developer:   house.Developer,
+		CreatedAt:   house.CreateHouseDate.Format(time.DateTime),
+		UpdateAt:    house.UpdateFlatDate.Format(time.DateTime),
+		Latitude:    house.Latitude,
+		Longitude:   house.Longitude,
+	}
+}
+
+// resolveDeveloper finds the developer by id or, for the deprecated developer name, by name
+// creating it if needed.
+func (u *HouseUsecase) resolveDeveloper(ctx context.Context, developerID int, name string, lg *zap.Logger) (domain.Developer, error) {
+	if developerID > 0 {
+		return u.developerRepo.GetByID(ctx, developerID, lg)
+	}
+
+	return u.developerRepo.GetOrCreateByName(ctx, strings.TrimSpace(name), lg)
+}
 
+func isCorrectPoint(lat float64, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+// isCorrectCoordinates accepts a house either without coordinates or with both of them in range.
+func isCorrectCoordinates(lat *float64, lon *float64) bool {
+	if lat == nil || lon == nil {
+		return lat == nil && lon == nil
+	}
+
+	return isCorrectPoint(*lat, *lon)
+}
+
+// validateCreateHouseRequest holds the rules for new houses shared by Create and Import.
+func validateCreateHouseRequest(req *domain.CreateHouseRequest) error {
 	if req == nil {
-		lg.Warn("house usecase: create error: bad request = nil")
-		return domain.CreateHouseResponse{},
-			fmt.Errorf("house usecase: create error: %w", domain.ErrHouse_BadRequest)
+		return domain.ErrHouse_BadRequest
 	}
 
 	if req.Year < 0 {
-		lg.Warn("house usecase: create error: bad house year", zap.Int("year", req.Year))
-		return domain.CreateHouseResponse{},
-			fmt.Errorf("house usecase: create error: %w", domain.ErrHouse_BadYear)
+		return domain.ErrHouse_BadYear
 	}
 
-	if req.Developer == "" {
-		lg.Warn("house usecase: create error: bad house developer", zap.String("developer", req.Developer))
-		return domain.CreateHouseResponse{},
-			fmt.Errorf("house usecase: create error: %w", domain.ErrHouse_BadDeveloper)
+	if req.DeveloperID < 0 {
+		return domain.ErrDeveloper_BadID
+	}
+
+	if req.DeveloperID == 0 && strings.TrimSpace(req.Developer) == "" {
+		return domain.ErrHouse_BadDeveloper
 	}
 
 	if req.Address == "" {
-		lg.Warn("house usecase: create error: bad house address", zap.String("address", req.Address))
-		return domain.CreateHouseResponse{},
-			fmt.Errorf("house usecase: create error: %w", domain.ErrHouse_BadAddress)
+		return domain.ErrHouse_BadAddress
+	}
+
+	if !isCorrectCoordinates(req.Latitude, req.Longitude) {
+		return domain.ErrHouse_BadCoords
+	}
+
+	return nil
+}
+
+func (u *HouseUsecase) Create(ctx context.Context, req *domain.CreateHouseRequest, lg *zap.Logger) (domain.CreateHouseResponse, error) {
+	lg.Info("house usecase: create")
+
+	if err := validateCreateHouseRequest(req); err != nil {
+		lg.Warn("house usecase: create error: bad house request", zap.Error(err))
+		return domain.CreateHouseResponse{}, fmt.Errorf("house usecase: create error: %w", err)
+	}
+
+	developer, err := u.resolveDeveloper(ctx, req.DeveloperID, req.Developer, lg)
+	if err != nil {
+		lg.Warn("house usecase: create error", zap.Error(err))
+		return domain.CreateHouseResponse{}, fmt.Errorf("house usecase: create error: %w", err)
 	}
 
 	date := time.Now()
@@ -62,27 +122,486 @@ func (u *HouseUsecase) Create(ctx context.Context, req *domain.CreateHouseReques
 		HouseID:         req.HomeID,
 		Address:         req.Address,
 		ConstructYear:   req.Year,
-		Developer:       req.Developer,
+		Developer:       developer.Name,
+		DeveloperID:     developer.DeveloperID,
 		CreateHouseDate: date,
 		UpdateFlatDate:  date,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
 	}
 
-	house, err := u.houseRepo.Create(ctx, &house, lg)
+	house, err = u.houseRepo.Create(ctx, &house, lg)
 	if err != nil {
 		lg.Warn("user usecase: create error", zap.Error(err))
 		return domain.CreateHouseResponse{}, fmt.Errorf("user usecase: create error: %v", err.Error())
 	}
 
-	houseResponse := domain.CreateHouseResponse{
-		HomeID:    house.HouseID,
-		Address:   house.Address,
-		Year:      house.ConstructYear,
-		Developer: house.Developer,
-		CreatedAt: house.CreateHouseDate.Format(time.DateTime),
-		UpdateAt:  house.UpdateFlatDate.Format(time.DateTime),
+	return newHouseResponse(&house), nil
+}
+
+func (u *HouseUsecase) Import(ctx context.Context, format string, file io.Reader, lg *zap.Logger) (domain.ImportResponse, error) {
+	lg.Info("house usecase: import", zap.String("format", format))
+
+	var (
+		houses []domain.House
+		lines  []int
+	)
+	report := domain.ImportResponse{Errors: []domain.ImportRowError{}}
+	developersByID := make(map[int]domain.Developer)
+	developersByName := make(map[string]domain.Developer)
+	date := time.Now()
+	err := readImportRows(format, file, houseImportColumns, func(row importRow, parseErr error) error {
+		report.Total++
+		if parseErr != nil {
+			addImportError(&report, row.line, parseErr)
+			return nil
+		}
+
+		req, err := houseRequestFromRow(row)
+		if err == nil {
+			err = validateCreateHouseRequest(&req)
+		}
+		if err != nil {
+			addImportError(&report, row.line, err)
+			return nil
+		}
+
+		name := strings.TrimSpace(req.Developer)
+		developer, ok := developersByID[req.DeveloperID]
+		if req.DeveloperID == 0 {
+			developer, ok = developersByName[name]
+		}
+		if !ok {
+			developer, err = u.resolveDeveloper(ctx, req.DeveloperID, name, lg)
+			if errors.Is(err, domain.ErrDeveloper_NotFound) {
+				addImportError(&report, row.line, err)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			developersByID[developer.DeveloperID] = developer
+			if req.DeveloperID == 0 {
+				developersByName[name] = developer
+			}
+		}
+
+		houses = append(houses, domain.House{
+			Address:         req.Address,
+			ConstructYear:   req.Year,
+			Developer:       developer.Name,
+			DeveloperID:     developer.DeveloperID,
+			CreateHouseDate: date,
+			UpdateFlatDate:  date,
+			Latitude:        req.Latitude,
+			Longitude:       req.Longitude,
+		})
+		lines = append(lines, row.line)
+		return nil
+	})
+	if err != nil {
+		lg.Warn("house usecase: import error", zap.Error(err))
+		return domain.ImportResponse{}, fmt.Errorf("house usecase: import error: %w", err)
+	}
+
+	if len(houses) == 0 {
+		return report, nil
 	}
 
-	return houseResponse, nil
+	houses, err = u.houseRepo.CopyHouses(ctx, houses, lg)
+	if err != nil {
+		lg.Warn("house usecase: import error", zap.Error(err))
+		return domain.ImportResponse{}, fmt.Errorf("house usecase: import error: %w", err)
+	}
+
+	report.Imported = len(houses)
+	report.Houses = make([]domain.ImportedHouse, 0, len(houses))
+	for i, house := range houses {
+		report.Houses = append(report.Houses, domain.ImportedHouse{Line: lines[i], ID: house.HouseID})
+	}
+
+	return report, nil
+}
+
+func (u *HouseUsecase) Update(ctx context.Context, id int, req *domain.UpdateHouseRequest, lg *zap.Logger) (domain.CreateHouseResponse, error) {
+	lg.Info("house usecase: update")
+
+	if req == nil || (req.Address == nil && req.Year == nil && req.DeveloperID == nil && req.Developer == nil &&
+		req.Latitude == nil && req.Longitude == nil) {
+		lg.Warn("house usecase: update error: bad request = nil or empty")
+		return domain.CreateHouseResponse{},
+			fmt.Errorf("house usecase: update error: %w", domain.ErrHouse_BadRequest)
+	}
+
+	if id < 1 {
+		lg.Warn("house usecase: update error: bad house id", zap.Int("id", id))
+		return domain.CreateHouseResponse{},
+			fmt.Errorf("house usecase: update error: %w", domain.ErrHouse_BadID)
+	}
+
+	if req.Year != nil && *req.Year < 0 {
+		lg.Warn("house usecase: update error: bad house year", zap.Int("year", *req.Year))
+		return domain.CreateHouseResponse{},
+			fmt.Errorf("house usecase: update error: %w", domain.ErrHouse_BadYear)
+	}
+
+	if req.DeveloperID != nil && *req.DeveloperID < 1 {
+		lg.Warn("house usecase: update error: bad developer id", zap.Int("developer_id", *req.DeveloperID))
+		return domain.CreateHouseResponse{},
+			fmt.Errorf("house usecase: update error: %w", domain.ErrDeveloper_BadID)
+	}
+
+	if req.Developer != nil && strings.TrimSpace(*req.Developer) == "" {
+		lg.Warn("house usecase: update error: bad house developer")
+		return domain.CreateHouseResponse{},
+			fmt.Errorf("house usecase: update error: %w", domain.ErrHouse_BadDeveloper)
+	}
+
+	if req.Address != nil && *req.Address == "" {
+		lg.Warn("house usecase: update error: bad house address")
+		return domain.CreateHouseResponse{},
+			fmt.Errorf("house usecase: update error: %w", domain.ErrHouse_BadAddress)
+	}
+
+	house, err := u.houseRepo.GetByID(ctx, id, lg)
+	if err != nil {
+		lg.Warn("house usecase: update error", zap.Error(err))
+		return domain.CreateHouseResponse{}, fmt.Errorf("house usecase: update error: %w", err)
+	}
+
+	if req.Address != nil {
+		house.Address = *req.Address
+	}
+	if req.Year != nil {
+		house.ConstructYear = *req.Year
+	}
+	if req.DeveloperID != nil || req.Developer != nil {
+		developerID, name := 0, ""
+		if req.DeveloperID != nil {
+			developerID = *req.DeveloperID
+		}
+		if req.Developer != nil {
+			name = *req.Developer
+		}
+
+		developer, err := u.resolveDeveloper(ctx, developerID, name, lg)
+		if err != nil {
+			lg.Warn("house usecase: update error", zap.Error(err))
+			return domain.CreateHouseResponse{}, fmt.Errorf("house usecase: update error: %w", err)
+		}
+		house.Developer = developer.Name
+		house.DeveloperID = developer.DeveloperID
+	}
+	if req.Latitude != nil {
+		house.Latitude = req.Latitude
+	}
+	if req.Longitude != nil {
+		house.Longitude = req.Longitude
+	}
+
+	if !isCorrectCoordinates(house.Latitude, house.Longitude) {
+		lg.Warn("house usecase: update error: bad house coordinates")
+		return domain.CreateHouseResponse{},
+			fmt.Errorf("house usecase: update error: %w", domain.ErrHouse_BadCoords)
+	}
+
+	err = u.houseRepo.Update(ctx, &house, lg)
+	if err != nil {
+		lg.Warn("house usecase: update error", zap.Error(err))
+		return domain.CreateHouseResponse{}, fmt.Errorf("house usecase: update error: %w", err)
+	}
+
+	return newHouseResponse(&house), nil
+}
+
+func isCorrectHouseSortField(field string) bool {
+	return field == domain.HouseSortByCreated || field == domain.HouseSortByUpdated
+}
+
+func houseSortDate(house *domain.House, sortBy string) time.Time {
+	if sortBy == domain.HouseSortByUpdated {
+		return house.UpdateFlatDate
+	}
+
+	return house.CreateHouseDate
+}
+
+// houseCursorKeys binds a houses page cursor to the sort it was issued for,
+// so a cursor from one ordering is not replayed against another.
+func houseCursorKeys(sortBy string, order string) (int, int) {
+	sortKey, orderKey := 0, 0
+	if sortBy == domain.HouseSortByUpdated {
+		sortKey = 1
+	}
+	if order == domain.DescOrder {
+		orderKey = 1
+	}
+
+	return sortKey, orderKey
+}
+
+func (u *HouseUsecase) List(ctx context.Context, req *domain.ListHousesRequest, lg *zap.Logger) (domain.ListHousesResponse, error) {
+	lg.Info("house usecase: list")
+
+	if req == nil {
+		lg.Warn("house usecase: list error: bad request = nil")
+		return domain.ListHousesResponse{},
+			fmt.Errorf("house usecase: list error: %w", domain.ErrHouse_BadRequest)
+	}
+
+	if req.MinYear < 0 || req.MaxYear < 0 || (req.MaxYear > 0 && req.MinYear > req.MaxYear) {
+		lg.Warn("house usecase: list error: bad year range",
+			zap.Int("year_min", req.MinYear), zap.Int("year_max", req.MaxYear))
+		return domain.ListHousesResponse{},
+			fmt.Errorf("house usecase: list error: %w", domain.ErrHouse_BadYearRange)
+	}
+
+	if req.SortBy == "" {
+		req.SortBy = domain.HouseSortByCreated
+	}
+	if !isCorrectHouseSortField(req.SortBy) {
+		lg.Warn("house usecase: list error: bad sort field", zap.String("sort", req.SortBy))
+		return domain.ListHousesResponse{},
+			fmt.Errorf("house usecase: list error: %w", domain.ErrHouse_BadSortField)
+	}
+
+	if req.Order == "" {
+		req.Order = domain.AscOrder
+	}
+	if !isCorrectSortOrder(req.Order) {
+		lg.Warn("house usecase: list error: bad sort order", zap.String("order", req.Order))
+		return domain.ListHousesResponse{},
+			fmt.Errorf("house usecase: list error: %w", domain.ErrHouse_BadSortOrder)
+	}
+
+	if req.Limit == 0 {
+		req.Limit = domain.DefaultPageLimit
+	}
+	if req.Limit < 0 || req.Limit > domain.MaxPageLimit {
+		lg.Warn("house usecase: list error: bad limit", zap.Int("limit", req.Limit))
+		return domain.ListHousesResponse{},
+			fmt.Errorf("house usecase: list error: %w", domain.ErrHouse_BadLimit)
+	}
+
+	sortKey, orderKey := houseCursorKeys(req.SortBy, req.Order)
+	keys, err := pkg.DecodeCursor(req.Cursor, 4)
+	if err != nil || (req.Cursor != "" && (keys[2] != sortKey || keys[3] != orderKey)) {
+		lg.Warn("house usecase: list error: bad cursor", zap.String("cursor", req.Cursor))
+		return domain.ListHousesResponse{},
+			fmt.Errorf("house usecase: list error: %w", domain.ErrHouse_BadCursor)
+	}
+
+	filter := domain.HouseFilter{
+		Developer: req.Developer,
+		Address:   req.Address,
+		MinYear:   req.MinYear,
+		MaxYear:   req.MaxYear,
+		SortBy:    req.SortBy,
+		Order:     req.Order,
+		After: domain.HouseCursor{
+			Date:    time.UnixMicro(int64(keys[0])).UTC(),
+			HouseID: keys[1],
+		},
+		Limit: req.Limit + 1,
+	}
+
+	houses, err := u.houseRepo.Search(ctx, &filter, lg)
+	if err != nil {
+		lg.Warn("house usecase: list error", zap.Error(err))
+		return domain.ListHousesResponse{}, fmt.Errorf("house usecase: list error: %w", err)
+	}
+
+	var housesResponse domain.ListHousesResponse
+	if len(houses) > req.Limit {
+		houses = houses[:req.Limit]
+		lastHouse := houses[len(houses)-1]
+		housesResponse.NextCursor = pkg.EncodeCursor(int(houseSortDate(&lastHouse, req.SortBy).UnixMicro()),
+			lastHouse.HouseID, sortKey, orderKey)
+	}
+
+	housesResponse.Houses = make([]domain.CreateHouseResponse, 0, len(houses))
+	for _, house := range houses {
+		housesResponse.Houses = append(housesResponse.Houses, newHouseResponse(&house))
+	}
+
+	return housesResponse, nil
+}
+
+func (u *HouseUsecase) SearchByAddress(ctx context.Context, query string, limit int, lg *zap.Logger) (domain.SearchHousesResponse, error) {
+	lg.Info("house usecase: search by address", zap.String("query", query))
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		lg.Warn("house usecase: search by address error: empty query")
+		return domain.SearchHousesResponse{},
+			fmt.Errorf("house usecase: search by address error: %w", domain.ErrHouse_BadQuery)
+	}
+
+	if limit == 0 {
+		limit = domain.DefaultSearchLimit
+	}
+	if limit < 0 || limit > domain.MaxSearchLimit {
+		lg.Warn("house usecase: search by address error: bad limit", zap.Int("limit", limit))
+		return domain.SearchHousesResponse{},
+			fmt.Errorf("house usecase: search by address error: %w", domain.ErrHouse_BadLimit)
+	}
+
+	altQuery := query
+	if pkg.HasLatin(query) {
+		altQuery = pkg.TransliterateToCyrillic(query)
+	}
+
+	matches, err := u.houseRepo.SearchByAddress(ctx, query, altQuery, limit, lg)
+	if err != nil {
+		lg.Warn("house usecase: search by address error", zap.Error(err))
+		return domain.SearchHousesResponse{}, fmt.Errorf("house usecase: search by address error: %w", err)
+	}
+
+	searchResponse := domain.SearchHousesResponse{
+		Houses: make([]domain.HouseMatchResponse, 0, len(matches)),
+	}
+	for _, match := range matches {
+		searchResponse.Houses = append(searchResponse.Houses, domain.HouseMatchResponse{
+			CreateHouseResponse: newHouseResponse(&match.House),
+			Score:               match.Score,
+		})
+	}
+
+	return searchResponse, nil
+}
+
+func (u *HouseUsecase) GetNearby(ctx context.Context, req *domain.NearbyHousesRequest, lg *zap.Logger) (domain.NearbyHousesResponse, error) {
+	lg.Info("house usecase: get nearby")
+
+	if req == nil {
+		lg.Warn("house usecase: get nearby error: bad request = nil")
+		return domain.NearbyHousesResponse{},
+			fmt.Errorf("house usecase: get nearby error: %w", domain.ErrHouse_BadRequest)
+	}
+
+	if !isCorrectPoint(req.Latitude, req.Longitude) {
+		lg.Warn("house usecase: get nearby error: bad coordinates",
+			zap.Float64("lat", req.Latitude), zap.Float64("lon", req.Longitude))
+		return domain.NearbyHousesResponse{},
+			fmt.Errorf("house usecase: get nearby error: %w", domain.ErrHouse_BadCoords)
+	}
+
+	if req.RadiusKm <= 0 || req.RadiusKm > domain.MaxNearbyRadiusKm {
+		lg.Warn("house usecase: get nearby error: bad radius", zap.Float64("radius_km", req.RadiusKm))
+		return domain.NearbyHousesResponse{},
+			fmt.Errorf("house usecase: get nearby error: %w", domain.ErrHouse_BadRadius)
+	}
+
+	if req.Limit == 0 {
+		req.Limit = domain.DefaultSearchLimit
+	}
+	if req.Limit < 0 || req.Limit > domain.MaxSearchLimit {
+		lg.Warn("house usecase: get nearby error: bad limit", zap.Int("limit", req.Limit))
+		return domain.NearbyHousesResponse{},
+			fmt.Errorf("house usecase: get nearby error: %w", domain.ErrHouse_BadLimit)
+	}
+
+	center := domain.GeoPoint{Latitude: req.Latitude, Longitude: req.Longitude}
+	houses, err := u.houseRepo.GetNearby(ctx, center, req.RadiusKm, req.Limit, lg)
+	if err != nil {
+		lg.Warn("house usecase: get nearby error", zap.Error(err))
+		return domain.NearbyHousesResponse{}, fmt.Errorf("house usecase: get nearby error: %w", err)
+	}
+
+	nearbyResponse := domain.NearbyHousesResponse{
+		Houses: make([]domain.NearbyHouseResponse, 0, len(houses)),
+	}
+	for _, house := range houses {
+		nearbyResponse.Houses = append(nearbyResponse.Houses, domain.NearbyHouseResponse{
+			CreateHouseResponse: newHouseResponse(&house.House),
+			DistanceKm:          math.Round(house.DistanceKm*1000) / 1000,
+		})
+	}
+
+	return nearbyResponse, nil
+}
+
+func (u *HouseUsecase) ExportGeoJSON(ctx context.Context, lg *zap.Logger) (domain.GeoJSONFeatureCollection, error) {
+	lg.Info("house usecase: export geojson")
+
+	houses, err := u.houseRepo.GetWithApprovedFlats(ctx, lg)
+	if err != nil {
+		lg.Warn("house usecase: export geojson error", zap.Error(err))
+		return domain.GeoJSONFeatureCollection{}, fmt.Errorf("house usecase: export geojson error: %w", err)
+	}
+
+	collection := domain.GeoJSONFeatureCollection{
+		Type:     domain.GeoJSONFeatureCollectionType,
+		Features: make([]domain.GeoJSONFeature, 0, len(houses)),
+	}
+	for _, house := range houses {
+		if house.House.Latitude == nil || house.House.Longitude == nil {
+			continue
+		}
+
+		collection.Features = append(collection.Features, domain.GeoJSONFeature{
+			Type: domain.GeoJSONFeatureType,
+			Geometry: domain.GeoJSONPoint{
+				Type:        domain.GeoJSONPointType,
+				Coordinates: [2]float64{*house.House.Longitude, *house.House.Latitude},
+			},
+			Properties: domain.HouseFeatureProperties{
+				ID:            house.House.HouseID,
+				Address:       house.House.Address,
+				Year:          house.House.ConstructYear,
+				Developer:     house.House.Developer,
+				ApprovedFlats: house.ApprovedFlats,
+			},
+		})
+	}
+
+	return collection, nil
+}
+
+func (u *HouseUsecase) GetStats(ctx context.Context, id int, status string, lg *zap.Logger) (domain.HouseStatsResponse, error) {
+	lg.Info("house usecase: get stats", zap.Int("house_id", id))
+
+	if id < 1 {
+		lg.Warn("house usecase: get stats error: bad house id", zap.Int("house_id", id))
+		return domain.HouseStatsResponse{},
+			fmt.Errorf("house usecase: get stats error: %w", domain.ErrHouse_BadID)
+	}
+
+	_, err := u.houseRepo.GetByID(ctx, id, lg)
+	if err != nil {
+		lg.Warn("house usecase: get stats error", zap.Error(err))
+		return domain.HouseStatsResponse{}, fmt.Errorf("house usecase: get stats error: %w", err)
+	}
+
+	stats, err := u.houseRepo.GetStats(ctx, id, status, lg)
+	if err != nil {
+		lg.Warn("house usecase: get stats error", zap.Error(err))
+		return domain.HouseStatsResponse{}, fmt.Errorf("house usecase: get stats error: %w", err)
+	}
+
+	// Statuses without flats are reported with zero so the response always has the same keys.
+	visibleStatuses := domain.FlatStatuses
+	if status != domain.AnyStatus {
+		visibleStatuses = []string{status}
+	}
+	byStatus := make(map[string]int, len(visibleStatuses))
+	for _, visibleStatus := range visibleStatuses {
+		byStatus[visibleStatus] = stats.StatusCounts[visibleStatus]
+	}
+
+	rooms := stats.RoomCounts
+	if rooms == nil {
+		rooms = map[int]int{}
+	}
+
+	return domain.HouseStatsResponse{
+		HouseID:      id,
+		Total:        stats.Total,
+		ByStatus:     byStatus,
+		Price:        stats.Price,
+		PricePerRoom: stats.PricePerRoom,
+		Rooms:        rooms,
+	}, nil
 }
 
 func parallelFlatFilter(flats []domain.Flat, lg *zap.Logger) domain.FlatsByHouseResponse {
@@ -106,13 +625,7 @@ func parallelFlatFilter(flats []domain.Flat, lg *zap.Logger) domain.FlatsByHouse
 		go func(n int, part []domain.Flat, wg *sync.WaitGroup) {
 			defer wg.Done()
 			for j := 0; j < len(part); j++ {
-				singleFlat := domain.SingleFlatResponse{
-					ID:      parts[n][j].ID,
-					HouseID: parts[n][j].HouseID,
-					Price:   parts[n][j].Price,
-					Rooms:   parts[n][j].Rooms,
-					Status:  parts[n][j].Status,
-				}
+				singleFlat := newSingleFlatResponse(&parts[n][j])
 				mtx.Lock()
 				flatsArr = append(flatsArr, singleFlat)
 				mtx.Unlock()
@@ -120,7 +633,7 @@ func parallelFlatFilter(flats []domain.Flat, lg *zap.Logger) domain.FlatsByHouse
 		}(i, parts[i], &wg)
 	}
 	wg.Wait()
-	return domain.FlatsByHouseResponse{flatsArr}
+	return domain.FlatsByHouseResponse{Flats: flatsArr}
 }
 
 func usualFlatFilter(flats []domain.Flat) domain.FlatsByHouseResponse {
@@ -128,20 +641,14 @@ func usualFlatFilter(flats []domain.Flat) domain.FlatsByHouseResponse {
 		flatsArr []domain.SingleFlatResponse
 	)
 	for _, flat := range flats {
-		singleFlat := domain.SingleFlatResponse{
-			ID:      flat.ID,
-			HouseID: flat.HouseID,
-			Price:   flat.Price,
-			Rooms:   flat.Rooms,
-			Status:  flat.Status,
-		}
+		singleFlat := newSingleFlatResponse(&flat)
 		flatsArr = append(flatsArr, singleFlat)
 	}
 
-	return domain.FlatsByHouseResponse{flatsArr}
+	return domain.FlatsByHouseResponse{Flats: flatsArr}
 }
 
-func (u *HouseUsecase) GetFlatsByHouseID(ctx context.Context, id int, status string, lg *zap.Logger) (domain.FlatsByHouseResponse, error) {
+func (u *HouseUsecase) GetFlatsByHouseID(ctx context.Context, id int, status string, cursor string, limit int, lg *zap.Logger) (domain.FlatsByHouseResponse, error) {
 	if id < 0 {
 		lg.Warn("house usecase: get flats by house id error: bad id", zap.Int("house_id", id))
 		return domain.FlatsByHouseResponse{},
@@ -154,22 +661,70 @@ func (u *HouseUsecase) GetFlatsByHouseID(ctx context.Context, id int, status str
 			fmt.Errorf("house usecase: get flats by house id error: %w", domain.ErrFlat_BadStatus)
 	}
 
-	flats, err := u.houseRepo.GetFlatsByHouseID(ctx, id, status, lg)
+	if limit == 0 {
+		limit = domain.DefaultPageLimit
+	}
+	if limit < 0 || limit > domain.MaxPageLimit {
+		lg.Warn("house usecase: get flats by house id error: bad limit", zap.Int("limit", limit))
+		return domain.FlatsByHouseResponse{},
+			fmt.Errorf("house usecase: get flats by house id error: %w", domain.ErrFlat_BadLimit)
+	}
+
+	keys, err := pkg.DecodeCursor(cursor, 2)
+	if err != nil || (cursor != "" && keys[0] != id) {
+		lg.Warn("house usecase: get flats by house id error: bad cursor", zap.String("cursor", cursor))
+		return domain.FlatsByHouseResponse{},
+			fmt.Errorf("house usecase: get flats by house id error: %w", domain.ErrFlat_BadCursor)
+	}
+
+	flats, err := u.houseRepo.GetFlatsByHouseID(ctx, id, status, keys[1], limit+1, lg)
 	if err != nil {
 		lg.Warn("house usecase: get flats by house id error", zap.Error(err))
 		return domain.FlatsByHouseResponse{}, fmt.Errorf("house usecase: get flats by house id error: %v", err.Error())
 	}
 
+	var nextCursor string
+	if len(flats) > limit {
+		flats = flats[:limit]
+		lastFlat := flats[len(flats)-1]
+		nextCursor = pkg.EncodeCursor(lastFlat.HouseID, lastFlat.ID)
+	}
+
 	var flatsResponse domain.FlatsByHouseResponse
 	if len(flats) < domain.FlatThreshhold {
 		flatsResponse = usualFlatFilter(flats)
 	} else {
 		flatsResponse = parallelFlatFilter(flats, lg)
 	}
+	flatsResponse.NextCursor = nextCursor
 
 	return flatsResponse, nil
 }
 
+func (u *HouseUsecase) StreamFlatsByHouseID(ctx context.Context, id int, status string, handle func(flat domain.SingleFlatResponse) error, lg *zap.Logger) error {
+	lg.Info("house usecase: stream flats by house id")
+
+	if id < 0 {
+		lg.Warn("house usecase: stream flats by house id error: bad id", zap.Int("house_id", id))
+		return fmt.Errorf("house usecase: stream flats by house id error: %w", domain.ErrHouse_BadID)
+	}
+
+	if !IsCorrectFlatStatus(status) {
+		lg.Warn("house usecase: stream flats by house id error: bad status", zap.String("status", status))
+		return fmt.Errorf("house usecase: stream flats by house id error: %w", domain.ErrFlat_BadStatus)
+	}
+
+	err := u.houseRepo.StreamFlatsByHouseID(ctx, id, status, func(flat domain.Flat) error {
+		return handle(newSingleFlatResponse(&flat))
+	}, lg)
+	if err != nil {
+		lg.Warn("house usecase: stream flats by house id error", zap.Error(err))
+		return fmt.Errorf("house usecase: stream flats by house id error: %v", err.Error())
+	}
+
+	return nil
+}
+
 func (uc *HouseUsecase) SubscribeByID(ctx context.Context, id int, userID uuid.UUID, lg *zap.Logger) error {
 	lg.Info("house usecase: subscribe by id")
 
@@ -192,6 +747,19 @@ func (uc *HouseUsecase) SubscribeByID(ctx context.Context, id int, userID uuid.U
 	return nil
 }
 
+func notifyMessage(notify *domain.Notify) string {
+	if notify.Kind == domain.FlatDeclinedNotifyKind {
+		msg := fmt.Sprintf("Your flat with number %d in house %d was declined: %s", notify.FlatID,
+			notify.HouseID, notify.DeclineReason.Code)
+		if notify.DeclineReason.Text != "" {
+			msg += " (" + notify.DeclineReason.Text + ")"
+		}
+		return msg
+	}
+
+	return fmt.Sprintf("New flat with number %d in house %d!", notify.FlatID, notify.HouseID)
+}
+
 func (uc *HouseUsecase) Notifying(done chan bool, frequency time.Duration, timeout time.Duration, lg *zap.Logger) {
 	for {
 		select {
@@ -209,7 +777,7 @@ func (uc *HouseUsecase) Notifying(done chan bool, frequency time.Duration, timeo
 			}
 
 			for _, notify := range notifies {
-				msg := fmt.Sprintf("New flat with number %d in house %d!", notify.FlatID, notify.HouseID)
+				msg := notifyMessage(&notify)
 				err = uc.notifySender.SendEmail(ctx, notify.UserMail, msg)
 				if err != nil {
 					lg.Warn("house usecase: notifying error: send email error", zap.Error(err))
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"avito-test-task/internal/domain"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const maxImportLineSize = 1 << 20
+
+var (
+	flatImportColumns  = []string{"flat_id", "house_id", "price", "rooms"}
+	houseImportColumns = []string{"address"}
+)
+
+// csvRecord is a CSV data row keyed by the header columns.
+type csvRecord map[string]string
+
+func (r csvRecord) intField(name string) (int, error) {
+	value := strings.TrimSpace(r[name])
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
+
+func (r csvRecord) floatField(name string) (*float64, error) {
+	value := strings.TrimSpace(r[name])
+	if value == "" {
+		return nil, nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &number, nil
+}
+
+// importRow is a data row of an import file: JSONL rows keep the raw object, CSV rows the named columns.
+type importRow struct {
+	line int
+	json []byte
+	csv  csvRecord
+}
+
+// readImportRows calls handle for every data row of a CSV or JSONL file. A CSV file must start with
+// a header that has all of the required columns. CSV rows that can't be parsed, such as rows with
+// a wrong number of fields or a stray quote, are passed to handle with parseErr set; an error
+// returned by handle stops reading.
+func readImportRows(format string, file io.Reader, required []string,
+	handle func(row importRow, parseErr error) error) error {
+	switch format {
+	case domain.ImportFormatCSV:
+		return readCSVRows(file, required, handle)
+	case domain.ImportFormatJSONL:
+		return readJSONLRows(file, handle)
+	}
+
+	return domain.ErrImport_BadFormat
+}
+
+func readCSVRows(file io.Reader, required []string, handle func(row importRow, parseErr error) error) error {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	var csvErr *csv.ParseError
+	if errors.As(err, &csvErr) {
+		return fmt.Errorf("%w: %v", domain.ErrImport_BadHeader, err)
+	}
+	if err != nil {
+		return fmt.Errorf("read csv header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	for _, column := range required {
+		if !contains(header, column) {
+			return fmt.Errorf("%w: %s", domain.ErrImport_BadHeader, column)
+		}
+	}
+	reader.FieldsPerRecord = len(header)
+
+	for rows := 0; ; rows++ {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if rows == domain.MaxImportRows {
+			return domain.ErrImport_TooManyRows
+		}
+		var (
+			line     int
+			parseErr error
+		)
+		if errors.As(err, &csvErr) {
+			line = csvErr.StartLine
+			parseErr = fmt.Errorf("%w: %v", domain.ErrImport_BadRow, err)
+		} else if err != nil {
+			return fmt.Errorf("read csv row: %w", err)
+		} else {
+			line, _ = reader.FieldPos(0)
+		}
+
+		record := make(csvRecord, len(header))
+		for i, field := range fields {
+			if i < len(header) {
+				record[header[i]] = field
+			}
+		}
+		if err = handle(importRow{line: line, csv: record}, parseErr); err != nil {
+			return err
+		}
+	}
+}
+
+func readJSONLRows(file io.Reader, handle func(row importRow, parseErr error) error) error {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxImportLineSize)
+
+	rows := 0
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if rows == domain.MaxImportRows {
+			return domain.ErrImport_TooManyRows
+		}
+		rows++
+
+		row := importRow{line: line, json: append([]byte(nil), data...)}
+		if err := handle(row, nil); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read jsonl row: %w", err)
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func flatRequestFromRow(row importRow) (domain.CreateFlatRequest, error) {
+	var req domain.CreateFlatRequest
+	if row.csv == nil {
+		if err := json.Unmarshal(row.json, &req); err != nil {
+			return domain.CreateFlatRequest{}, fmt.Errorf("%w: %v", domain.ErrImport_BadRow, err)
+		}
+		return req, nil
+	}
+
+	fields := []*int{&req.FlatID, &req.HouseID, &req.Price, &req.Rooms}
+	for i, column := range flatImportColumns {
+		value, err := row.csv.intField(column)
+		if err != nil {
+			return domain.CreateFlatRequest{}, fmt.Errorf("%w: %s: %v", domain.ErrImport_BadRow, column, err)
+		}
+		*fields[i] = value
+	}
+
+	return req, nil
+}
+
+func houseRequestFromRow(row importRow) (domain.CreateHouseRequest, error) {
+	var (
+		req domain.CreateHouseRequest
+		err error
+	)
+	if row.csv == nil {
+		if err = json.Unmarshal(row.json, &req); err != nil {
+			return domain.CreateHouseRequest{}, fmt.Errorf("%w: %v", domain.ErrImport_BadRow, err)
+		}
+		return req, nil
+	}
+
+	req.Address = row.csv["address"]
+	req.Developer = row.csv["developer"]
+	if req.Year, err = row.csv.intField("year"); err != nil {
+		return domain.CreateHouseRequest{}, fmt.Errorf("%w: year: %v", domain.ErrImport_BadRow, err)
+	}
+	if req.DeveloperID, err = row.csv.intField("developer_id"); err != nil {
+		return domain.CreateHouseRequest{}, fmt.Errorf("%w: developer_id: %v", domain.ErrImport_BadRow, err)
+	}
+	if req.Latitude, err = row.csv.floatField("latitude"); err != nil {
+		return domain.CreateHouseRequest{}, fmt.Errorf("%w: latitude: %v", domain.ErrImport_BadRow, err)
+	}
+	if req.Longitude, err = row.csv.floatField("longitude"); err != nil {
+		return domain.CreateHouseRequest{}, fmt.Errorf("%w: longitude: %v", domain.ErrImport_BadRow, err)
+	}
+
+	return req, nil
+}
+
+func addImportError(report *domain.ImportResponse, line int, err error) {
+	report.Errors = append(report.Errors, domain.ImportRowError{Line: line, Error: err.Error()})
+}
+
+func sortImportErrors(report *domain.ImportResponse) {
+	sort.Slice(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"avito-test-task/internal/domain"
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+// LoginThrottle slows down password guessing by the failures counted in Postgres, so that all
+// replicas see the same counters. A nil LoginThrottle lets every attempt through.
+type LoginThrottle struct {
+	loginAttemptRepo domain.LoginAttemptRepo
+	policy           domain.LoginPolicy
+}
+
+func NewLoginThrottle(loginAttemptRepo domain.LoginAttemptRepo, policy domain.LoginPolicy, done chan bool,
+	freq time.Duration, timeout time.Duration, lg *zap.Logger) *LoginThrottle {
+	throttle := LoginThrottle{
+		loginAttemptRepo: loginAttemptRepo,
+		policy:           policy,
+	}
+
+	go throttle.Cleaning(done, freq, timeout, lg)
+
+	return &throttle
+}
+
+// window keeps failures at least as long as a lockout lasts.
+func (t *LoginThrottle) window() time.Duration {
+	return max(t.policy.Window, t.policy.LockDuration)
+}
+
+// delay returns how long after the last failure the next attempt is refused and whether this is a lockout.
+func (t *LoginThrottle) delay(scope string, failures int) (time.Duration, bool) {
+	lockAttempts := t.policy.AccountLockAttempts
+	if scope == domain.LoginScopeIP {
+		lockAttempts = t.policy.IPLockAttempts
+	}
+	if lockAttempts > 0 && failures >= lockAttempts {
+		return t.policy.LockDuration, true
+	}
+
+	if failures <= t.policy.FreeAttempts {
+		return 0, false
+	}
+	delay := t.policy.BaseDelay
+	for i := t.policy.FreeAttempts + 1; i < failures && delay < t.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, t.policy.MaxDelay), false
+}
+
+// Attempt counts the attempt as a failure before the password is checked, so that concurrent
+// guesses see each other, and refuses it while the subject has to wait after the failures before it.
+func (t *LoginThrottle) Attempt(ctx context.Context, scope string, subject string, lg *zap.Logger) error {
+	if t == nil || subject == "" {
+		return nil
+	}
+
+	failures, err := t.loginAttemptRepo.RegisterAttempt(ctx, scope, subject, t.window(), lg)
+	if err != nil {
+		return err
+	}
+
+	delay, locked := t.delay(scope, failures.Failures)
+	retryAfter := time.Until(failures.LastFailedAt.Add(delay))
+	if retryAfter <= 0 {
+		return nil
+	}
+
+	return &domain.LoginBlockedError{Scope: scope, Locked: locked, RetryAfter: retryAfter}
+}
+
+// Forgive takes back the failure counted by Attempt once the attempt has succeeded.
+func (t *LoginThrottle) Forgive(ctx context.Context, scope string, subject string, lg *zap.Logger) error {
+	if t == nil || subject == "" {
+		return nil
+	}
+
+	return t.loginAttemptRepo.ForgiveAttempt(ctx, scope, subject, lg)
+}
+
+func (t *LoginThrottle) Reset(ctx context.Context, scope string, subject string, lg *zap.Logger) error {
+	if t == nil || subject == "" {
+		return nil
+	}
+
+	return t.loginAttemptRepo.Reset(ctx, scope, subject, lg)
+}
+
+func (t *LoginThrottle) Cleaning(done chan bool, frequency time.Duration, timeout time.Duration, lg *zap.Logger) {
+	for {
+		select {
+		case <-done:
+			lg.Warn("login throttle: cleaning goroutine exited")
+			return
+		default:
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+			err := t.loginAttemptRepo.DeleteStale(ctx, t.window(), lg)
+			if err != nil {
+				lg.Warn("login throttle: cleaning error", zap.Error(err))
+			}
+			cancel()
+
+			time.Sleep(frequency)
+		}
+	}
+}
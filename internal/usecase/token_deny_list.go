@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"avito-test-task/internal/domain"
+	"avito-test-task/pkg"
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	// denyListSyncOverlap re-reads revocations that were committed out of order around the last sync.
+	denyListSyncOverlap = time.Minute
+	tokenCleanupPeriod  = time.Hour
+)
+
+// TokenDenyList keeps revoked access tokens in memory, so that checking a token costs no query.
+// Revocations made by other replicas are loaded from Postgres every sync. A token is kept for
+// pkg.Leeway after it expires, as long as it is still accepted.
+type TokenDenyList struct {
+	tokenRepo   domain.TokenRepo
+	mu          sync.RWMutex
+	revoked     map[string]time.Time
+	syncedUntil time.Time
+	cleanedAt   time.Time
+}
+
+func NewTokenDenyList(tokenRepo domain.TokenRepo, done chan bool, freq time.Duration, timeout time.Duration, lg *zap.Logger) *TokenDenyList {
+	denyList := TokenDenyList{
+		tokenRepo: tokenRepo,
+		revoked:   make(map[string]time.Time),
+	}
+
+	go denyList.Syncing(done, freq, timeout, lg)
+
+	return &denyList
+}
+
+func (l *TokenDenyList) IsRevoked(tokenID string) bool {
+	l.mu.RLock()
+	expiresAt, ok := l.revoked[tokenID]
+	l.mu.RUnlock()
+
+	return ok && time.Now().Before(expiresAt.Add(pkg.Leeway))
+}
+
+func (l *TokenDenyList) Revoke(tokenID string, expiresAt time.Time) {
+	l.mu.Lock()
+	l.revoked[tokenID] = expiresAt
+	l.mu.Unlock()
+}
+
+// Sync loads the tokens revoked since the last sync and forgets the expired ones.
+func (l *TokenDenyList) Sync(ctx context.Context, lg *zap.Logger) error {
+	l.mu.RLock()
+	since := l.syncedUntil
+	l.mu.RUnlock()
+	if !since.IsZero() {
+		since = since.Add(-denyListSyncOverlap)
+	}
+
+	tokens, err := l.tokenRepo.GetRevokedAccessTokens(ctx, since, lg)
+	if err != nil {
+		lg.Warn("token deny list: sync error", zap.Error(err))
+		return fmt.Errorf("token deny list: sync error: %w", err)
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, token := range tokens {
+		l.revoked[token.TokenID] = token.ExpiresAt
+		if token.RevokedAt.After(l.syncedUntil) {
+			l.syncedUntil = token.RevokedAt
+		}
+	}
+	for tokenID, expiresAt := range l.revoked {
+		if !now.Before(expiresAt.Add(pkg.Leeway)) {
+			delete(l.revoked, tokenID)
+		}
+	}
+
+	return nil
+}
+
+func (l *TokenDenyList) Syncing(done chan bool, frequency time.Duration, timeout time.Duration, lg *zap.Logger) {
+	for {
+		select {
+		case <-done:
+			lg.Warn("token deny list: syncing goroutine exited")
+			return
+		default:
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+			err := l.Sync(ctx, lg)
+			if err != nil {
+				lg.Warn("token deny list: syncing error", zap.Error(err))
+			}
+
+			if time.Since(l.cleanedAt) > tokenCleanupPeriod {
+				err = l.tokenRepo.DeleteExpired(ctx, lg)
+				if err != nil {
+					lg.Warn("token deny list: cleanup error", zap.Error(err))
+				} else {
+					l.cleanedAt = time.Now()
+				}
+			}
+			cancel()
+
+			time.Sleep(frequency)
+		}
+	}
+}
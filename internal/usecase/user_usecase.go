@@ -8,21 +8,36 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"net/mail"
+	"strings"
+	"time"
 )
 
 type UserUsecase struct {
-	userRepo domain.UserRepo
+	userRepo      domain.UserRepo
+	tokenRepo     domain.TokenRepo
+	denyList      domain.TokenDenyList
+	loginThrottle *LoginThrottle
 }
 
-func NewUserUsecase(userRepo domain.UserRepo) *UserUsecase {
+func NewUserUsecase(userRepo domain.UserRepo, tokenRepo domain.TokenRepo, denyList domain.TokenDenyList,
+	loginThrottle *LoginThrottle) *UserUsecase {
 	return &UserUsecase{
-		userRepo: userRepo,
+		userRepo:      userRepo,
+		tokenRepo:     tokenRepo,
+		denyList:      denyList,
+		loginThrottle: loginThrottle,
 	}
 }
 
-func isValidEmail(email string) bool {
-	_, err := mail.ParseAddress(email)
-	return err == nil
+// normalizeEmail returns the address users are told apart by: without a display name,
+// surrounding spaces and in lower case.
+func normalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(address.Address), nil
 }
 
 func isValidUserType(userType string) bool {
@@ -44,7 +59,8 @@ func (u *UserUsecase) Register(ctx context.Context, userReq *domain.RegisterUser
 			fmt.Errorf("user usecase: register error: %w", domain.ErrUser_BadType)
 	}
 
-	if !isValidEmail(userReq.Email) {
+	email, err := normalizeEmail(userReq.Email)
+	if err != nil {
 		lg.Warn("user usecase: register error: bad mail", zap.String("mail", userReq.Email))
 		return domain.RegisterUserResponse{},
 			fmt.Errorf("user usecase: register error: %w", domain.ErrUser_BadMail)
@@ -70,7 +86,7 @@ func (u *UserUsecase) Register(ctx context.Context, userReq *domain.RegisterUser
 
 	user := domain.User{
 		UserID:   uuid,
-		Mail:     userReq.Email,
+		Mail:     email,
 		Password: encryptedPassword,
 		Role:     userReq.UserType,
 	}
@@ -78,10 +94,10 @@ func (u *UserUsecase) Register(ctx context.Context, userReq *domain.RegisterUser
 	err = u.userRepo.Create(ctx, &user, lg)
 	if err != nil {
 		lg.Warn("user usecase: register error", zap.Error(err))
-		return domain.RegisterUserResponse{}, fmt.Errorf("user usecase: register error: %v", err.Error())
+		return domain.RegisterUserResponse{}, fmt.Errorf("user usecase: register error: %w", err)
 	}
 
-	return domain.RegisterUserResponse{uuid}, nil
+	return domain.RegisterUserResponse{UserID: uuid}, nil
 }
 
 func (u *UserUsecase) Login(ctx context.Context, userReq *domain.LoginUserRequest, lg *zap.Logger) (domain.LoginUserResponse, error) {
@@ -93,25 +109,198 @@ func (u *UserUsecase) Login(ctx context.Context, userReq *domain.LoginUserReques
 			fmt.Errorf("user usecase: login error: %w", domain.ErrUser_BadRequest)
 	}
 
-	expectedUser, err := u.userRepo.GetByID(ctx, userReq.ID, lg)
+	var email string
+	switch {
+	case userReq.Email != "":
+		var err error
+		email, err = normalizeEmail(userReq.Email)
+		if err != nil {
+			lg.Warn("user usecase: login error: bad mail", zap.String("mail", userReq.Email))
+			return domain.LoginUserResponse{},
+				fmt.Errorf("user usecase: login error: %w", domain.ErrUser_BadMail)
+		}
+	case userReq.ID == uuid.Nil:
+		lg.Warn("user usecase: login error: no mail or id")
+		return domain.LoginUserResponse{},
+			fmt.Errorf("user usecase: login error: %w", domain.ErrUser_NoLogin)
+	}
+
+	// The attempt is counted before the password is checked and forgiven only on success, so that
+	// guesses sent at once cannot all pass the throttle before any of them is counted.
+	err := u.loginThrottle.Attempt(ctx, domain.LoginScopeIP, userReq.ClientIP, lg)
+	if err != nil {
+		lg.Warn("user usecase: login error", zap.Error(err))
+		return domain.LoginUserResponse{}, fmt.Errorf("user usecase: login error: %w", err)
+	}
+
+	var expectedUser domain.User
+	if email != "" {
+		expectedUser, err = u.userRepo.GetByMail(ctx, email, lg)
+	} else {
+		expectedUser, err = u.userRepo.GetByID(ctx, userReq.ID, lg)
+	}
 	if err != nil {
 		lg.Warn("user usescase: login error", zap.Error(err))
 		return domain.LoginUserResponse{}, fmt.Errorf("user usecase: login error: %v", err.Error())
 	}
 
+	userID := expectedUser.UserID.String()
+	err = u.loginThrottle.Attempt(ctx, domain.LoginScopeAccount, userID, lg)
+	if err != nil {
+		lg.Warn("user usecase: login error", zap.Error(err))
+		return domain.LoginUserResponse{}, fmt.Errorf("user usecase: login error: %w", err)
+	}
+
 	err = pkg.IsEqualPasswords(expectedUser.Password, userReq.Password)
 	if err != nil {
 		lg.Warn("user usecase: login error", zap.Error(err))
 		return domain.LoginUserResponse{}, fmt.Errorf("user usecase: login error: %v", err.Error())
 	}
 
-	token, err := pkg.GenerateJWTToken(expectedUser.UserID, expectedUser.Role)
+	err = u.loginThrottle.Reset(ctx, domain.LoginScopeAccount, userID, lg)
+	if err != nil {
+		lg.Warn("user usecase: login error: failures not reset", zap.Error(err))
+	}
+	err = u.loginThrottle.Forgive(ctx, domain.LoginScopeIP, userReq.ClientIP, lg)
+	if err != nil {
+		lg.Warn("user usecase: login error: ip failure not forgiven", zap.Error(err))
+	}
+
+	familyID, err := uuid.NewV7()
 	if err != nil {
 		lg.Warn("user usecase: login error", zap.Error(err))
 		return domain.LoginUserResponse{}, fmt.Errorf("user usecase: login error: %v", err.Error())
 	}
 
-	return domain.LoginUserResponse{token}, nil
+	loginResponse, err := u.issueTokens(ctx, expectedUser.UserID, expectedUser.Role, familyID, lg)
+	if err != nil {
+		lg.Warn("user usecase: login error", zap.Error(err))
+		return domain.LoginUserResponse{}, fmt.Errorf("user usecase: login error: %w", err)
+	}
+
+	return loginResponse, nil
+}
+
+func (u *UserUsecase) Unlock(ctx context.Context, userID uuid.UUID, lg *zap.Logger) error {
+	lg.Info("user usecase: unlock", zap.String("user_id", userID.String()))
+
+	if userID == uuid.Nil {
+		lg.Warn("user usecase: unlock error: bad user id")
+		return fmt.Errorf("user usecase: unlock error: %w", domain.ErrUser_BadId)
+	}
+
+	err := u.loginThrottle.Reset(ctx, domain.LoginScopeAccount, userID.String(), lg)
+	if err != nil {
+		lg.Warn("user usecase: unlock error", zap.Error(err))
+		return fmt.Errorf("user usecase: unlock error: %v", err.Error())
+	}
+
+	return nil
+}
+
+// issueTokens creates an access token and a refresh token of the family for the user.
+func (u *UserUsecase) issueTokens(ctx context.Context, userID uuid.UUID, role string, familyID uuid.UUID,
+	lg *zap.Logger) (domain.LoginUserResponse, error) {
+	accessToken, accessTokenID, accessExpiresAt, err := pkg.GenerateAccessToken(userID, role)
+	if err != nil {
+		return domain.LoginUserResponse{}, err
+	}
+
+	refreshToken, refreshTokenHash, err := pkg.GenerateRefreshToken()
+	if err != nil {
+		return domain.LoginUserResponse{}, err
+	}
+
+	tokenID, err := uuid.NewV7()
+	if err != nil {
+		return domain.LoginUserResponse{}, err
+	}
+
+	err = u.tokenRepo.Create(ctx, &domain.RefreshToken{
+		TokenID:         tokenID,
+		FamilyID:        familyID,
+		UserID:          userID,
+		TokenHash:       refreshTokenHash,
+		ExpiresAt:       time.Now().Add(pkg.RefreshTokenTTL),
+		AccessTokenID:   accessTokenID,
+		AccessExpiresAt: accessExpiresAt,
+	}, lg)
+	if err != nil {
+		return domain.LoginUserResponse{}, err
+	}
+
+	return domain.LoginUserResponse{Token: accessToken, RefreshToken: refreshToken}, nil
+}
+
+func (u *UserUsecase) Refresh(ctx context.Context, req *domain.RefreshTokenRequest, lg *zap.Logger) (domain.LoginUserResponse, error) {
+	lg.Info("user usecase: refresh")
+
+	if req == nil {
+		lg.Warn("user usecase: refresh error: bad nil request")
+		return domain.LoginUserResponse{},
+			fmt.Errorf("user usecase: refresh error: %w", domain.ErrToken_BadRequest)
+	}
+
+	if req.RefreshToken == "" {
+		lg.Warn("user usecase: refresh error: empty refresh token")
+		return domain.LoginUserResponse{},
+			fmt.Errorf("user usecase: refresh error: %w", domain.ErrToken_BadRefresh)
+	}
+
+	usedToken, err := u.tokenRepo.Use(ctx, pkg.HashRefreshToken(req.RefreshToken), lg)
+	if err != nil {
+		lg.Warn("user usecase: refresh error", zap.Error(err))
+		return domain.LoginUserResponse{}, fmt.Errorf("user usecase: refresh error: %w", err)
+	}
+
+	loginResponse, err := u.issueTokens(ctx, usedToken.UserID, usedToken.Role, usedToken.FamilyID, lg)
+	if err != nil {
+		lg.Warn("user usecase: refresh error", zap.Error(err))
+		return domain.LoginUserResponse{}, fmt.Errorf("user usecase: refresh error: %w", err)
+	}
+
+	return loginResponse, nil
+}
+
+func (u *UserUsecase) Logout(ctx context.Context, accessToken string, req *domain.LogoutRequest, lg *zap.Logger) error {
+	lg.Info("user usecase: logout")
+
+	if req == nil {
+		lg.Warn("user usecase: logout error: bad nil request")
+		return fmt.Errorf("user usecase: logout error: %w", domain.ErrToken_BadRequest)
+	}
+
+	claims, err := pkg.ValidateJWTToken(accessToken)
+	if err != nil {
+		lg.Warn("user usecase: logout error", zap.Error(err))
+		return fmt.Errorf("user usecase: logout error: %w", domain.ErrToken_BadAccess)
+	}
+	tokenID, _ := (*claims)["jti"].(string)
+	rawUserID, _ := claims.GetSubject()
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		lg.Warn("user usecase: logout error: bad subject", zap.Error(err))
+		return fmt.Errorf("user usecase: logout error: %w", domain.ErrToken_BadAccess)
+	}
+	expirationTime, _ := claims.GetExpirationTime()
+	expiresAt := expirationTime.Time
+
+	err = u.tokenRepo.RevokeAccessToken(ctx, tokenID, expiresAt, lg)
+	if err != nil {
+		lg.Warn("user usecase: logout error", zap.Error(err))
+		return fmt.Errorf("user usecase: logout error: %w", err)
+	}
+	u.denyList.Revoke(tokenID, expiresAt)
+
+	if req.RefreshToken != "" {
+		err = u.tokenRepo.RevokeFamily(ctx, pkg.HashRefreshToken(req.RefreshToken), userID, lg)
+		if err != nil {
+			lg.Warn("user usecase: logout error", zap.Error(err))
+			return fmt.Errorf("user usecase: logout error: %w", err)
+		}
+	}
+
+	return nil
 }
 
 func (u *UserUsecase) DummyLogin(ctx context.Context, userType string, lg *zap.Logger) (domain.LoginUserResponse, error) {
@@ -138,7 +327,7 @@ func (u *UserUsecase) DummyLogin(ctx context.Context, userType string, lg *zap.L
 
 	user := domain.User{
 		UserID:   uuid,
-		Mail:     domain.DummyMail,
+		Mail:     fmt.Sprintf(domain.DummyMailFormat, uuid),
 		Password: domain.DummyPassword,
 		Role:     userType,
 	}
@@ -150,5 +339,5 @@ func (u *UserUsecase) DummyLogin(ctx context.Context, userType string, lg *zap.L
 			fmt.Errorf("user usecase: dummy login error: %v", err.Error())
 	}
 
-	return domain.LoginUserResponse{token}, nil
+	return domain.LoginUserResponse{Token: token}, nil
 }
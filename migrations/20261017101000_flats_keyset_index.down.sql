@@ -0,0 +1,4 @@
+drop index if exists flats_house_id_flat_id;
+
+create index houses_id_on_flats
+    on flats (house_id);
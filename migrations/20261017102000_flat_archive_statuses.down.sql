@@ -0,0 +1,2 @@
+-- postgres can't drop enum values, so withdrawn flats just go back to moderation
+update flats set status = 'created' where status in ('archived', 'sold');
@@ -0,0 +1,2 @@
+alter type flat_status add value if not exists 'archived';
+alter type flat_status add value if not exists 'sold';
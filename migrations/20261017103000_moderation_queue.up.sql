@@ -0,0 +1,6 @@
+alter table flats
+    add column created_at timestamp without time zone not null default now();
+
+create index flats_moderation_queue
+    on flats (created_at, house_id, flat_id)
+    where status = 'created';
@@ -0,0 +1,42 @@
+drop function if exists update_status(flat_status, int, int, uuid, int, text);
+
+drop trigger if exists update_flat_status_history_trigger on flats;
+drop trigger if exists insert_flat_status_history_trigger on flats;
+drop function if exists record_flat_status_change;
+
+drop table if exists flat_status_history;
+
+alter table flats
+    drop column if exists status_comment,
+    drop column if exists status_actor_id;
+
+create or replace function update_status(new_status flat_status, new_flat_id int, new_house_id int,
+                                         new_moderator_id uuid, lease_ttl_sec int)
+    returns setof flats as $$
+declare
+    mod_id uuid;
+begin
+    if new_status = 'on moderation' then
+        select flats.moderator_id into mod_id from flats
+        where flats.flat_id=new_flat_id and flats.house_id=new_house_id;
+
+        if mod_id != new_moderator_id then
+            raise exception 'flat already on moderation';
+        end if;
+    end if;
+
+    if new_status = 'approved' or new_status = 'declined' or new_status = 'created' then
+        return query
+            update flats set status=new_status, moderator_id=null, lease_expires_at=null
+                where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+                returning *;
+        return;
+    end if;
+
+    return query
+        update flats set status=new_status, moderator_id=new_moderator_id,
+                         lease_expires_at=now() + make_interval(secs => lease_ttl_sec)
+            where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+        returning *;
+end;
+$$ language plpgsql;
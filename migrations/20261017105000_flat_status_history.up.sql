@@ -0,0 +1,80 @@
+alter table flats
+    add column status_actor_id uuid references users(user_id),
+    add column status_comment text;
+
+create table flat_status_history (
+    id bigserial primary key,
+    flat_id int not null,
+    house_id int not null,
+    old_status flat_status,
+    new_status flat_status not null,
+    actor_id uuid references users(user_id),
+    changed_at timestamp with time zone not null default now(),
+    comment text,
+    foreign key (flat_id, house_id) references flats(flat_id, house_id) on delete cascade
+);
+
+create index flat_status_history_flat
+    on flat_status_history (house_id, flat_id, id);
+
+create or replace function record_flat_status_change()
+    returns trigger as $$
+begin
+    if tg_op = 'INSERT' then
+        insert into flat_status_history (flat_id, house_id, old_status, new_status, actor_id, comment)
+        values (new.flat_id, new.house_id, null, new.status, coalesce(new.status_actor_id, new.user_id),
+                new.status_comment);
+    else
+        insert into flat_status_history (flat_id, house_id, old_status, new_status, actor_id, comment)
+        values (new.flat_id, new.house_id, old.status, new.status, new.status_actor_id, new.status_comment);
+    end if;
+
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger insert_flat_status_history_trigger
+    after insert on flats
+    for each row
+execute function record_flat_status_change();
+
+create trigger update_flat_status_history_trigger
+    after update of status on flats
+    for each row
+    when (old.status is distinct from new.status)
+execute function record_flat_status_change();
+
+drop function if exists update_status(flat_status, int, int, uuid, int);
+
+create or replace function update_status(new_status flat_status, new_flat_id int, new_house_id int,
+                                         new_moderator_id uuid, lease_ttl_sec int, new_comment text)
+    returns setof flats as $$
+declare
+    mod_id uuid;
+begin
+    if new_status = 'on moderation' then
+        select flats.moderator_id into mod_id from flats
+        where flats.flat_id=new_flat_id and flats.house_id=new_house_id;
+
+        if mod_id != new_moderator_id then
+            raise exception 'flat already on moderation';
+        end if;
+    end if;
+
+    if new_status = 'approved' or new_status = 'declined' or new_status = 'created' then
+        return query
+            update flats set status=new_status, moderator_id=null, lease_expires_at=null,
+                             status_actor_id=new_moderator_id, status_comment=new_comment
+                where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+                returning *;
+        return;
+    end if;
+
+    return query
+        update flats set status=new_status, moderator_id=new_moderator_id,
+                         lease_expires_at=now() + make_interval(secs => lease_ttl_sec),
+                         status_actor_id=new_moderator_id, status_comment=new_comment
+            where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+        returning *;
+end;
+$$ language plpgsql;
@@ -0,0 +1,70 @@
+drop function if exists update_status(flat_status, int, int, uuid, int, text, text, text);
+
+drop trigger if exists flat_declined_trigger on flats;
+drop function if exists insert_flat_declined_to_outbox;
+
+create or replace function record_flat_status_change()
+    returns trigger as $$
+begin
+    if tg_op = 'INSERT' then
+        insert into flat_status_history (flat_id, house_id, old_status, new_status, actor_id, comment)
+        values (new.flat_id, new.house_id, null, new.status, coalesce(new.status_actor_id, new.user_id),
+                new.status_comment);
+    else
+        insert into flat_status_history (flat_id, house_id, old_status, new_status, actor_id, comment)
+        values (new.flat_id, new.house_id, old.status, new.status, new.status_actor_id, new.status_comment);
+    end if;
+
+    return new;
+end;
+$$ language plpgsql;
+
+delete from new_flats_outbox where kind = 'flat declined';
+
+alter table new_flats_outbox
+    drop column if exists decline_reason_text,
+    drop column if exists decline_reason_code,
+    drop column if exists kind;
+
+alter table flat_status_history
+    drop column if exists decline_reason_text,
+    drop column if exists decline_reason_code;
+
+alter table flats
+    drop column if exists decline_reason_text,
+    drop column if exists decline_reason_code;
+
+drop type if exists outbox_notify_kind;
+
+create or replace function update_status(new_status flat_status, new_flat_id int, new_house_id int,
+                                         new_moderator_id uuid, lease_ttl_sec int, new_comment text)
+    returns setof flats as $$
+declare
+    mod_id uuid;
+begin
+    if new_status = 'on moderation' then
+        select flats.moderator_id into mod_id from flats
+        where flats.flat_id=new_flat_id and flats.house_id=new_house_id;
+
+        if mod_id != new_moderator_id then
+            raise exception 'flat already on moderation';
+        end if;
+    end if;
+
+    if new_status = 'approved' or new_status = 'declined' or new_status = 'created' then
+        return query
+            update flats set status=new_status, moderator_id=null, lease_expires_at=null,
+                             status_actor_id=new_moderator_id, status_comment=new_comment
+                where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+                returning *;
+        return;
+    end if;
+
+    return query
+        update flats set status=new_status, moderator_id=new_moderator_id,
+                         lease_expires_at=now() + make_interval(secs => lease_ttl_sec),
+                         status_actor_id=new_moderator_id, status_comment=new_comment
+            where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+        returning *;
+end;
+$$ language plpgsql;
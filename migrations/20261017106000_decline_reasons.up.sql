@@ -0,0 +1,108 @@
+create type outbox_notify_kind as enum ('new flat', 'flat declined');
+
+alter table flats
+    add column decline_reason_code text,
+    add column decline_reason_text text;
+
+alter table flat_status_history
+    add column decline_reason_code text,
+    add column decline_reason_text text;
+
+alter table new_flats_outbox
+    add column kind outbox_notify_kind not null default 'new flat',
+    add column decline_reason_code text,
+    add column decline_reason_text text;
+
+create or replace function record_flat_status_change()
+    returns trigger as $$
+begin
+    if tg_op = 'INSERT' then
+        insert into flat_status_history (flat_id, house_id, old_status, new_status, actor_id, comment)
+        values (new.flat_id, new.house_id, null, new.status, coalesce(new.status_actor_id, new.user_id),
+                new.status_comment);
+    elsif new.status = 'declined' then
+        insert into flat_status_history (flat_id, house_id, old_status, new_status, actor_id, comment,
+                                         decline_reason_code, decline_reason_text)
+        values (new.flat_id, new.house_id, old.status, new.status, new.status_actor_id, new.status_comment,
+                new.decline_reason_code, new.decline_reason_text);
+    else
+        insert into flat_status_history (flat_id, house_id, old_status, new_status, actor_id, comment)
+        values (new.flat_id, new.house_id, old.status, new.status, new.status_actor_id, new.status_comment);
+    end if;
+
+    return new;
+end;
+$$ language plpgsql;
+
+create or replace function insert_flat_declined_to_outbox()
+    returns trigger as $$
+declare
+    owner_mail text;
+begin
+    select users.mail into owner_mail from users where users.user_id = new.user_id;
+
+    if owner_mail is null then
+        return new;
+    end if;
+
+    insert into new_flats_outbox(flat_id, house_id, mail, status, kind, decline_reason_code, decline_reason_text)
+    values (new.flat_id, new.house_id, owner_mail, 'no send', 'flat declined',
+            new.decline_reason_code, new.decline_reason_text);
+
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger flat_declined_trigger
+    after update of status on flats
+    for each row
+    when (new.status = 'declined' and old.status is distinct from new.status)
+execute function insert_flat_declined_to_outbox();
+
+drop function if exists update_status(flat_status, int, int, uuid, int, text);
+
+create or replace function update_status(new_status flat_status, new_flat_id int, new_house_id int,
+                                         new_moderator_id uuid, lease_ttl_sec int, new_comment text,
+                                         new_reason_code text, new_reason_text text)
+    returns setof flats as $$
+declare
+    mod_id uuid;
+begin
+    if new_status = 'on moderation' then
+        select flats.moderator_id into mod_id from flats
+        where flats.flat_id=new_flat_id and flats.house_id=new_house_id;
+
+        if mod_id != new_moderator_id then
+            raise exception 'flat already on moderation';
+        end if;
+    end if;
+
+    if new_status = 'declined' then
+        return query
+            update flats set status=new_status, moderator_id=null, lease_expires_at=null,
+                             status_actor_id=new_moderator_id, status_comment=new_comment,
+                             decline_reason_code=new_reason_code, decline_reason_text=new_reason_text
+                where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+                returning *;
+        return;
+    end if;
+
+    if new_status = 'approved' or new_status = 'created' then
+        return query
+            update flats set status=new_status, moderator_id=null, lease_expires_at=null,
+                             status_actor_id=new_moderator_id, status_comment=new_comment,
+                             decline_reason_code=null, decline_reason_text=null
+                where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+                returning *;
+        return;
+    end if;
+
+    return query
+        update flats set status=new_status, moderator_id=new_moderator_id,
+                         lease_expires_at=now() + make_interval(secs => lease_ttl_sec),
+                         status_actor_id=new_moderator_id, status_comment=new_comment,
+                         decline_reason_code=null, decline_reason_text=null
+            where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+        returning *;
+end;
+$$ language plpgsql;
@@ -0,0 +1,47 @@
+drop function if exists update_status(flat_status, int, int, uuid, int, text, text, text, flat_status);
+
+create or replace function update_status(new_status flat_status, new_flat_id int, new_house_id int,
+                                         new_moderator_id uuid, lease_ttl_sec int, new_comment text,
+                                         new_reason_code text, new_reason_text text)
+    returns setof flats as $$
+declare
+    mod_id uuid;
+begin
+    if new_status = 'on moderation' then
+        select flats.moderator_id into mod_id from flats
+        where flats.flat_id=new_flat_id and flats.house_id=new_house_id;
+
+        if mod_id != new_moderator_id then
+            raise exception 'flat already on moderation';
+        end if;
+    end if;
+
+    if new_status = 'declined' then
+        return query
+            update flats set status=new_status, moderator_id=null, lease_expires_at=null,
+                             status_actor_id=new_moderator_id, status_comment=new_comment,
+                             decline_reason_code=new_reason_code, decline_reason_text=new_reason_text
+                where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+                returning *;
+        return;
+    end if;
+
+    if new_status = 'approved' or new_status = 'created' then
+        return query
+            update flats set status=new_status, moderator_id=null, lease_expires_at=null,
+                             status_actor_id=new_moderator_id, status_comment=new_comment,
+                             decline_reason_code=null, decline_reason_text=null
+                where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+                returning *;
+        return;
+    end if;
+
+    return query
+        update flats set status=new_status, moderator_id=new_moderator_id,
+                         lease_expires_at=now() + make_interval(secs => lease_ttl_sec),
+                         status_actor_id=new_moderator_id, status_comment=new_comment,
+                         decline_reason_code=null, decline_reason_text=null
+            where flats.flat_id=new_flat_id and flats.house_id=new_house_id
+        returning *;
+end;
+$$ language plpgsql;
@@ -0,0 +1,8 @@
+drop index if exists houses_update_flat_date_id;
+drop index if exists houses_create_house_date_id;
+
+alter table houses
+    alter column update_flat_date drop not null,
+    alter column update_flat_date drop default,
+    alter column create_house_date drop not null,
+    alter column create_house_date drop default;
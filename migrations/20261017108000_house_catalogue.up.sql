@@ -0,0 +1,17 @@
+update houses set create_house_date = now()
+where create_house_date is null;
+
+update houses set update_flat_date = create_house_date
+where update_flat_date is null;
+
+alter table houses
+    alter column create_house_date set default now(),
+    alter column create_house_date set not null,
+    alter column update_flat_date set default now(),
+    alter column update_flat_date set not null;
+
+create index houses_create_house_date_id
+    on houses (create_house_date, house_id);
+
+create index houses_update_flat_date_id
+    on houses (update_flat_date, house_id);
@@ -0,0 +1,4 @@
+drop index if exists houses_developer_trgm;
+drop index if exists houses_address_trgm;
+
+drop function if exists normalize_address(text);
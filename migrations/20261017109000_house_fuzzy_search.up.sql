@@ -0,0 +1,45 @@
+create extension if not exists pg_trgm;
+
+-- normalize_address lower-cases an address and expands common abbreviations
+-- ("ул." -> "улица", "пр-т" -> "проспект", ...) so that both spellings
+-- produce the same trigrams. Both the stored addresses and the search query
+-- go through it.
+create or replace function normalize_address(address text)
+    returns text as
+$$
+declare
+    abbreviations constant text[][] := array[
+        ['ул', 'улица'],
+        ['пр-к?т', 'проспект'],
+        ['просп', 'проспект'],
+        ['пр-д', 'проезд'],
+        ['пер', 'переулок'],
+        ['б-р', 'бульвар'],
+        ['бул', 'бульвар'],
+        ['наб', 'набережная'],
+        ['пл', 'площадь'],
+        ['ш', 'шоссе'],
+        ['мкр', 'микрорайон'],
+        ['г', 'город'],
+        ['д', 'дом'],
+        ['корп', 'корпус'],
+        ['к', 'корпус'],
+        ['стр', 'строение']
+    ];
+    normalized text := replace(lower(address), 'ё', 'е');
+begin
+    for i in 1 .. array_length(abbreviations, 1) loop
+        normalized := regexp_replace(normalized,
+            '(?<![а-яa-z0-9])' || abbreviations[i][1] || '(?![а-яa-z0-9-])\.?',
+            abbreviations[i][2], 'g');
+    end loop;
+
+    return trim(regexp_replace(normalized, '[[:space:][:punct:]]+', ' ', 'g'));
+end;
+$$ language plpgsql immutable parallel safe;
+
+create index houses_address_trgm
+    on houses using gin (normalize_address(address) gin_trgm_ops);
+
+create index houses_developer_trgm
+    on houses using gin (lower(developer) gin_trgm_ops);
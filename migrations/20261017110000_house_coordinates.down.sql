@@ -0,0 +1,6 @@
+drop index if exists houses_coordinates;
+
+alter table houses
+    drop constraint if exists houses_coordinates_check,
+    drop column if exists longitude,
+    drop column if exists latitude;
@@ -0,0 +1,14 @@
+alter table houses
+    add column latitude double precision,
+    add column longitude double precision,
+    add constraint houses_coordinates_check check (
+        (latitude is null) = (longitude is null)
+        and latitude between -90 and 90
+        and longitude between -180 and 180
+    );
+
+-- Radius search first narrows houses down to the bounding box of the circle
+-- with this index and only then computes exact distances.
+create index houses_coordinates
+    on houses (latitude, longitude)
+    where latitude is not null;
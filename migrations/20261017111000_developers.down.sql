@@ -0,0 +1,8 @@
+drop index if exists houses_developer_id;
+
+alter table houses
+    drop column if exists developer_id;
+
+drop table if exists developers;
+
+drop function if exists normalize_developer(text);
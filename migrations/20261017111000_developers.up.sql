@@ -0,0 +1,39 @@
+-- normalize_developer is the key developers are deduplicated by: case, quotes
+-- and repeated spaces do not make two developers different.
+create or replace function normalize_developer(name text)
+    returns text as
+$$
+    select lower(trim(regexp_replace(regexp_replace(name, '[«»"''“”„]', '', 'g'), '\s+', ' ', 'g')));
+$$ language sql immutable parallel safe;
+
+create table developers (
+    developer_id serial primary key,
+    name text not null check (normalize_developer(name) <> ''),
+    created_at timestamptz not null default now()
+);
+
+create unique index developers_name_unique
+    on developers (normalize_developer(name));
+
+-- Every group of equal developer strings becomes one row named after its most
+-- used spelling.
+insert into developers(name)
+select distinct on (name_key) name
+from (
+    select normalize_developer(developer) as name_key, trim(developer) as name, count(*) as houses
+    from houses
+    where developer is not null and normalize_developer(developer) <> ''
+    group by 1, 2
+) spellings
+order by name_key, houses desc, name;
+
+alter table houses
+    add column developer_id int references developers(developer_id);
+
+update houses h set developer_id = d.developer_id,
+                    developer = d.name
+from developers d
+where normalize_developer(h.developer) = normalize_developer(d.name);
+
+create index houses_developer_id
+    on houses (developer_id, house_id);
@@ -0,0 +1,64 @@
+alter table flats
+    add column previous_price int,
+    add column price_dropped_at timestamp with time zone;
+
+create table flat_price_history (
+    id bigserial primary key,
+    flat_id int not null,
+    house_id int not null,
+    price int not null,
+    changed_at timestamp with time zone not null default now(),
+    foreign key (flat_id, house_id) references flats(flat_id, house_id) on delete cascade
+);
+
+create index flat_price_history_flat
+    on flat_price_history (house_id, flat_id, id);
+
+insert into flat_price_history (flat_id, house_id, price, changed_at)
+select flat_id, house_id, price, created_at
+from flats
+order by created_at, house_id, flat_id;
+
+-- previous_price and price_dropped_at describe the last price change and are set
+-- only when that change lowered the price.
+create or replace function mark_flat_price_drop()
+    returns trigger as $$
+begin
+    if new.price < old.price then
+        new.previous_price = old.price;
+        new.price_dropped_at = now();
+    else
+        new.previous_price = null;
+        new.price_dropped_at = null;
+    end if;
+
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger mark_flat_price_drop_trigger
+    before update of price on flats
+    for each row
+    when (old.price is distinct from new.price)
+execute function mark_flat_price_drop();
+
+create or replace function record_flat_price_change()
+    returns trigger as $$
+begin
+    insert into flat_price_history (flat_id, house_id, price)
+    values (new.flat_id, new.house_id, new.price);
+
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger insert_flat_price_history_trigger
+    after insert on flats
+    for each row
+execute function record_flat_price_change();
+
+create trigger update_flat_price_history_trigger
+    after update of price on flats
+    for each row
+    when (old.price is distinct from new.price)
+execute function record_flat_price_change();
@@ -0,0 +1,2 @@
+alter table flats
+    alter column status drop default;
@@ -0,0 +1,3 @@
+-- Bulk import copies flats without the status column: pgx COPY can't encode the flat_status enum.
+alter table flats
+    alter column status set default 'created';
@@ -0,0 +1,14 @@
+drop trigger if exists delete_house_developer_feed_trigger on houses;
+drop trigger if exists update_house_developer_feed_trigger on houses;
+drop trigger if exists update_house_feed_trigger on houses;
+drop trigger if exists update_flat_feed_trigger on flats;
+drop trigger if exists insert_flat_feed_trigger on flats;
+
+drop function if exists bump_developer_feed_version;
+drop function if exists queue_house_feed_change;
+drop function if exists queue_flat_feed_change;
+
+drop table if exists developer_feed_versions;
+drop table if exists feed_versions;
+drop table if exists feed_offers;
+drop table if exists feed_changes;
@@ -0,0 +1,112 @@
+-- feed_changes queues flats whose feed offers have to be rendered again. A flat
+-- queued twice keeps one row with the time of the last change.
+create table feed_changes (
+    flat_id int not null,
+    house_id int not null references houses(house_id) on delete cascade,
+    changed_at timestamp with time zone not null default clock_timestamp(),
+    primary key (house_id, flat_id)
+);
+
+create index feed_changes_changed_at
+    on feed_changes (changed_at);
+
+create table feed_offers (
+    flat_id int not null,
+    house_id int not null,
+    format varchar(16) not null,
+    offer text not null,
+    primary key (house_id, flat_id, format),
+    foreign key (flat_id, house_id) references flats(flat_id, house_id) on delete cascade
+);
+
+-- feed_versions keeps when the offers of a house last changed; feeds are cached by it.
+create table feed_versions (
+    house_id int primary key references houses(house_id) on delete cascade,
+    updated_at timestamp with time zone not null default now()
+);
+
+-- developer_feed_versions keeps when the set of houses of a developer last changed. A house
+-- that moves to another developer or is deleted takes its offers and its version with it,
+-- so the version of a developer feed can not be told by its remaining houses alone.
+create table developer_feed_versions (
+    developer_id int primary key references developers(developer_id) on delete cascade,
+    updated_at timestamp with time zone not null default now()
+);
+
+create or replace function queue_flat_feed_change()
+    returns trigger as $$
+begin
+    insert into feed_changes (flat_id, house_id)
+    values (new.flat_id, new.house_id)
+    on conflict (house_id, flat_id) do update set changed_at = clock_timestamp();
+
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger insert_flat_feed_trigger
+    after insert on flats
+    for each row
+    when (new.status = 'approved')
+execute function queue_flat_feed_change();
+
+-- Only approved flats are in feeds, so a change matters when the flat is approved
+-- before or after it.
+create trigger update_flat_feed_trigger
+    after update of status, price, rooms on flats
+    for each row
+    when (old.status = 'approved' or new.status = 'approved')
+execute function queue_flat_feed_change();
+
+create or replace function queue_house_feed_change()
+    returns trigger as $$
+begin
+    insert into feed_changes (flat_id, house_id)
+    select flat_id, house_id
+    from flats
+    where house_id = new.house_id and status = 'approved'
+    on conflict (house_id, flat_id) do update set changed_at = clock_timestamp();
+
+    return new;
+end;
+$$ language plpgsql;
+
+create trigger update_house_feed_trigger
+    after update of address, construct_year, developer, developer_id, latitude, longitude on houses
+    for each row
+execute function queue_house_feed_change();
+
+create or replace function bump_developer_feed_version()
+    returns trigger as $$
+declare
+    changed_developers int[] := array[old.developer_id];
+begin
+    if tg_op = 'UPDATE' then
+        changed_developers := changed_developers || new.developer_id;
+    end if;
+
+    insert into developer_feed_versions (developer_id, updated_at)
+    select distinct developer_id, now()
+    from unnest(changed_developers) as changed(developer_id)
+    where developer_id is not null
+    on conflict (developer_id) do update set updated_at = now();
+
+    return null;
+end;
+$$ language plpgsql;
+
+create trigger update_house_developer_feed_trigger
+    after update of developer_id on houses
+    for each row
+    when (old.developer_id is distinct from new.developer_id)
+execute function bump_developer_feed_version();
+
+create trigger delete_house_developer_feed_trigger
+    after delete on houses
+    for each row
+execute function bump_developer_feed_version();
+
+insert into feed_changes (flat_id, house_id)
+select flat_id, house_id
+from flats
+where status = 'approved';
@@ -0,0 +1,4 @@
+drop index if exists users_mail_unique;
+
+alter table users
+    alter column mail type varchar(50) using left(mail, 50);
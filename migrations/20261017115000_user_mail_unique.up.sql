@@ -0,0 +1,19 @@
+alter table users
+    alter column mail type text;
+
+update users set mail = lower(trim(mail));
+
+-- Addresses registered more than once, dummy logins above all, stay only with the
+-- user whose id sorts first. Users have no creation time, so this is just a stable
+-- pick, not the oldest account. The others get their user id as an address tag
+-- and keep logging in by id.
+update users u
+set mail = split_part(u.mail, '@', 1) || '+' || u.user_id || '@' || split_part(u.mail, '@', 2)
+from (
+    select user_id, row_number() over (partition by mail order by user_id) as n
+    from users
+) duplicates
+where duplicates.user_id = u.user_id and duplicates.n > 1;
+
+create unique index users_mail_unique
+    on users (lower(mail));
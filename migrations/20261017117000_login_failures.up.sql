@@ -0,0 +1,11 @@
+create table login_failures (
+    scope text not null check (scope in ('account', 'ip')),
+    subject text not null,
+    failures int not null,
+    last_failed_at timestamptz not null,
+    previous_failed_at timestamptz not null,
+    primary key (scope, subject)
+);
+
+create index login_failures_last_failed_at
+    on login_failures (last_failed_at);
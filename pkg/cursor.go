@@ -0,0 +1,45 @@
+package pkg
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrBadCursor = errors.New("bad cursor")
+
+func EncodeCursor(keys ...int) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, strconv.Itoa(key))
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, ":")))
+}
+
+func DecodeCursor(cursor string, keysNumber int) ([]int, error) {
+	keys := make([]int, keysNumber)
+	if cursor == "" {
+		return keys, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != keysNumber {
+		return nil, ErrBadCursor
+	}
+
+	for i, part := range parts {
+		keys[i], err = strconv.Atoi(part)
+		if err != nil || keys[i] < 0 {
+			return nil, ErrBadCursor
+		}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,179 @@
+package pkg
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"os"
+	"sort"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	minRSAKeyBits = 2048
+)
+
+var ErrBadSigningKey = errors.New("bad signing key")
+
+// Algorithm is HS256 to sign tokens with Key, or RS256 or EdDSA to sign them with PrivateKey:
+// then other services verify tokens by the public keys from the JWKS endpoint and do not need the secret.
+var (
+	Algorithm  = AlgorithmHS256
+	PrivateKey crypto.Signer
+	// PreviousPublicKeys, by kid, verify tokens signed with rotated out private keys.
+	PreviousPublicKeys map[string]crypto.PublicKey
+)
+
+// JWK is a public verification key as described by RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadSigningKeys reads the PEM private key for RS256 and EdDSA and the PEM public keys that were
+// used before rotation, by kid. HS256 needs no private key.
+func LoadSigningKeys(algorithm string, privateKeyFile string, previousPublicKeyFiles map[string]string) error {
+	var (
+		privateKey crypto.Signer
+		err        error
+	)
+	switch algorithm {
+	case AlgorithmHS256:
+	case AlgorithmRS256, AlgorithmEdDSA:
+		privateKey, err = readPrivateKey(privateKeyFile, algorithm)
+		if err != nil {
+			return fmt.Errorf("load signing keys error: %w", err)
+		}
+	default:
+		return fmt.Errorf("load signing keys error: %w: unknown algorithm %q", ErrBadSigningKey, algorithm)
+	}
+
+	previousPublicKeys := make(map[string]crypto.PublicKey, len(previousPublicKeyFiles))
+	for kid, file := range previousPublicKeyFiles {
+		previousPublicKeys[kid], err = readPublicKey(file)
+		if err != nil {
+			return fmt.Errorf("load signing keys error: key %s: %w", kid, err)
+		}
+	}
+
+	Algorithm, PrivateKey, PreviousPublicKeys = algorithm, privateKey, previousPublicKeys
+	return nil
+}
+
+func readPrivateKey(path string, algorithm string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if algorithm == AlgorithmRS256 {
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBadSigningKey, err)
+		}
+		if key.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("%w: rsa key is shorter than %d bits", ErrBadSigningKey, minRSAKeyBits)
+		}
+		return key, nil
+	}
+
+	key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadSigningKey, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an ed25519 key", ErrBadSigningKey)
+	}
+
+	return edKey, nil
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no pem block", ErrBadSigningKey)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadSigningKey, err)
+	}
+	if publicKeyAlgorithm(key) == "" {
+		return nil, fmt.Errorf("%w: key is neither rsa nor ed25519", ErrBadSigningKey)
+	}
+
+	return key, nil
+}
+
+// publicKeyAlgorithm returns the only algorithm tokens verified by the key may use.
+func publicKeyAlgorithm(key crypto.PublicKey) string {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return AlgorithmRS256
+	case ed25519.PublicKey:
+		return AlgorithmEdDSA
+	default:
+		return ""
+	}
+}
+
+// JWKS lists the public keys tokens are verified with: the current one first, then the previous ones.
+// HMAC keys are secret and are never listed.
+func JWKS() JWKSet {
+	keySet := JWKSet{Keys: []JWK{}}
+	if PrivateKey != nil {
+		keySet.Keys = append(keySet.Keys, publicJWK(KeyID, PrivateKey.Public()))
+	}
+
+	kids := make([]string, 0, len(PreviousPublicKeys))
+	for kid := range PreviousPublicKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+	for _, kid := range kids {
+		keySet.Keys = append(keySet.Keys, publicJWK(kid, PreviousPublicKeys[kid]))
+	}
+
+	return keySet
+}
+
+func publicJWK(kid string, key crypto.PublicKey) JWK {
+	jwk := JWK{Kid: kid, Use: "sig", Alg: publicKeyAlgorithm(key)}
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	}
+
+	return jwk
+}
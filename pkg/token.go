@@ -1,46 +1,175 @@
 package pkg
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"time"
 )
 
-var Key string
+// Key signs issued tokens; their kid header is KeyID. PreviousKeys, by kid, only verify tokens
+// signed before the key was rotated, so that rotation does not log everyone out.
+var (
+	Key          string
+	KeyID        = "main"
+	PreviousKeys map[string]string
+)
 
-func GenerateJWTToken(userId uuid.UUID, role string) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-	claims["userID"] = userId
-	claims["role"] = role
-	claims["expired_time"] = time.Now().Add(time.Hour * 1).Unix()
-	tokenString, err := token.SignedString([]byte(Key))
+var (
+	Issuer   = "avito-test-task"
+	Audience = "avito-test-task"
+	// Leeway allows for clock skew between replicas when checking exp, nbf and iat.
+	Leeway = 30 * time.Second
+)
+
+var (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// allowedSigningMethods keeps tokens signed with other algorithms, "none" included, from being accepted.
+// Each key is also bound to its own algorithm, so a public key is never used as an HMAC secret.
+var allowedSigningMethods = []string{AlgorithmHS256, AlgorithmRS256, AlgorithmEdDSA}
+
+const refreshTokenSize = 32
+
+// GenerateAccessToken returns a signed access token together with its id (jti claim)
+// and expiry, which are needed to revoke it.
+func GenerateAccessToken(userId uuid.UUID, role string) (string, string, time.Time, error) {
+	tokenID, err := uuid.NewRandom()
 	if err != nil {
-		return "", err
+		return "", "", time.Time{}, err
+	}
+	issuedAt := jwt.NewNumericDate(time.Now())
+	expiresAt := jwt.NewNumericDate(issuedAt.Add(AccessTokenTTL))
+
+	method, key, err := signingKey()
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"iss":  Issuer,
+		"aud":  Audience,
+		"sub":  userId.String(),
+		"jti":  tokenID.String(),
+		"iat":  issuedAt,
+		"nbf":  issuedAt,
+		"exp":  expiresAt,
+		"role": role,
+	})
+	token.Header["kid"] = KeyID
+	tokenString, err := token.SignedString(key)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	return tokenString, tokenID.String(), expiresAt.Time, nil
+}
+
+func GenerateJWTToken(userId uuid.UUID, role string) (string, error) {
+	tokenString, _, _, err := GenerateAccessToken(userId, role)
+	return tokenString, err
+}
+
+// GenerateRefreshToken returns a random opaque refresh token and the hash it is stored by.
+func GenerateRefreshToken() (string, string, error) {
+	value := make([]byte, refreshTokenSize)
+	if _, err := rand.Read(value); err != nil {
+		return "", "", err
 	}
 
-	return tokenString, nil
+	token := base64.RawURLEncoding.EncodeToString(value)
+	return token, HashRefreshToken(token), nil
 }
 
+func HashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func signingKey() (jwt.SigningMethod, interface{}, error) {
+	if Algorithm == AlgorithmHS256 {
+		return jwt.SigningMethodHS256, []byte(Key), nil
+	}
+	if PrivateKey == nil {
+		return nil, nil, fmt.Errorf("%w: no private key for %s", ErrBadSigningKey, Algorithm)
+	}
+
+	return jwt.GetSigningMethod(Algorithm), PrivateKey, nil
+}
+
+// verificationKey picks the key by the kid header of the token and checks that the token is signed
+// with the algorithm of the key.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	alg := token.Method.Alg()
+
+	var (
+		key          interface{}
+		keyAlgorithm string
+	)
+	switch {
+	case kid == KeyID && Algorithm == AlgorithmHS256:
+		key, keyAlgorithm = []byte(Key), AlgorithmHS256
+	case kid == KeyID && PrivateKey != nil:
+		key, keyAlgorithm = PrivateKey.Public(), Algorithm
+	case PreviousKeys[kid] != "":
+		key, keyAlgorithm = []byte(PreviousKeys[kid]), AlgorithmHS256
+	case PreviousPublicKeys[kid] != nil:
+		key, keyAlgorithm = PreviousPublicKeys[kid], publicKeyAlgorithm(PreviousPublicKeys[kid])
+	default:
+		return nil, fmt.Errorf("%w: unknown key id %q", jwt.ErrTokenUnverifiable, kid)
+	}
+	if alg != keyAlgorithm {
+		return nil, fmt.Errorf("%w: key %q is for %s, not %s", jwt.ErrTokenSignatureInvalid, kid, keyAlgorithm, alg)
+	}
+
+	return key, nil
+}
+
+// ValidateJWTToken checks the signature and the registered claims of the token: it must be issued
+// by Issuer for Audience, be already valid and not expired, and name its subject and id.
 func ValidateJWTToken(tokenString string) (*jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(Key), nil
-	})
+	token, err := jwt.Parse(tokenString, verificationKey,
+		jwt.WithValidMethods(allowedSigningMethods),
+		jwt.WithIssuer(Issuer),
+		jwt.WithAudience(Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(Leeway),
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return &claims, nil
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	for _, field := range []string{"sub", "jti"} {
+		if value, _ := claims[field].(string); value == "" {
+			return nil, fmt.Errorf("%w: no %s claim", jwt.ErrTokenInvalidClaims, field)
+		}
 	}
-	return nil, err
+
+	return &claims, nil
 }
 
 func ExtractPayloadFromToken(tokenString string, field string) (string, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(Key), nil
-	})
+	claims, err := ValidateJWTToken(tokenString)
+	if err != nil {
+		return "", err
+	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims[field].(string), nil
+	value, ok := (*claims)[field].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: no %s claim", jwt.ErrTokenInvalidClaims, field)
 	}
 
-	return "", err
+	return value, nil
 }
@@ -0,0 +1,33 @@
+package pkg
+
+import (
+	"strings"
+	"unicode"
+)
+
+// latinToCyrillic follows the common street-sign romanization. Longer
+// combinations go first: strings.Replacer tries them in argument order.
+var latinToCyrillic = strings.NewReplacer(
+	"shch", "щ", "sch", "щ",
+	"yo", "ё", "yu", "ю", "ya", "я", "ye", "е",
+	"zh", "ж", "kh", "х", "ts", "ц", "ch", "ч", "sh", "ш",
+	"iy", "ий", "yy", "ый", "'", "ь",
+	"a", "а", "b", "б", "v", "в", "g", "г", "d", "д", "e", "е", "z", "з",
+	"i", "и", "j", "й", "k", "к", "l", "л", "m", "м", "n", "н", "o", "о",
+	"p", "п", "r", "р", "s", "с", "t", "т", "u", "у", "f", "ф", "h", "х",
+	"c", "ц", "w", "в", "x", "кс", "y", "ы", "q", "к",
+)
+
+func HasLatin(s string) bool {
+	for _, r := range s {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TransliterateToCyrillic(s string) string {
+	return latinToCyrillic.Replace(strings.ToLower(s))
+}
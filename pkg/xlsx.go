@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// xlsxParts are the fixed parts of a workbook with a single sheet stored in xl/worksheets/sheet1.xml.
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// XLSXWriter streams rows into a single-sheet XLSX workbook. Strings are stored inline,
+// so rows are written as they come without keeping them in memory.
+type XLSXWriter struct {
+	zw    *zip.Writer
+	sheet io.Writer
+}
+
+func NewXLSXWriter(w io.Writer, sheetName string) (*XLSXWriter, error) {
+	zw := zip.NewWriter(w)
+
+	for _, part := range xlsxParts {
+		if _, err := writeZipPart(zw, part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	workbook := xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + escapeXML(sheetName) + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if _, err := writeZipPart(zw, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+
+	sheet, err := writeZipPart(zw, "xl/worksheets/sheet1.xml", xml.Header+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &XLSXWriter{zw: zw, sheet: sheet}, nil
+}
+
+// writeZipPart starts a new archive entry with the given content and returns its writer.
+func writeZipPart(zw *zip.Writer, name string, content string) (io.Writer, error) {
+	part, err := zw.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.WriteString(part, content); err != nil {
+		return nil, err
+	}
+
+	return part, nil
+}
+
+// WriteRow appends a row. Integers and floats become number cells, nil an empty cell
+// and anything else a string cell.
+func (x *XLSXWriter) WriteRow(values []any) error {
+	var row strings.Builder
+	row.WriteString("<row>")
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			row.WriteString("<c/>")
+		case int:
+			row.WriteString("<c><v>" + strconv.Itoa(v) + "</v></c>")
+		case int64:
+			row.WriteString("<c><v>" + strconv.FormatInt(v, 10) + "</v></c>")
+		case float64:
+			row.WriteString("<c><v>" + strconv.FormatFloat(v, 'f', -1, 64) + "</v></c>")
+		default:
+			row.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">` + escapeXML(fmt.Sprint(v)) + "</t></is></c>")
+		}
+	}
+	row.WriteString("</row>")
+
+	_, err := io.WriteString(x.sheet, row.String())
+	return err
+}
+
+// Flush sends the rows written so far to the underlying writer.
+func (x *XLSXWriter) Flush() error {
+	return x.zw.Flush()
+}
+
+// Close finishes the sheet and the archive. It does not close the underlying writer.
+func (x *XLSXWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, "</sheetData></worksheet>"); err != nil {
+		return err
+	}
+
+	return x.zw.Close()
+}
+
+func escapeXML(s string) string {
+	var escaped strings.Builder
+	_ = xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
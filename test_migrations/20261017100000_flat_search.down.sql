@@ -0,0 +1,4 @@
+drop index if exists houses_construct_year;
+drop index if exists houses_developer;
+drop index if exists flats_rooms;
+drop index if exists flats_status_price;
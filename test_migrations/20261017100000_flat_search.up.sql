@@ -0,0 +1,11 @@
+create index flats_status_price
+    on flats (status, price);
+
+create index flats_rooms
+    on flats (rooms);
+
+create index houses_developer
+    on houses (developer);
+
+create index houses_construct_year
+    on houses (construct_year);
@@ -0,0 +1,4 @@
+drop index if exists houses_id_on_flats;
+
+create index flats_house_id_flat_id
+    on flats (house_id, flat_id);
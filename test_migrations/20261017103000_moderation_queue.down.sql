@@ -0,0 +1,4 @@
+drop index if exists flats_moderation_queue;
+
+alter table flats
+    drop column if exists created_at;
@@ -0,0 +1,12 @@
+drop trigger if exists update_flat_price_history_trigger on flats;
+drop trigger if exists insert_flat_price_history_trigger on flats;
+drop function if exists record_flat_price_change;
+
+drop trigger if exists mark_flat_price_drop_trigger on flats;
+drop function if exists mark_flat_price_drop;
+
+drop table if exists flat_price_history;
+
+alter table flats
+    drop column if exists price_dropped_at,
+    drop column if exists previous_price;
@@ -0,0 +1,26 @@
+create table refresh_tokens (
+    token_id uuid primary key,
+    family_id uuid not null,
+    user_id uuid not null references users(user_id) on delete cascade,
+    token_hash text not null unique,
+    created_at timestamp with time zone not null default now(),
+    expires_at timestamp with time zone not null,
+    used_at timestamp with time zone,
+    revoked_at timestamp with time zone,
+    access_token_id text not null,
+    access_expires_at timestamp with time zone not null
+);
+
+create index refresh_tokens_family
+    on refresh_tokens (family_id);
+
+-- revoked_access_tokens is the deny-list of access tokens revoked before they expired.
+-- Every replica keeps it in memory and reloads rows by revoked_at.
+create table revoked_access_tokens (
+    token_id text primary key,
+    expires_at timestamp with time zone not null,
+    revoked_at timestamp with time zone not null default clock_timestamp()
+);
+
+create index revoked_access_tokens_revoked_at
+    on revoked_access_tokens (revoked_at);
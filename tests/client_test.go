@@ -10,6 +10,7 @@ import (
 	"avito-test-task/internal/usecase"
 	"avito-test-task/pkg"
 	"context"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/suite"
@@ -21,15 +22,16 @@ import (
 
 type ClientTest struct {
 	suite.Suite
-	userUsecase  domain.UserUsecase
-	flatUsecase  domain.FlatUsecase
-	houseUsecase domain.HouseUsecase
-	notifySender domain.NotifySender
-	userRepo     domain.UserRepo
-	flatRepo     domain.FlatRepo
-	houseRepo    domain.HouseRepo
-	notifyRepo   domain.NotifyRepo
-	skipped      bool
+	userUsecase   domain.UserUsecase
+	flatUsecase   domain.FlatUsecase
+	houseUsecase  domain.HouseUsecase
+	notifySender  domain.NotifySender
+	userRepo      domain.UserRepo
+	flatRepo      domain.FlatRepo
+	houseRepo     domain.HouseRepo
+	developerRepo domain.DeveloperRepo
+	notifyRepo    domain.NotifyRepo
+	skipped       bool
 
 	db   repo.IPool
 	lg   *zap.Logger
@@ -55,15 +57,23 @@ func (c *ClientTest) BeforeAll(t provider.T) {
 	c.userRepo = repo.NewPostrgesUserRepo(c.db, nil)
 	c.flatRepo = repo.NewPostgresFlatRepo(c.db, nil)
 	c.houseRepo = repo.NewPostgresHouseRepo(c.db, nil)
+	c.developerRepo = repo.NewPostgresDeveloperRepo(c.db, nil)
 	c.notifyRepo = repo.NewPostgresNotifyRepo(c.db, nil)
 
-	c.userUsecase = usecase.NewUserUsecase(c.userRepo)
-	c.flatUsecase = usecase.NewFlatUsecase(c.flatRepo)
+	tokenRepo := repo.NewPostgresTokenRepo(c.db, nil)
+	tokenDone := make(chan bool)
+	close(tokenDone)
+	c.userUsecase = usecase.NewUserUsecase(c.userRepo, tokenRepo,
+		usecase.NewTokenDenyList(tokenRepo, tokenDone, time.Second, time.Second, c.lg), nil)
 	c.notifySender = ports.NewSender()
 
+	flatDone := make(chan bool)
+	close(flatDone)
+	c.flatUsecase = usecase.NewFlatUsecase(c.flatRepo, time.Minute, nil, flatDone, time.Second, time.Second, c.lg)
+
 	c.done = make(chan bool, 1)
 	c.done <- true
-	c.houseUsecase = usecase.NewHouseUsecase(c.houseRepo, c.notifySender,
+	c.houseUsecase = usecase.NewHouseUsecase(c.houseRepo, c.developerRepo, c.notifySender,
 		c.notifyRepo, c.done, time.Second, time.Second, c.lg)
 
 	args := os.Args
@@ -113,7 +123,7 @@ func (c *ClientTest) TestCreateNewFlat(t provider.T) {
 		Price:       100000,
 		Rooms:       3,
 		Status:      domain.CreatedStatus,
-		ModeratorID: 0,
+		ModeratorID: uuid.Nil,
 	}
 
 	_, createdErr := c.flatUsecase.Create(ctx, registerResponse.UserID,
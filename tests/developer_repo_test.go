@@ -0,0 +1,75 @@
+//go:build unit
+// +build unit
+
+package tests
+
+import (
+	"avito-test-task/internal/domain"
+	"avito-test-task/internal/repo"
+	"avito-test-task/pkg"
+	mock_domain "avito-test-task/tests/mocks"
+	"context"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type DeveloperRepoTest struct {
+	suite.Suite
+	mockLg *zap.Logger
+}
+
+func (d *DeveloperRepoTest) BeforeAll(t provider.T) {
+	t.Log("Init log")
+	d.mockLg = pkg.CreateMockLogger()
+}
+
+func (d *DeveloperRepoTest) TestTakenNameCreateDeveloper(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowMock := mock_domain.NewMockRow(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	developerRepo := repo.NewPostgresDeveloperRepo(poolMock, retryAdapter)
+
+	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), "ПИК").Return(rowMock)
+	rowMock.EXPECT().Scan(gomock.Any()).Return(&pgconn.PgError{Code: "23505"})
+
+	_, err := developerRepo.Create(context.Background(), &domain.Developer{Name: "ПИК"}, d.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrDeveloper_NameTaken)
+}
+
+func (d *DeveloperRepoTest) TestHasHousesDeleteDeveloper(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	developerRepo := repo.NewPostgresDeveloperRepo(poolMock, retryAdapter)
+
+	poolMock.EXPECT().Exec(context.Background(), gomock.Any(), 3).
+		Return(pgconn.CommandTag{}, &pgconn.PgError{Code: "23503"})
+
+	err := developerRepo.DeleteByID(context.Background(), 3, d.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrDeveloper_HasHouses)
+}
+
+func (d *DeveloperRepoTest) TestNotFoundDeleteDeveloper(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	developerRepo := repo.NewPostgresDeveloperRepo(poolMock, retryAdapter)
+
+	poolMock.EXPECT().Exec(context.Background(), gomock.Any(), 3).Return(pgconn.NewCommandTag("DELETE 0"), nil)
+
+	err := developerRepo.DeleteByID(context.Background(), 3, d.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrDeveloper_NotFound)
+}
+
+func TestDeveloperRepoSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(DeveloperRepoTest))
+}
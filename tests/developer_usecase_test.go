@@ -0,0 +1,135 @@
+//go:build unit
+// +build unit
+
+package tests
+
+import (
+	"avito-test-task/internal/delivery/handlers"
+	"avito-test-task/internal/domain"
+	"avito-test-task/internal/usecase"
+	"avito-test-task/pkg"
+	mock_domain "avito-test-task/tests/mocks"
+	"context"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type DeveloperUsecaseTest struct {
+	suite.Suite
+	developerRepoMock *mock_domain.MockDeveloperRepo
+	houseRepoMock     *mock_domain.MockHouseRepo
+	mockLg            *zap.Logger
+}
+
+func (d *DeveloperUsecaseTest) BeforeAll(t provider.T) {
+	t.Log("Init mock")
+	ctrl := gomock.NewController(t)
+	d.developerRepoMock = mock_domain.NewMockDeveloperRepo(ctrl)
+	d.houseRepoMock = mock_domain.NewMockHouseRepo(ctrl)
+	d.mockLg = pkg.CreateMockLogger()
+}
+
+func (d *DeveloperUsecaseTest) TestNormalCreateDeveloper(t provider.T) {
+	developerUsecase := usecase.NewDeveloperUsecase(d.developerRepoMock, d.houseRepoMock)
+
+	req := domain.DeveloperRequest{Name: "  ПИК "}
+
+	d.developerRepoMock.EXPECT().Create(context.Background(), &domain.Developer{Name: "ПИК"}, d.mockLg).
+		Return(domain.Developer{DeveloperID: 1, Name: "ПИК"}, nil)
+
+	resp, err := developerUsecase.Create(context.Background(), &req, d.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(1, resp.ID)
+	t.Require().Equal("ПИК", resp.Name)
+}
+
+func (d *DeveloperUsecaseTest) TestEmptyNameCreateDeveloper(t provider.T) {
+	developerUsecase := usecase.NewDeveloperUsecase(d.developerRepoMock, d.houseRepoMock)
+
+	resp, err := developerUsecase.Create(context.Background(), &domain.DeveloperRequest{Name: " "}, d.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrDeveloper_BadName)
+	t.Require().Equal(domain.DeveloperResponse{}, resp)
+}
+
+func (d *DeveloperUsecaseTest) TestTakenNameUpdateDeveloper(t provider.T) {
+	developerUsecase := usecase.NewDeveloperUsecase(d.developerRepoMock, d.houseRepoMock)
+
+	d.developerRepoMock.EXPECT().GetByID(context.Background(), 2, d.mockLg).
+		Return(domain.Developer{DeveloperID: 2, Name: "PIK Group"}, nil)
+	d.developerRepoMock.EXPECT().Update(context.Background(), &domain.Developer{DeveloperID: 2, Name: "ПИК"}, d.mockLg).
+		Return(domain.ErrDeveloper_NameTaken)
+
+	resp, err := developerUsecase.Update(context.Background(), 2, &domain.DeveloperRequest{Name: "ПИК"}, d.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrDeveloper_NameTaken)
+	t.Require().Equal(domain.DeveloperResponse{}, resp)
+}
+
+func (d *DeveloperUsecaseTest) TestHasHousesDeleteDeveloper(t provider.T) {
+	developerUsecase := usecase.NewDeveloperUsecase(d.developerRepoMock, d.houseRepoMock)
+
+	d.developerRepoMock.EXPECT().DeleteByID(context.Background(), 3, d.mockLg).Return(domain.ErrDeveloper_HasHouses)
+
+	err := developerUsecase.DeleteByID(context.Background(), 3, d.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrDeveloper_HasHouses)
+}
+
+func (d *DeveloperUsecaseTest) TestNormalGetDeveloperHouses(t provider.T) {
+	developerUsecase := usecase.NewDeveloperUsecase(d.developerRepoMock, d.houseRepoMock)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	houses := []domain.House{
+		{HouseID: 4, Address: "ул. Ленина, 1", Developer: "ПИК", DeveloperID: 1, CreateHouseDate: now, UpdateFlatDate: now},
+		{HouseID: 9, Address: "ул. Ленина, 2", Developer: "ПИК", DeveloperID: 1, CreateHouseDate: now, UpdateFlatDate: now},
+	}
+
+	d.developerRepoMock.EXPECT().GetByID(context.Background(), 1, d.mockLg).
+		Return(domain.Developer{DeveloperID: 1, Name: "ПИК", CreatedAt: now}, nil)
+	d.houseRepoMock.EXPECT().GetByDeveloperID(context.Background(), 1, 0, 2, d.mockLg).Return(houses, nil)
+
+	resp, err := developerUsecase.GetHouses(context.Background(), 1, "", 1, d.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal("ПИК", resp.Developer.Name)
+	t.Require().Len(resp.Houses, 1)
+	t.Require().Equal(pkg.EncodeCursor(4), resp.NextCursor)
+}
+
+func (d *DeveloperUsecaseTest) TestNotFoundGetDeveloperHouses(t provider.T) {
+	developerUsecase := usecase.NewDeveloperUsecase(d.developerRepoMock, d.houseRepoMock)
+
+	d.developerRepoMock.EXPECT().GetByID(context.Background(), 100, d.mockLg).
+		Return(domain.Developer{}, domain.ErrDeveloper_NotFound)
+
+	resp, err := developerUsecase.GetHouses(context.Background(), 100, "", 0, d.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrDeveloper_NotFound)
+	t.Require().Equal(domain.DeveloperHousesResponse{}, resp)
+}
+
+func (d *DeveloperUsecaseTest) TestBadPageGetDevelopers(t provider.T) {
+	developerUsecase := usecase.NewDeveloperUsecase(d.developerRepoMock, d.houseRepoMock)
+
+	_, err := developerUsecase.GetAll(context.Background(), "", domain.MaxPageLimit+1, d.mockLg)
+	t.Require().ErrorIs(err, domain.ErrDeveloper_BadLimit)
+
+	_, err = developerUsecase.GetAll(context.Background(), "not a cursor", 0, d.mockLg)
+	t.Require().ErrorIs(err, domain.ErrDeveloper_BadCursor)
+
+	_, err = developerUsecase.GetHouses(context.Background(), 1, "not a cursor", 0, d.mockLg)
+	t.Require().ErrorIs(err, domain.ErrDeveloper_BadCursor)
+	t.Require().Equal(http.StatusBadRequest, handlers.GetReturnHTTPCode(httptest.NewRecorder(), err))
+}
+
+func TestDeveloperUsecaseSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(DeveloperUsecaseTest))
+}
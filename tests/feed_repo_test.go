@@ -0,0 +1,48 @@
+//go:build unit
+// +build unit
+
+package tests
+
+import (
+	"avito-test-task/internal/domain"
+	"avito-test-task/internal/repo"
+	"avito-test-task/pkg"
+	mock_domain "avito-test-task/tests/mocks"
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type FeedRepoTest struct {
+	suite.Suite
+	mockLg *zap.Logger
+}
+
+func (f *FeedRepoTest) BeforeAll(t provider.T) {
+	t.Log("Init log")
+	f.mockLg = pkg.CreateMockLogger()
+}
+
+func (f *FeedRepoTest) TestNotFoundGetFeedVersion(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowMock := mock_domain.NewMockRow(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	feedRepo := repo.NewPostgresFeedRepo(poolMock, retryAdapter)
+
+	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), 7).Return(rowMock)
+	rowMock.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+
+	_, err := feedRepo.GetVersion(context.Background(), domain.FeedScopeDeveloper, 7, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFeed_NotFound)
+}
+
+func TestFeedRepoSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(FeedRepoTest))
+}
@@ -0,0 +1,120 @@
+//go:build unit
+// +build unit
+
+package tests
+
+import (
+	"avito-test-task/internal/domain"
+	"avito-test-task/internal/usecase"
+	"avito-test-task/pkg"
+	mock_domain "avito-test-task/tests/mocks"
+	"context"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+type FeedUsecaseTest struct {
+	suite.Suite
+	feedRepoMock *mock_domain.MockFeedRepo
+	mockLg       *zap.Logger
+	done         chan bool
+}
+
+func (f *FeedUsecaseTest) BeforeAll(t provider.T) {
+	t.Log("Init mock")
+	ctrl := gomock.NewController(t)
+	f.feedRepoMock = mock_domain.NewMockFeedRepo(ctrl)
+	f.mockLg = pkg.CreateMockLogger()
+}
+
+func (f *FeedUsecaseTest) BeforeEach(t provider.T) {
+	f.done = make(chan bool, 1)
+	f.done <- true
+}
+
+func (f *FeedUsecaseTest) TestRegenerateOffers(t provider.T) {
+	feedUsecase := usecase.NewFeedUsecase(f.feedRepoMock, f.done, time.Second, time.Second, f.mockLg)
+
+	latitude, longitude := 55.75, 37.61
+	house := domain.House{HouseID: 1, Address: "Москва, ул. Тверская, 1", ConstructYear: 2020,
+		Developer: "ПИК", Latitude: &latitude, Longitude: &longitude}
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	flats := []domain.FeedFlat{
+		{FlatID: 1, HouseID: 1, Price: 5000000, Rooms: 2, Status: domain.ApprovedStatus,
+			CreatedAt: createdAt, House: house, Exists: true},
+		{FlatID: 2, HouseID: 1, Price: 3000000, Rooms: 1, Status: domain.DeclinedStatus,
+			CreatedAt: createdAt, House: house, Exists: true},
+		{FlatID: 3, HouseID: 1, Price: 0, Rooms: 1, Status: domain.ApprovedStatus,
+			CreatedAt: createdAt, House: house, Exists: true},
+	}
+
+	var saved []domain.FeedOffer
+	f.feedRepoMock.EXPECT().GetChangedFlats(context.Background(), domain.FeedChangesBatch, f.mockLg).Return(flats, nil)
+	f.feedRepoMock.EXPECT().SaveOffers(context.Background(), flats, gomock.Any(), f.mockLg).
+		DoAndReturn(func(ctx context.Context, flats []domain.FeedFlat, offers []domain.FeedOffer, lg *zap.Logger) error {
+			saved = offers
+			return nil
+		})
+
+	regenerated, err := feedUsecase.RegenerateOffers(context.Background(), f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(3, regenerated)
+	t.Require().Len(saved, 6)
+
+	yandex, avito := saved[0], saved[1]
+	t.Require().Equal(domain.FeedFormatYandex, yandex.Format)
+	t.Require().Contains(yandex.Offer, `<offer internal-id="1-1">`)
+	t.Require().Contains(yandex.Offer, "<address>Москва, ул. Тверская, 1</address>")
+	t.Require().Contains(yandex.Offer, "<value>5000000</value>")
+	t.Require().Contains(yandex.Offer, "<organization>ПИК</organization>")
+	t.Require().Equal(domain.FeedFormatAvito, avito.Format)
+	t.Require().Contains(avito.Offer, "<Id>1-1</Id>")
+	t.Require().Contains(avito.Offer, "<MarketType>Новостройка</MarketType>")
+	t.Require().Contains(avito.Offer, "<Rooms>2</Rooms>")
+
+	for _, offer := range saved[2:] {
+		t.Require().Equal("", offer.Offer)
+	}
+}
+
+func (f *FeedUsecaseTest) TestStreamFeed(t provider.T) {
+	feedUsecase := usecase.NewFeedUsecase(f.feedRepoMock, f.done, time.Second, time.Second, f.mockLg)
+
+	req := domain.FeedRequest{Format: domain.FeedFormatAvito, Scope: domain.FeedScopeDeveloper, ID: 3}
+	version := time.Date(2026, 10, 17, 11, 0, 0, 0, time.UTC)
+	f.feedRepoMock.EXPECT().GetVersion(context.Background(), domain.FeedScopeDeveloper, 3, f.mockLg).Return(version, nil)
+	f.feedRepoMock.EXPECT().StreamOffers(context.Background(), domain.FeedFormatAvito, domain.FeedScopeDeveloper, 3,
+		gomock.Any(), f.mockLg).
+		DoAndReturn(func(ctx context.Context, format string, scope string, id int, handle func(string) error, lg *zap.Logger) error {
+			return handle("<Ad><Id>1-1</Id></Ad>\n")
+		})
+
+	var feed strings.Builder
+	err := feedUsecase.Stream(context.Background(), &req, func(chunk string) error {
+		feed.WriteString(chunk)
+		return nil
+	}, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().True(strings.HasPrefix(feed.String(), `<?xml version="1.0" encoding="UTF-8"?>`))
+	t.Require().Contains(feed.String(), `<Ads formatVersion="3" target="Avito.ru">`+"\n<Ad><Id>1-1</Id></Ad>\n</Ads>\n")
+}
+
+func (f *FeedUsecaseTest) TestBadFormatGetFeedVersion(t provider.T) {
+	feedUsecase := usecase.NewFeedUsecase(f.feedRepoMock, f.done, time.Second, time.Second, f.mockLg)
+
+	req := domain.FeedRequest{Format: "cian", Scope: domain.FeedScopeHouse, ID: 1}
+	_, err := feedUsecase.GetVersion(context.Background(), &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFeed_BadFormat)
+}
+
+func TestFeedUsecaseSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(FeedUsecaseTest))
+}
@@ -16,6 +16,7 @@ import (
 	"go.uber.org/zap"
 	"os"
 	"testing"
+	"time"
 )
 
 type FlatIntegrationTest struct {
@@ -39,8 +40,10 @@ func (f *FlatIntegrationTest) BeforeAll(t provider.T) {
 		t.Fatalf("error while connecting to db: %v", err.Error())
 	}
 	f.flatRepo = repo.NewPostgresFlatRepo(f.db, nil)
-	f.flatUsecase = usecase.NewFlatUsecase(f.flatRepo)
 	f.mockLg = pkg.CreateMockLogger()
+	done := make(chan bool)
+	close(done)
+	f.flatUsecase = usecase.NewFlatUsecase(f.flatRepo, time.Minute, nil, done, time.Second, time.Second, f.mockLg)
 	f.flatMother = &FlatMother{}
 
 	args := os.Args
@@ -137,6 +140,12 @@ func (f *FlatIntegrationTest) TestNormalUpdate(t provider.T) {
 		t.Skip()
 	}
 
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	takeFlat := domain.UpdateFlatRequest{
+		ID:      1,
+		HouseID: 1,
+		Status:  domain.ModeratingStatus,
+	}
 	updFlat := domain.UpdateFlatRequest{
 		ID:      1,
 		HouseID: 1,
@@ -151,12 +160,33 @@ func (f *FlatIntegrationTest) TestNormalUpdate(t provider.T) {
 		Status:  domain.ApprovedStatus,
 	}
 
+	_, takeErr := f.flatUsecase.Update(context.Background(), moderatorID, &takeFlat, f.mockLg)
+	updated, err := f.flatUsecase.Update(context.Background(), moderatorID, &updFlat, f.mockLg)
+
+	t.Require().Nil(takeErr)
+	t.Require().Nil(err)
+	t.Require().Equal(expected, updated)
+}
+
+func (f *FlatIntegrationTest) TestIllegalTransitionUpdate(t provider.T) {
+	if f.skipped {
+		t.Skip()
+	}
+
+	updFlat := domain.UpdateFlatRequest{
+		ID:      9,
+		HouseID: 5,
+		Status:  domain.ApprovedStatus,
+	}
+
 	updated, err := f.flatUsecase.Update(context.Background(),
 		uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"),
 		&updFlat, f.mockLg)
 
-	t.Require().Nil(err)
-	t.Require().Equal(expected, updated)
+	var transitionErr *domain.FlatTransitionError
+	t.Require().ErrorAs(err, &transitionErr)
+	t.Require().Equal(domain.CreatedStatus, transitionErr.CurrentStatus)
+	t.Require().Equal(domain.CreateFlatResponse{}, updated)
 }
 
 func (f *FlatIntegrationTest) TestNilRequestUpdate(t provider.T) {
@@ -15,7 +15,7 @@ func (f *FlatMother) DefaultFlat(id int, houseID int) domain.Flat {
 		Price:       10000,
 		Rooms:       2,
 		Status:      domain.CreatedStatus,
-		ModeratorID: 1,
+		ModeratorID: uuid.Nil,
 	}
 }
 
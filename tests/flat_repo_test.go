@@ -50,7 +50,7 @@ func (f *FlatRepoTest) TestNormalCreateFlat(t provider.T) {
 		Price:       10000000,
 		Rooms:       2,
 		Status:      domain.CreatedStatus,
-		ModeratorID: 1,
+		ModeratorID: uuid.Nil,
 	}
 
 	query := `insert into flats(flat_id, house_id, user_id, price, rooms, status)
@@ -85,7 +85,7 @@ func (f *FlatRepoTest) TestContextTimeoutCreateFlat(t provider.T) {
 		Price:       10000000,
 		Rooms:       2,
 		Status:      domain.CreatedStatus,
-		ModeratorID: 1,
+		ModeratorID: uuid.Nil,
 	}
 	ctx, _ := context.WithTimeout(context.Background(), time.Millisecond)
 	time.Sleep(time.Millisecond)
@@ -96,6 +96,32 @@ func (f *FlatRepoTest) TestContextTimeoutCreateFlat(t provider.T) {
 	t.Require().Error(err)
 }
 
+func (f *FlatRepoTest) TestNormalCopyFlats(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	txMock := mock_domain.NewMockTx(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
+
+	userID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	flats := []domain.Flat{
+		{ID: 1, HouseID: 3, UserID: userID, Price: 1000, Rooms: 2, Status: domain.CreatedStatus},
+		{ID: 2, HouseID: 3, UserID: userID, Price: 2000, Rooms: 3, Status: domain.CreatedStatus},
+	}
+	execQuery := `update houses set update_flat_date=$1 where house_id = any($2)`
+
+	poolMock.EXPECT().BeginTx(context.Background(), gomock.Any()).Return(txMock, nil)
+	txMock.EXPECT().CopyFrom(context.Background(), pgx.Identifier{"flats"},
+		[]string{"flat_id", "house_id", "user_id", "price", "rooms"}, gomock.Any()).Return(int64(2), nil)
+	txMock.EXPECT().Exec(context.Background(), execQuery, gomock.Any(), []int{3})
+	txMock.EXPECT().Commit(context.Background())
+
+	copied, err := flatRepo.CopyFlats(context.Background(), flats, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(int64(2), copied)
+}
+
 func (f *FlatRepoTest) TestNormalDeleteByIdFlat(t provider.T) {
 	ctrl := gomock.NewController(t)
 	poolMock := mock_domain.NewMockIPool(ctrl)
@@ -111,7 +137,7 @@ func (f *FlatRepoTest) TestNormalDeleteByIdFlat(t provider.T) {
 		Price:       10000000,
 		Rooms:       2,
 		Status:      domain.CreatedStatus,
-		ModeratorID: 1,
+		ModeratorID: uuid.Nil,
 	}
 	query := `delete from flats where flat_id=$1 and house_id=$2`
 	cTag := pgconn.CommandTag{}
@@ -147,7 +173,7 @@ func (f *FlatRepoTest) TestNormalGetByIdFlat(t provider.T) {
 	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
 	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
 
-	query := `select flat_id, house_id, user_id, price, rooms, status
+	query := `select flat_id, house_id, user_id, price, rooms, status, moderator_id
 	from flats where flat_id=$1 and house_id=$2`
 
 	poolMock.EXPECT().QueryRow(context.Background(), query, 1, 1).Return(rowsMock)
@@ -165,12 +191,13 @@ func (f *FlatRepoTest) TestNormalGetAllFlat(t provider.T) {
 
 	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
 	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
-	query := `select flat_id, house_id, user_id, price, rooms, status from flats limit $1 offset $2`
-	poolMock.EXPECT().Query(context.Background(), query, 10, 0).Return(rowsMock, nil)
+	query := `select flat_id, house_id, user_id, price, rooms, status from flats
+	where (house_id, flat_id) > ($1, $2) order by house_id, flat_id limit $3`
+	poolMock.EXPECT().Query(context.Background(), query, 0, 0, 10).Return(rowsMock, nil)
 	rowsMock.EXPECT().Next()
 	rowsMock.EXPECT().Close()
 
-	_, err := flatRepo.GetAll(context.Background(), 0, 10, f.mockLg)
+	_, err := flatRepo.GetAll(context.Background(), domain.FlatCursor{}, 10, f.mockLg)
 
 	t.Require().Nil(err)
 }
@@ -183,17 +210,183 @@ func (f *FlatRepoTest) TestContextTimeoutGetAllFlat(t provider.T) {
 	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
 	ctx, _ := context.WithTimeout(context.Background(), 1)
 	time.Sleep(1)
-	query := `select flat_id, house_id, user_id, price, rooms, status from flats limit $1 offset $2`
+	query := `select flat_id, house_id, user_id, price, rooms, status from flats
+	where (house_id, flat_id) > ($1, $2) order by house_id, flat_id limit $3`
 
-	poolMock.EXPECT().Query(ctx, query, 1, 1).Return(rowsMock, errors.New("expired context"))
+	poolMock.EXPECT().Query(ctx, query, 1, 1, 1).Return(rowsMock, errors.New("expired context"))
 	rowsMock.EXPECT().Close().MinTimes(1)
 
-	flats, err := flatRepo.GetAll(ctx, 1, 1, f.mockLg)
+	flats, err := flatRepo.GetAll(ctx, domain.FlatCursor{HouseID: 1, FlatID: 1}, 1, f.mockLg)
+
+	t.Require().Error(err)
+	t.Require().Equal(len(flats), 0)
+}
+
+func (f *FlatRepoTest) TestNormalSearchFlat(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowsMock := mock_domain.NewMockRows(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
+
+	filter := domain.FlatFilter{
+		MaxPrice: 8000000,
+		Rooms:    2,
+		Status:   domain.ApprovedStatus,
+		SortBy:   domain.SortByPrice,
+		Order:    domain.DescOrder,
+		Limit:    10,
+	}
+	query := `select f.flat_id, f.house_id, f.user_id, f.price, f.rooms, f.status,
+		coalesce(f.previous_price, 0), f.price_dropped_at
+	from flats f join houses h on f.house_id = h.house_id where f.status=$1 and f.price<=$2 and f.rooms=$3 order by f.price desc, f.house_id, f.flat_id limit $4 offset $5`
+
+	poolMock.EXPECT().Query(context.Background(), query, domain.ApprovedStatus, 8000000, 2, 10, 0).Return(rowsMock, nil)
+	rowsMock.EXPECT().Next().Return(false)
+	rowsMock.EXPECT().Err().Return(nil)
+	rowsMock.EXPECT().Close()
+
+	_, err := flatRepo.Search(context.Background(), &filter, f.mockLg)
+
+	t.Require().Nil(err)
+}
+
+func (f *FlatRepoTest) TestContextTimeoutSearchFlat(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
+	ctx, cancel := context.WithTimeout(context.Background(), 1)
+	defer cancel()
+	time.Sleep(1)
+
+	filter := domain.FlatFilter{Status: domain.AnyStatus, Limit: 10}
+	poolMock.EXPECT().Query(ctx, gomock.Any(), 10, 0).Return(nil, errors.New("expired context"))
+
+	flats, err := flatRepo.Search(ctx, &filter, f.mockLg)
 
 	t.Require().Error(err)
 	t.Require().Equal(len(flats), 0)
 }
 
+func (f *FlatRepoTest) TestEmptyQueueClaimNextFlat(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowMock := mock_domain.NewMockRow(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
+
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+
+	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), domain.ModeratingStatus,
+		moderatorID, 60).Return(rowMock)
+	rowMock.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+
+	_, err := flatRepo.ClaimNext(context.Background(), moderatorID, time.Minute, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_QueueEmpty)
+}
+
+func (f *FlatRepoTest) TestNormalReclaimExpiredLeases(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
+
+	query := `update flats set status=$1, moderator_id=null, lease_expires_at=null,
+		status_actor_id=null, status_comment=$3
+	where status=$2 and lease_expires_at < now()`
+
+	poolMock.EXPECT().Exec(context.Background(), query, domain.CreatedStatus,
+		domain.ModeratingStatus, domain.LeaseExpiredComment).Return(pgconn.NewCommandTag("UPDATE 2"), nil)
+
+	reclaimed, err := flatRepo.ReclaimExpiredLeases(context.Background(), f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(int64(2), reclaimed)
+}
+
+func (f *FlatRepoTest) TestLeaseNotHeldExtendLease(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowMock := mock_domain.NewMockRow(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
+
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	flat := domain.Flat{
+		ID:      2,
+		HouseID: 1,
+	}
+
+	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), 60, 2, 1,
+		domain.ModeratingStatus, moderatorID).Return(rowMock)
+	rowMock.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+
+	_, err := flatRepo.ExtendLease(context.Background(), moderatorID, &flat, time.Minute, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_LeaseNotHeld)
+}
+
+func (f *FlatRepoTest) TestContextTimeoutGetStatusHistory(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
+	ctx, cancel := context.WithTimeout(context.Background(), 1)
+	defer cancel()
+	time.Sleep(1)
+
+	poolMock.EXPECT().Query(ctx, gomock.Any(), 2, 1).Return(nil, errors.New("expired context"))
+
+	history, err := flatRepo.GetStatusHistory(ctx, 2, 1, f.mockLg)
+
+	t.Require().Error(err)
+	t.Require().Equal(len(history), 0)
+}
+
+func (f *FlatRepoTest) TestContextTimeoutGetPriceHistory(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
+	ctx, cancel := context.WithTimeout(context.Background(), 1)
+	defer cancel()
+	time.Sleep(1)
+
+	poolMock.EXPECT().Query(ctx, gomock.Any(), 2, 1).Return(nil, errors.New("expired context"))
+
+	history, err := flatRepo.GetPriceHistory(ctx, 2, 1, f.mockLg)
+
+	t.Require().Error(err)
+	t.Require().Equal(len(history), 0)
+}
+
+func (f *FlatRepoTest) TestConflictUpdateByOwnerFlat(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowMock := mock_domain.NewMockRow(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	flatRepo := repo.NewPostgresFlatRepo(poolMock, retryAdapter)
+
+	ownerID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db24")
+	flat := domain.Flat{
+		ID:      2,
+		HouseID: 1,
+		Price:   170,
+		Rooms:   3,
+		Status:  domain.CreatedStatus,
+	}
+
+	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), 170, 3, domain.CreatedStatus,
+		2, 1, ownerID, domain.ApprovedStatus).Return(rowMock)
+	rowMock.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+
+	_, err := flatRepo.UpdateByOwner(context.Background(), ownerID, &flat, domain.ApprovedStatus, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_Conflict)
+}
+
 func TestFlatSuiteRunner(t *testing.T) {
 	suite.RunSuite(t, new(FlatRepoTest))
 }
@@ -4,6 +4,7 @@
 package tests
 
 import (
+	"avito-test-task/internal/delivery/handlers"
 	"avito-test-task/internal/domain"
 	"avito-test-task/internal/usecase"
 	"avito-test-task/pkg"
@@ -15,13 +16,25 @@ import (
 	"github.com/ozontech/allure-go/pkg/framework/suite"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 type FlatUsecaseTest struct {
 	suite.Suite
 	flatRepoMock *mock_domain.MockFlatRepo
 	mockLg       *zap.Logger
+	done         chan bool
+}
+
+const testLeaseTTL = time.Minute
+
+var testDeclineReasons = map[string]string{
+	"wrong-price": "Price does not match the listing",
+	"other":       "Other reason, see text",
 }
 
 func (f *FlatUsecaseTest) BeforeAll(t provider.T) {
@@ -29,10 +42,12 @@ func (f *FlatUsecaseTest) BeforeAll(t provider.T) {
 	ctrl := gomock.NewController(t)
 	f.flatRepoMock = mock_domain.NewMockFlatRepo(ctrl)
 	f.mockLg = pkg.CreateMockLogger()
+	f.done = make(chan bool)
+	close(f.done)
 }
 
 func (f *FlatUsecaseTest) TestNormalCreateFlat(t provider.T) {
-	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock)
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
 	userID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28")
 	flat := domain.Flat{
 		ID:      1000,
@@ -66,7 +81,7 @@ func (f *FlatUsecaseTest) TestNormalCreateFlat(t provider.T) {
 }
 
 func (f *FlatUsecaseTest) TestEmptyRequestCreateFlat(t provider.T) {
-	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock)
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
 	created, err := userUsecase.Create(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28"), nil, f.mockLg)
 
 	t.Require().Error(err)
@@ -74,7 +89,7 @@ func (f *FlatUsecaseTest) TestEmptyRequestCreateFlat(t provider.T) {
 }
 
 func (f *FlatUsecaseTest) TestBadFlatIDCreateFlat(t provider.T) {
-	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock)
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
 	req := domain.CreateFlatRequest{
 		FlatID:  0,
 		HouseID: 4,
@@ -89,7 +104,7 @@ func (f *FlatUsecaseTest) TestBadFlatIDCreateFlat(t provider.T) {
 }
 
 func (f *FlatUsecaseTest) TestBadHouseIDCreateFlat(t provider.T) {
-	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock)
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
 	req := domain.CreateFlatRequest{
 		FlatID:  10,
 		HouseID: 0,
@@ -104,7 +119,7 @@ func (f *FlatUsecaseTest) TestBadHouseIDCreateFlat(t provider.T) {
 }
 
 func (f *FlatUsecaseTest) TestBadRoomsCreateFlat(t provider.T) {
-	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock)
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
 	req := domain.CreateFlatRequest{
 		FlatID:  10,
 		HouseID: 10,
@@ -119,7 +134,7 @@ func (f *FlatUsecaseTest) TestBadRoomsCreateFlat(t provider.T) {
 }
 
 func (f *FlatUsecaseTest) TestBadRepoCallCreateFlat(t provider.T) {
-	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock)
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
 	userID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28")
 	flat := domain.Flat{
 		ID:      1000,
@@ -146,7 +161,7 @@ func (f *FlatUsecaseTest) TestBadRepoCallCreateFlat(t provider.T) {
 }
 
 func (f *FlatUsecaseTest) TestNormalUpdateFlat(t provider.T) {
-	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock)
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
 	userID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28")
 	flat := domain.Flat{
 		ID:      1000,
@@ -165,7 +180,9 @@ func (f *FlatUsecaseTest) TestNormalUpdateFlat(t provider.T) {
 		Status:  domain.ModeratingStatus,
 	}
 
-	f.flatRepoMock.EXPECT().Update(gomock.Any(), userID, &flat, f.mockLg).Return(flat, nil)
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 1000, 4, f.mockLg).
+		Return(domain.Flat{ID: 1000, HouseID: 4, Status: domain.CreatedStatus}, nil)
+	f.flatRepoMock.EXPECT().Update(gomock.Any(), userID, &flat, domain.CreatedStatus, testLeaseTTL, f.mockLg).Return(flat, nil)
 
 	upd, err := userUsecase.Update(context.Background(), userID, &req, f.mockLg)
 
@@ -174,7 +191,7 @@ func (f *FlatUsecaseTest) TestNormalUpdateFlat(t provider.T) {
 }
 
 func (f *FlatUsecaseTest) TestBadStatusUpdateFlat(t provider.T) {
-	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock)
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
 	userID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28")
 
 	req := domain.UpdateFlatRequest{
@@ -190,14 +207,14 @@ func (f *FlatUsecaseTest) TestBadStatusUpdateFlat(t provider.T) {
 }
 
 func (f *FlatUsecaseTest) TestBadRepoCallUpdateFlat(t provider.T) {
-	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock)
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
 	userID := uuid.Nil
 	flat := domain.Flat{
 		ID:          1000,
 		UserID:      userID,
 		HouseID:     4,
 		Status:      domain.ApprovedStatus,
-		ModeratorID: 0,
+		ModeratorID: uuid.Nil,
 	}
 
 	req := domain.UpdateFlatRequest{
@@ -206,7 +223,10 @@ func (f *FlatUsecaseTest) TestBadRepoCallUpdateFlat(t provider.T) {
 		Status:  domain.ApprovedStatus,
 	}
 
-	f.flatRepoMock.EXPECT().Update(gomock.Any(), userID, &flat, f.mockLg).Return(domain.Flat{}, errors.New("flat repo error"))
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 1000, 4, f.mockLg).
+		Return(domain.Flat{ID: 1000, HouseID: 4, Status: domain.ModeratingStatus}, nil)
+	f.flatRepoMock.EXPECT().Update(gomock.Any(), userID, &flat, domain.ModeratingStatus, testLeaseTTL, f.mockLg).
+		Return(domain.Flat{}, errors.New("flat repo error"))
 
 	created, err := userUsecase.Update(context.Background(), userID, &req, f.mockLg)
 
@@ -214,6 +234,719 @@ func (f *FlatUsecaseTest) TestBadRepoCallUpdateFlat(t provider.T) {
 	t.Require().Equal(domain.CreateFlatResponse{}, created)
 }
 
+func (f *FlatUsecaseTest) TestAnyStatusUpdateFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+
+	req := domain.UpdateFlatRequest{
+		ID:      1000,
+		HouseID: 4,
+		Status:  domain.AnyStatus,
+	}
+
+	upd, err := userUsecase.Update(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28"), &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_BadStatus)
+	t.Require().Equal(domain.CreateFlatResponse{}, upd)
+}
+
+func (f *FlatUsecaseTest) TestIllegalTransitionUpdateFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+
+	req := domain.UpdateFlatRequest{
+		ID:      1001,
+		HouseID: 4,
+		Status:  domain.ApprovedStatus,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 1001, 4, f.mockLg).
+		Return(domain.Flat{ID: 1001, HouseID: 4, Status: domain.CreatedStatus}, nil)
+
+	upd, err := userUsecase.Update(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28"), &req, f.mockLg)
+
+	var transitionErr *domain.FlatTransitionError
+	t.Require().ErrorIs(err, domain.ErrFlat_IllegalTransition)
+	t.Require().ErrorAs(err, &transitionErr)
+	t.Require().Equal(domain.CreatedStatus, transitionErr.CurrentStatus)
+	t.Require().Equal(domain.CreateFlatResponse{}, upd)
+}
+
+func (f *FlatUsecaseTest) TestApprovedToCreatedUpdateFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+
+	req := domain.UpdateFlatRequest{
+		ID:      1005,
+		HouseID: 4,
+		Status:  domain.CreatedStatus,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 1005, 4, f.mockLg).
+		Return(domain.Flat{ID: 1005, HouseID: 4, Status: domain.ApprovedStatus}, nil)
+
+	_, err := userUsecase.Update(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28"), &req, f.mockLg)
+
+	var transitionErr *domain.FlatTransitionError
+	t.Require().ErrorAs(err, &transitionErr)
+	t.Require().Equal(domain.ApprovedStatus, transitionErr.CurrentStatus)
+	t.Require().Equal(http.StatusConflict, handlers.GetReturnHTTPCode(httptest.NewRecorder(), err))
+}
+
+func (f *FlatUsecaseTest) TestOnModerationByAnotherUpdateFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+
+	req := domain.UpdateFlatRequest{
+		ID:      1002,
+		HouseID: 4,
+		Status:  domain.ApprovedStatus,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 1002, 4, f.mockLg).Return(domain.Flat{
+		ID:          1002,
+		HouseID:     4,
+		Status:      domain.ModeratingStatus,
+		ModeratorID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db33"),
+	}, nil)
+
+	upd, err := userUsecase.Update(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"), &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_OnModeration)
+	t.Require().Equal(domain.CreateFlatResponse{}, upd)
+}
+
+func (f *FlatUsecaseTest) TestNormalSearchFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	flats := []domain.Flat{
+		{ID: 1, HouseID: 4, Price: 1000, Rooms: 2, Status: domain.ApprovedStatus},
+		{ID: 7, HouseID: 5, Price: 2000, Rooms: 2, Status: domain.ApprovedStatus},
+	}
+
+	req := domain.SearchFlatRequest{
+		MaxPrice: 8000000,
+		Rooms:    2,
+	}
+	filter := domain.FlatFilter{
+		MaxPrice: 8000000,
+		Rooms:    2,
+		Status:   domain.ApprovedStatus,
+		SortBy:   domain.SortByPrice,
+		Order:    domain.AscOrder,
+		Limit:    domain.DefaultSearchLimit,
+	}
+	resp := domain.SearchFlatResponse{
+		Flats: []domain.SingleFlatResponse{
+			{ID: 1, HouseID: 4, Price: 1000, Rooms: 2, Status: domain.ApprovedStatus},
+			{ID: 7, HouseID: 5, Price: 2000, Rooms: 2, Status: domain.ApprovedStatus},
+		},
+	}
+
+	f.flatRepoMock.EXPECT().Search(gomock.Any(), &filter, f.mockLg).Return(flats, nil)
+
+	found, err := userUsecase.Search(context.Background(), &req, domain.ApprovedStatus, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(resp, found)
+}
+
+func (f *FlatUsecaseTest) TestBadPriceRangeSearchFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	req := domain.SearchFlatRequest{
+		MinPrice: 9000,
+		MaxPrice: 1000,
+	}
+
+	found, err := userUsecase.Search(context.Background(), &req, domain.ApprovedStatus, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_BadPriceRange)
+	t.Require().Equal(domain.SearchFlatResponse{}, found)
+}
+
+func (f *FlatUsecaseTest) TestBadSortFieldSearchFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	req := domain.SearchFlatRequest{
+		SortBy: "user_id",
+	}
+
+	found, err := userUsecase.Search(context.Background(), &req, domain.ApprovedStatus, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_BadSortField)
+	t.Require().Equal(domain.SearchFlatResponse{}, found)
+}
+
+func (f *FlatUsecaseTest) TestNormalEditApprovedFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	ownerID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db24")
+	price := 7500000
+	current := domain.Flat{
+		ID:      2,
+		HouseID: 1,
+		UserID:  ownerID,
+		Price:   8000000,
+		Rooms:   3,
+		Status:  domain.ApprovedStatus,
+	}
+	edited := domain.Flat{
+		ID:      2,
+		HouseID: 1,
+		UserID:  ownerID,
+		Price:   price,
+		Rooms:   3,
+		Status:  domain.CreatedStatus,
+	}
+
+	req := domain.EditFlatRequest{
+		ID:      2,
+		HouseID: 1,
+		Price:   &price,
+	}
+	resp := domain.CreateFlatResponse{
+		ID:      2,
+		HouseID: 1,
+		Price:   price,
+		Rooms:   3,
+		Status:  domain.CreatedStatus,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 2, 1, f.mockLg).Return(current, nil)
+	f.flatRepoMock.EXPECT().UpdateByOwner(gomock.Any(), ownerID, &edited, domain.ApprovedStatus, f.mockLg).Return(edited, nil)
+
+	upd, err := userUsecase.Edit(context.Background(), ownerID, &req, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(resp, upd)
+}
+
+func (f *FlatUsecaseTest) TestNotOwnerEditFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	rooms := 4
+	current := domain.Flat{
+		ID:      3,
+		HouseID: 1,
+		UserID:  uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db24"),
+		Price:   200,
+		Rooms:   2,
+		Status:  domain.CreatedStatus,
+	}
+
+	req := domain.EditFlatRequest{
+		ID:      3,
+		HouseID: 1,
+		Rooms:   &rooms,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 3, 1, f.mockLg).Return(current, nil)
+
+	upd, err := userUsecase.Edit(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db25"), &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_NotOwner)
+	t.Require().Equal(domain.CreateFlatResponse{}, upd)
+}
+
+func (f *FlatUsecaseTest) TestOnModerationEditFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	ownerID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db25")
+	rooms := 3
+	current := domain.Flat{
+		ID:          4,
+		HouseID:     2,
+		UserID:      ownerID,
+		Price:       250,
+		Rooms:       4,
+		Status:      domain.ModeratingStatus,
+		ModeratorID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"),
+	}
+
+	req := domain.EditFlatRequest{
+		ID:      4,
+		HouseID: 2,
+		Rooms:   &rooms,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 4, 2, f.mockLg).Return(current, nil)
+
+	upd, err := userUsecase.Edit(context.Background(), ownerID, &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_OnModeration)
+	t.Require().Equal(domain.CreateFlatResponse{}, upd)
+}
+
+func (f *FlatUsecaseTest) TestNormalWithdrawFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	ownerID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db26")
+	current := domain.Flat{
+		ID:      5,
+		HouseID: 2,
+		UserID:  ownerID,
+		Price:   300,
+		Rooms:   1,
+		Status:  domain.ApprovedStatus,
+	}
+	sold := current
+	sold.Status = domain.SoldStatus
+	sold.StatusComment = "sold by owner"
+
+	req := domain.UpdateFlatRequest{
+		ID:      5,
+		HouseID: 2,
+		Status:  domain.SoldStatus,
+		Comment: "sold by owner",
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 5, 2, f.mockLg).Return(current, nil)
+	f.flatRepoMock.EXPECT().UpdateStatus(gomock.Any(), ownerID, &sold, domain.ApprovedStatus, f.mockLg).Return(sold, nil)
+
+	upd, err := userUsecase.Withdraw(context.Background(), ownerID, domain.Client, &req, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(domain.SoldStatus, upd.Status)
+}
+
+func (f *FlatUsecaseTest) TestNotOwnerWithdrawFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	current := domain.Flat{
+		ID:      6,
+		HouseID: 3,
+		UserID:  uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db27"),
+		Status:  domain.ApprovedStatus,
+	}
+
+	req := domain.UpdateFlatRequest{
+		ID:      6,
+		HouseID: 3,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 6, 3, f.mockLg).Return(current, nil)
+
+	upd, err := userUsecase.Withdraw(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28"),
+		domain.Client, &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_NotOwner)
+	t.Require().Equal(domain.CreateFlatResponse{}, upd)
+}
+
+func (f *FlatUsecaseTest) TestModeratorRestoreFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	current := domain.Flat{
+		ID:      7,
+		HouseID: 4,
+		UserID:  uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28"),
+		Price:   400,
+		Rooms:   3,
+		Status:  domain.ArchivedStatus,
+	}
+	restored := current
+	restored.Status = domain.CreatedStatus
+
+	req := domain.UpdateFlatRequest{
+		ID:      7,
+		HouseID: 4,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 7, 4, f.mockLg).Return(current, nil)
+	f.flatRepoMock.EXPECT().UpdateStatus(gomock.Any(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"),
+		&restored, domain.ArchivedStatus, f.mockLg).Return(restored, nil)
+
+	upd, err := userUsecase.Restore(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"),
+		domain.Moderator, &req, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(domain.CreatedStatus, upd.Status)
+}
+
+func (f *FlatUsecaseTest) TestNotWithdrawnRestoreFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	ownerID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db29")
+	current := domain.Flat{
+		ID:      8,
+		HouseID: 4,
+		UserID:  ownerID,
+		Status:  domain.CreatedStatus,
+	}
+
+	req := domain.UpdateFlatRequest{
+		ID:      8,
+		HouseID: 4,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 8, 4, f.mockLg).Return(current, nil)
+
+	upd, err := userUsecase.Restore(context.Background(), ownerID, domain.Client, &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_NotWithdrawn)
+	t.Require().Equal(domain.CreateFlatResponse{}, upd)
+}
+
+func (f *FlatUsecaseTest) TestNormalClaimNextFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	claimed := domain.Flat{
+		ID:          9,
+		HouseID:     4,
+		Price:       1000,
+		Rooms:       2,
+		Status:      domain.ModeratingStatus,
+		ModeratorID: moderatorID,
+	}
+
+	f.flatRepoMock.EXPECT().ClaimNext(gomock.Any(), moderatorID, testLeaseTTL, f.mockLg).Return(claimed, nil)
+
+	resp, err := userUsecase.ClaimNext(context.Background(), moderatorID, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(9, resp.ID)
+	t.Require().Equal(domain.ModeratingStatus, resp.Status)
+}
+
+func (f *FlatUsecaseTest) TestEmptyQueueClaimNextFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+
+	f.flatRepoMock.EXPECT().ClaimNext(gomock.Any(), moderatorID, testLeaseTTL, f.mockLg).Return(domain.Flat{}, domain.ErrFlat_QueueEmpty)
+
+	resp, err := userUsecase.ClaimNext(context.Background(), moderatorID, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_QueueEmpty)
+	t.Require().Equal(domain.CreateFlatResponse{}, resp)
+}
+
+func (f *FlatUsecaseTest) TestNormalExtendLease(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	leaseExpiresAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	flat := domain.Flat{
+		ID:      9,
+		HouseID: 4,
+	}
+	extended := domain.Flat{
+		ID:             9,
+		HouseID:        4,
+		Status:         domain.ModeratingStatus,
+		ModeratorID:    moderatorID,
+		LeaseExpiresAt: leaseExpiresAt,
+	}
+
+	req := domain.UpdateFlatRequest{
+		ID:      9,
+		HouseID: 4,
+	}
+
+	f.flatRepoMock.EXPECT().ExtendLease(gomock.Any(), moderatorID, &flat, testLeaseTTL, f.mockLg).Return(extended, nil)
+
+	resp, err := userUsecase.ExtendLease(context.Background(), moderatorID, &req, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(leaseExpiresAt, *resp.LeaseExpiresAt)
+}
+
+func (f *FlatUsecaseTest) TestLeaseNotHeldExtendLease(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	flat := domain.Flat{
+		ID:      9,
+		HouseID: 4,
+	}
+
+	req := domain.UpdateFlatRequest{
+		ID:      9,
+		HouseID: 4,
+	}
+
+	f.flatRepoMock.EXPECT().ExtendLease(gomock.Any(), moderatorID, &flat, testLeaseTTL, f.mockLg).
+		Return(domain.Flat{}, domain.ErrFlat_LeaseNotHeld)
+
+	resp, err := userUsecase.ExtendLease(context.Background(), moderatorID, &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_LeaseNotHeld)
+	t.Require().Equal(domain.CreateFlatResponse{}, resp)
+}
+
+func (f *FlatUsecaseTest) TestNormalReclaimingLeases(t provider.T) {
+	ctrl := gomock.NewController(t)
+	flatRepoMock := mock_domain.NewMockFlatRepo(ctrl)
+	done := make(chan bool, 1)
+	reclaimed := make(chan bool)
+
+	flatRepoMock.EXPECT().ReclaimExpiredLeases(gomock.Any(), f.mockLg).DoAndReturn(
+		func(ctx context.Context, lg *zap.Logger) (int64, error) {
+			done <- true
+			close(reclaimed)
+			return 1, nil
+		})
+
+	_ = usecase.NewFlatUsecase(flatRepoMock, testLeaseTTL, nil, done, time.Millisecond, time.Second, f.mockLg)
+
+	<-reclaimed
+}
+
+func (f *FlatUsecaseTest) TestDeclineUpdateFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	reason := domain.DeclineReason{Code: "wrong-price", Text: "price is ten times below the market"}
+	flat := domain.Flat{
+		ID:            1000,
+		HouseID:       4,
+		Status:        domain.DeclinedStatus,
+		DeclineReason: reason,
+	}
+
+	req := domain.UpdateFlatRequest{
+		ID:      1000,
+		HouseID: 4,
+		Status:  domain.DeclinedStatus,
+		Reason:  &reason,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 1000, 4, f.mockLg).
+		Return(domain.Flat{ID: 1000, HouseID: 4, Status: domain.ModeratingStatus, ModeratorID: moderatorID}, nil)
+	f.flatRepoMock.EXPECT().Update(gomock.Any(), moderatorID, &flat, domain.ModeratingStatus, testLeaseTTL, f.mockLg).Return(flat, nil)
+
+	upd, err := userUsecase.Update(context.Background(), moderatorID, &req, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(domain.DeclinedStatus, upd.Status)
+	t.Require().Equal(&reason, upd.DeclineReason)
+}
+
+func (f *FlatUsecaseTest) TestNoReasonDeclineUpdateFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+
+	req := domain.UpdateFlatRequest{
+		ID:      1000,
+		HouseID: 4,
+		Status:  domain.DeclinedStatus,
+	}
+
+	upd, err := userUsecase.Update(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"), &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_NoDeclineReason)
+	t.Require().Equal(domain.CreateFlatResponse{}, upd)
+}
+
+func (f *FlatUsecaseTest) TestUnknownReasonDeclineUpdateFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+
+	req := domain.UpdateFlatRequest{
+		ID:      1000,
+		HouseID: 4,
+		Status:  domain.DeclinedStatus,
+		Reason:  &domain.DeclineReason{Code: "bad-mood"},
+	}
+
+	upd, err := userUsecase.Update(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"), &req, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_BadDeclineReason)
+	t.Require().Equal(domain.CreateFlatResponse{}, upd)
+}
+
+func (f *FlatUsecaseTest) TestOwnerGetStatusHistory(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	ownerID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db26")
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	current := domain.Flat{
+		ID:      5,
+		HouseID: 2,
+		UserID:  ownerID,
+		Status:  domain.DeclinedStatus,
+	}
+	history := []domain.FlatStatusChange{
+		{ID: 1, FlatID: 5, HouseID: 2, NewStatus: domain.CreatedStatus, ActorID: ownerID},
+		{ID: 2, FlatID: 5, HouseID: 2, OldStatus: domain.CreatedStatus, NewStatus: domain.ModeratingStatus, ActorID: moderatorID},
+		{ID: 3, FlatID: 5, HouseID: 2, OldStatus: domain.ModeratingStatus, NewStatus: domain.DeclinedStatus,
+			ActorID: moderatorID, Comment: "no photos"},
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 5, 2, f.mockLg).Return(current, nil)
+	f.flatRepoMock.EXPECT().GetStatusHistory(gomock.Any(), 5, 2, f.mockLg).Return(history, nil)
+
+	resp, err := userUsecase.GetStatusHistory(context.Background(), ownerID, domain.Client, 5, 2, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Len(resp.History, 3)
+	t.Require().Equal(domain.ModeratingStatus, resp.History[2].OldStatus)
+	t.Require().Equal(moderatorID, resp.History[2].ActorID)
+	t.Require().Equal("no photos", resp.History[2].Comment)
+}
+
+func (f *FlatUsecaseTest) TestNotOwnerGetStatusHistory(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	current := domain.Flat{
+		ID:      5,
+		HouseID: 2,
+		UserID:  uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db26"),
+		Status:  domain.ApprovedStatus,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 5, 2, f.mockLg).Return(current, nil)
+
+	resp, err := userUsecase.GetStatusHistory(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28"),
+		domain.Client, 5, 2, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_NotOwner)
+	t.Require().Equal(domain.FlatStatusHistoryResponse{}, resp)
+}
+
+func (f *FlatUsecaseTest) TestPriceDropSearchFlat(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	droppedAt := time.Now().Add(-24 * time.Hour)
+	flats := []domain.Flat{
+		{ID: 1, HouseID: 4, Price: 1000, Rooms: 2, Status: domain.ApprovedStatus,
+			PreviousPrice: 1200, PriceDroppedAt: droppedAt},
+		{ID: 7, HouseID: 5, Price: 2000, Rooms: 2, Status: domain.ApprovedStatus,
+			PreviousPrice: 2500, PriceDroppedAt: time.Now().Add(-domain.RecentPriceDropPeriod - time.Hour)},
+	}
+
+	f.flatRepoMock.EXPECT().Search(gomock.Any(), gomock.Any(), f.mockLg).Return(flats, nil)
+
+	found, err := userUsecase.Search(context.Background(), &domain.SearchFlatRequest{}, domain.ApprovedStatus, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Len(found.Flats, 2)
+	t.Require().Equal(&domain.PriceDropResponse{PreviousPrice: 1200, DroppedAt: droppedAt}, found.Flats[0].PriceDrop)
+	t.Require().Nil(found.Flats[1].PriceDrop)
+}
+
+func (f *FlatUsecaseTest) TestApprovedGetPriceHistory(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	changedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	current := domain.Flat{
+		ID:      5,
+		HouseID: 2,
+		UserID:  uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db26"),
+		Price:   900,
+		Status:  domain.ApprovedStatus,
+	}
+	history := []domain.FlatPriceChange{
+		{ID: 1, FlatID: 5, HouseID: 2, Price: 1000, ChangedAt: changedAt},
+		{ID: 2, FlatID: 5, HouseID: 2, Price: 900, ChangedAt: changedAt.Add(time.Hour)},
+	}
+	resp := domain.FlatPriceHistoryResponse{
+		ID:      5,
+		HouseID: 2,
+		History: []domain.FlatPriceChangeResponse{
+			{Price: 1000, ChangedAt: changedAt},
+			{Price: 900, ChangedAt: changedAt.Add(time.Hour)},
+		},
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 5, 2, f.mockLg).Return(current, nil)
+	f.flatRepoMock.EXPECT().GetPriceHistory(gomock.Any(), 5, 2, f.mockLg).Return(history, nil)
+
+	found, err := userUsecase.GetPriceHistory(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28"),
+		domain.Client, 5, 2, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(resp, found)
+}
+
+func (f *FlatUsecaseTest) TestNotVisibleGetPriceHistory(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	current := domain.Flat{
+		ID:      5,
+		HouseID: 2,
+		UserID:  uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db26"),
+		Status:  domain.CreatedStatus,
+	}
+
+	f.flatRepoMock.EXPECT().GetByID(gomock.Any(), 5, 2, f.mockLg).Return(current, nil)
+
+	resp, err := userUsecase.GetPriceHistory(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db28"),
+		domain.Client, 5, 2, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_NotFound)
+	t.Require().Equal(domain.FlatPriceHistoryResponse{}, resp)
+}
+
+func (f *FlatUsecaseTest) TestCSVImportFlats(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	file := strings.NewReader("flat_id,house_id,price,rooms\n" +
+		"1,4,1000,2\n" +
+		"2,4,1000,0\n" +
+		"1,4,1500,3\n" +
+		"3,9,1000,1\n" +
+		"4,4,1000,1\n" +
+		"5,4,2000,3\n")
+	checked := []domain.Flat{
+		{ID: 1, HouseID: 4, UserID: moderatorID, Price: 1000, Rooms: 2, Status: domain.CreatedStatus},
+		{ID: 3, HouseID: 9, UserID: moderatorID, Price: 1000, Rooms: 1, Status: domain.CreatedStatus},
+		{ID: 4, HouseID: 4, UserID: moderatorID, Price: 1000, Rooms: 1, Status: domain.CreatedStatus},
+		{ID: 5, HouseID: 4, UserID: moderatorID, Price: 2000, Rooms: 3, Status: domain.CreatedStatus},
+	}
+	conflicts := domain.FlatImportConflicts{
+		ExistingFlats: map[domain.FlatKey]bool{{FlatID: 4, HouseID: 4}: true},
+		MissingHouses: map[int]bool{9: true},
+	}
+	copied := []domain.Flat{checked[0], checked[3]}
+
+	f.flatRepoMock.EXPECT().FindImportConflicts(gomock.Any(), checked, f.mockLg).Return(conflicts, nil)
+	f.flatRepoMock.EXPECT().CopyFlats(gomock.Any(), copied, f.mockLg).Return(int64(2), nil)
+
+	report, err := userUsecase.Import(context.Background(), moderatorID, domain.ImportFormatCSV, file, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(6, report.Total)
+	t.Require().Equal(2, report.Imported)
+	t.Require().Equal([]domain.ImportRowError{
+		{Line: 3, Error: domain.ErrFlat_BadRooms.Error()},
+		{Line: 4, Error: domain.ErrImport_DuplicateRow.Error()},
+		{Line: 5, Error: domain.ErrHouse_NotFound.Error()},
+		{Line: 6, Error: domain.ErrFlat_Exists.Error()},
+	}, report.Errors)
+}
+
+func (f *FlatUsecaseTest) TestBadQuoteImportFlats(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	moderatorID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23")
+	file := strings.NewReader("flat_id,house_id,price,rooms\n" +
+		"1,4,10\"00,2\n" +
+		"2,4,1000,1\n")
+	checked := []domain.Flat{
+		{ID: 2, HouseID: 4, UserID: moderatorID, Price: 1000, Rooms: 1, Status: domain.CreatedStatus},
+	}
+
+	f.flatRepoMock.EXPECT().FindImportConflicts(gomock.Any(), checked, f.mockLg).Return(domain.FlatImportConflicts{}, nil)
+	f.flatRepoMock.EXPECT().CopyFlats(gomock.Any(), checked, f.mockLg).Return(int64(1), nil)
+
+	report, err := userUsecase.Import(context.Background(), moderatorID, domain.ImportFormatCSV, file, f.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(2, report.Total)
+	t.Require().Equal(1, report.Imported)
+	t.Require().Len(report.Errors, 1)
+	t.Require().Equal(2, report.Errors[0].Line)
+	t.Require().True(strings.HasPrefix(report.Errors[0].Error, domain.ErrImport_BadRow.Error()))
+}
+
+func (f *FlatUsecaseTest) TestBadQuoteHeaderImportFlats(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	file := strings.NewReader("flat_id,\"house_id,price,rooms\n1,4,1000,2\n")
+
+	_, err := userUsecase.Import(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"),
+		domain.ImportFormatCSV, file, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrImport_BadHeader)
+	t.Require().Equal(http.StatusBadRequest, handlers.GetReturnHTTPCode(httptest.NewRecorder(), err))
+}
+
+func (f *FlatUsecaseTest) TestBadHeaderImportFlats(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+	file := strings.NewReader("flat_id,house_id,price\n1,4,1000\n")
+
+	report, err := userUsecase.Import(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"),
+		domain.ImportFormatCSV, file, f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrImport_BadHeader)
+	t.Require().Equal(domain.ImportResponse{}, report)
+}
+
+func (f *FlatUsecaseTest) TestBadFormatImportFlats(t provider.T) {
+	userUsecase := usecase.NewFlatUsecase(f.flatRepoMock, testLeaseTTL, testDeclineReasons, f.done, time.Second, time.Second, f.mockLg)
+
+	report, err := userUsecase.Import(context.Background(), uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db23"),
+		"xml", strings.NewReader("<flats/>"), f.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrImport_BadFormat)
+	t.Require().Equal(domain.ImportResponse{}, report)
+}
+
 func TestFlatUsecaseSuiteRunner(t *testing.T) {
 	suite.RunSuite(t, new(FlatUsecaseTest))
 }
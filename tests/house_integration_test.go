@@ -22,13 +22,14 @@ import (
 
 type HouseIntegrationTest struct {
 	suite.Suite
-	houseUsecase domain.HouseUsecase
-	houseRepo    domain.HouseRepo
-	notifyRepo   domain.NotifyRepo
-	notifySender domain.NotifySender
-	db           repo.IPool
-	mockLg       *zap.Logger
-	skipped      bool
+	houseUsecase  domain.HouseUsecase
+	houseRepo     domain.HouseRepo
+	developerRepo domain.DeveloperRepo
+	notifyRepo    domain.NotifyRepo
+	notifySender  domain.NotifySender
+	db            repo.IPool
+	mockLg        *zap.Logger
+	skipped       bool
 }
 
 func (h *HouseIntegrationTest) BeforeAll(t provider.T) {
@@ -44,10 +45,11 @@ func (h *HouseIntegrationTest) BeforeAll(t provider.T) {
 	h.notifyRepo = repo.NewPostgresNotifyRepo(h.db, nil)
 	h.notifySender = ports.NewSender()
 	h.houseRepo = repo.NewPostgresHouseRepo(h.db, nil)
+	h.developerRepo = repo.NewPostgresDeveloperRepo(h.db, nil)
 
 	done := make(chan bool, 1)
 	h.mockLg = pkg.CreateMockLogger()
-	h.houseUsecase = usecase.NewHouseUsecase(h.houseRepo, h.notifySender, h.notifyRepo, done, time.Minute, time.Minute, h.mockLg)
+	h.houseUsecase = usecase.NewHouseUsecase(h.houseRepo, h.developerRepo, h.notifySender, h.notifyRepo, done, time.Minute, time.Minute, h.mockLg)
 
 	args := os.Args
 	for _, arg := range args {
@@ -168,7 +170,7 @@ func (h *HouseIntegrationTest) TestNormalGetFlatsByHouseID(t provider.T) {
 
 	expected := domain.FlatsByHouseResponse{Flats: expectedFlats}
 
-	flats, err := h.houseUsecase.GetFlatsByHouseID(context.Background(), 4, domain.ModeratingStatus, h.mockLg)
+	flats, err := h.houseUsecase.GetFlatsByHouseID(context.Background(), 4, domain.ModeratingStatus, "", 0, h.mockLg)
 
 	t.Require().Nil(err)
 	t.Require().Equal(expected, flats)
@@ -179,7 +181,7 @@ func (h *HouseIntegrationTest) TestBadIDGetFlatsByHouseID(t provider.T) {
 		t.Skip()
 	}
 
-	flats, err := h.houseUsecase.GetFlatsByHouseID(context.Background(), -1, domain.ModeratingStatus, h.mockLg)
+	flats, err := h.houseUsecase.GetFlatsByHouseID(context.Background(), -1, domain.ModeratingStatus, "", 0, h.mockLg)
 
 	t.Require().Error(err)
 	t.Require().Equal(domain.FlatsByHouseResponse{}, flats)
@@ -190,7 +192,7 @@ func (h *HouseIntegrationTest) TestBadStatusGetFlatsByHouseID(t provider.T) {
 		t.Skip()
 	}
 
-	flats, err := h.houseUsecase.GetFlatsByHouseID(context.Background(), 1, "bad status", h.mockLg)
+	flats, err := h.houseUsecase.GetFlatsByHouseID(context.Background(), 1, "bad status", "", 0, h.mockLg)
 
 	t.Require().Error(err)
 	t.Require().Equal(domain.FlatsByHouseResponse{}, flats)
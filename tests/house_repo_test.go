@@ -16,6 +16,7 @@ import (
 	"github.com/ozontech/allure-go/pkg/framework/suite"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"math"
 	"testing"
 	"time"
 )
@@ -47,7 +48,8 @@ func (h *HouseRepoTest) TestNormalCreateHouse(t provider.T) {
 		UpdateFlatDate:  now,
 	}
 	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(),
-		house.Address, house.ConstructYear, house.Developer, house.CreateHouseDate, house.UpdateFlatDate).Return(rowMock)
+		house.Address, house.ConstructYear, house.Developer, house.CreateHouseDate, house.UpdateFlatDate,
+		house.Latitude, house.Longitude, house.DeveloperID).Return(rowMock)
 	rowMock.EXPECT().Scan(gomock.Any()).Return(nil)
 
 	_, err := houseRepo.Create(context.Background(), &house, h.mockLg)
@@ -74,7 +76,8 @@ func (h *HouseRepoTest) TestContextTimeoutCreateHouse(t provider.T) {
 		UpdateFlatDate:  now,
 	}
 	poolMock.EXPECT().QueryRow(ctx, gomock.Any(),
-		house.Address, house.ConstructYear, house.Developer, house.CreateHouseDate, house.UpdateFlatDate).Return(rowMock)
+		house.Address, house.ConstructYear, house.Developer, house.CreateHouseDate, house.UpdateFlatDate,
+		house.Latitude, house.Longitude, house.DeveloperID).Return(rowMock)
 	rowMock.EXPECT().Scan(gomock.Any()).Return(errors.New("expired context"))
 
 	_, err := houseRepo.Create(ctx, &house, h.mockLg)
@@ -136,7 +139,8 @@ func (h *HouseRepoTest) TestNormalUpdateHouse(t provider.T) {
 	}
 	poolMock.EXPECT().Exec(context.Background(), gomock.Any(),
 		house.HouseID, house.Address, house.ConstructYear, house.Developer, house.CreateHouseDate,
-		house.UpdateFlatDate).Return(pgconn.CommandTag{}, nil)
+		house.UpdateFlatDate, house.Latitude, house.Longitude,
+		house.DeveloperID).Return(pgconn.CommandTag{}, nil)
 
 	err := houseRepo.Update(context.Background(), &house, h.mockLg)
 
@@ -161,7 +165,8 @@ func (h *HouseRepoTest) TestContextTimeoutUpdateHouse(t provider.T) {
 		UpdateFlatDate:  now,
 	}
 	poolMock.EXPECT().Exec(ctx, gomock.Any(), house.HouseID, house.Address, house.ConstructYear,
-		house.Developer, house.CreateHouseDate, house.UpdateFlatDate).Return(pgconn.CommandTag{}, errors.New("expired context"))
+		house.Developer, house.CreateHouseDate, house.UpdateFlatDate, house.Latitude, house.Longitude,
+		house.DeveloperID).Return(pgconn.CommandTag{}, errors.New("expired context"))
 
 	err := houseRepo.Update(ctx, &house, h.mockLg)
 
@@ -212,7 +217,7 @@ func (h *HouseRepoTest) TestNormalGetAll(t provider.T) {
 		{HouseID: 4, Address: "ул. Спортивная, д. 4", ConstructYear: 2022, Developer: "ЗАО Новострой", CreateHouseDate: now, UpdateFlatDate: now},
 		{HouseID: 5, Address: "ул. Спортивная, д. 5", ConstructYear: 2021, Developer: "OOO Строй", CreateHouseDate: now, UpdateFlatDate: now},
 	}
-	poolMock.EXPECT().Query(context.Background(), gomock.Any(), 5, 0).Return(rowsMock, nil)
+	poolMock.EXPECT().Query(context.Background(), gomock.Any(), 0, 5).Return(rowsMock, nil)
 	rowsMock.EXPECT().Next()
 	rowsMock.EXPECT().Close().AnyTimes()
 
@@ -229,7 +234,7 @@ func (h *HouseRepoTest) TestContextTimeoutGetAll(t provider.T) {
 	houseRepo := repo.NewPostgresHouseRepo(poolMock, retryAdapter)
 	ctx, _ := context.WithTimeout(context.Background(), 1)
 	time.Sleep(1)
-	poolMock.EXPECT().Query(ctx, gomock.Any(), 10, 0).Return(rowsMock, errors.New("expired context"))
+	poolMock.EXPECT().Query(ctx, gomock.Any(), 0, 10).Return(rowsMock, errors.New("expired context"))
 	rowsMock.EXPECT().Close()
 
 	_, err := houseRepo.GetAll(ctx, 0, 10, h.mockLg)
@@ -237,6 +242,122 @@ func (h *HouseRepoTest) TestContextTimeoutGetAll(t provider.T) {
 	t.Require().Error(err)
 }
 
+func (h *HouseRepoTest) TestNormalSearchHouses(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowsMock := mock_domain.NewMockRows(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	houseRepo := repo.NewPostgresHouseRepo(poolMock, retryAdapter)
+	after := time.Now().UTC().Truncate(time.Second)
+	filter := domain.HouseFilter{
+		Address: "50%_off",
+		MinYear: 2000,
+		SortBy:  domain.HouseSortByUpdated,
+		Order:   domain.DescOrder,
+		After:   domain.HouseCursor{Date: after, HouseID: 7},
+		Limit:   11,
+	}
+	poolMock.EXPECT().Query(context.Background(), gomock.Any(), 2000, `%50\%\_off%`, after, 7, 11).Return(rowsMock, nil)
+	rowsMock.EXPECT().Next()
+	rowsMock.EXPECT().Err()
+	rowsMock.EXPECT().Close().AnyTimes()
+
+	_, err := houseRepo.Search(context.Background(), &filter, h.mockLg)
+
+	t.Require().Nil(err)
+}
+
+func (h *HouseRepoTest) TestNormalSearchHousesByAddress(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowsMock := mock_domain.NewMockRows(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	houseRepo := repo.NewPostgresHouseRepo(poolMock, retryAdapter)
+	poolMock.EXPECT().Query(context.Background(), gomock.Any(), "ул Ленина", "ул Ленина", 20).Return(rowsMock, nil)
+	rowsMock.EXPECT().Next()
+	rowsMock.EXPECT().Err()
+	rowsMock.EXPECT().Close().AnyTimes()
+
+	_, err := houseRepo.SearchByAddress(context.Background(), "ул Ленина", "ул Ленина", 20, h.mockLg)
+
+	t.Require().Nil(err)
+}
+
+func (h *HouseRepoTest) TestNormalGetNearbyHouses(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowsMock := mock_domain.NewMockRows(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	houseRepo := repo.NewPostgresHouseRepo(poolMock, retryAdapter)
+	center := domain.GeoPoint{Latitude: 55.7558, Longitude: 37.6173}
+	poolMock.EXPECT().Query(context.Background(), gomock.Any(), center.Latitude, center.Longitude, domain.EarthRadiusKm,
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), 2.0, 10).Return(rowsMock, nil)
+	rowsMock.EXPECT().Next()
+	rowsMock.EXPECT().Err()
+	rowsMock.EXPECT().Close().AnyTimes()
+
+	_, err := houseRepo.GetNearby(context.Background(), center, 2, 10, h.mockLg)
+
+	t.Require().Nil(err)
+}
+
+func (h *HouseRepoTest) TestEdgeGetNearbyHouses(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowsMock := mock_domain.NewMockRows(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	houseRepo := repo.NewPostgresHouseRepo(poolMock, retryAdapter)
+	center := domain.GeoPoint{Latitude: 55.75, Longitude: 37.6173}
+
+	// The easternmost point of a 499.5 km circle lies just inside the 500 km search radius.
+	lat := center.Latitude * math.Pi / 180
+	edge := 499.5 / domain.EarthRadiusKm
+	houseLon := center.Longitude + math.Asin(math.Sin(edge)/math.Cos(lat))*180/math.Pi
+	houseLat := math.Asin(math.Sin(lat)/math.Cos(edge)) * 180 / math.Pi
+
+	poolMock.EXPECT().Query(context.Background(), gomock.Any(), center.Latitude, center.Longitude, domain.EarthRadiusKm,
+		gomock.Cond(func(x any) bool { return x.(float64) <= houseLat }),
+		gomock.Cond(func(x any) bool { return x.(float64) >= houseLat }),
+		gomock.Cond(func(x any) bool { return x.(float64) <= 2*center.Longitude-houseLon }),
+		gomock.Cond(func(x any) bool { return x.(float64) >= houseLon }),
+		500.0, 10).Return(rowsMock, nil)
+	rowsMock.EXPECT().Next()
+	rowsMock.EXPECT().Err()
+	rowsMock.EXPECT().Close().AnyTimes()
+
+	_, err := houseRepo.GetNearby(context.Background(), center, 500, 10, h.mockLg)
+
+	t.Require().Nil(err)
+}
+
+func (h *HouseRepoTest) TestApprovedGetHouseStats(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowMock := mock_domain.NewMockRow(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	houseRepo := repo.NewPostgresHouseRepo(poolMock, retryAdapter)
+	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), 6, domain.ApprovedStatus).Return(rowMock)
+	rowMock.EXPECT().Scan(gomock.Any()).Return(nil)
+
+	_, err := houseRepo.GetStats(context.Background(), 6, domain.ApprovedStatus, h.mockLg)
+
+	t.Require().Nil(err)
+}
+
+func (h *HouseRepoTest) TestModeratingGetHouseStats(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowMock := mock_domain.NewMockRow(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	houseRepo := repo.NewPostgresHouseRepo(poolMock, retryAdapter)
+	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), 6).Return(rowMock)
+	rowMock.EXPECT().Scan(gomock.Any()).Return(nil)
+
+	_, err := houseRepo.GetStats(context.Background(), 6, domain.AnyStatus, h.mockLg)
+
+	t.Require().Nil(err)
+}
+
 func (h *HouseRepoTest) TestNormalNonModeratingGetFlatsByHouseID(t provider.T) {
 	ctrl := gomock.NewController(t)
 	poolMock := mock_domain.NewMockIPool(ctrl)
@@ -244,17 +365,17 @@ func (h *HouseRepoTest) TestNormalNonModeratingGetFlatsByHouseID(t provider.T) {
 	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
 	houseRepo := repo.NewPostgresHouseRepo(poolMock, retryAdapter)
 	_ = []domain.Flat{
-		{ID: 10, HouseID: 1, UserID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db22"), Price: 100, Rooms: 2, Status: "created", ModeratorID: 0},
-		{ID: 1, HouseID: 1, UserID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db22"), Price: 100, Rooms: 2, Status: "created", ModeratorID: 0},
-		{ID: 2, HouseID: 1, UserID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db24"), Price: 150, Rooms: 3, Status: "approved", ModeratorID: 0},
-		{ID: 3, HouseID: 1, UserID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db24"), Price: 200, Rooms: 2, Status: "declined", ModeratorID: 0},
+		{ID: 10, HouseID: 1, UserID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db22"), Price: 100, Rooms: 2, Status: "created", ModeratorID: uuid.Nil},
+		{ID: 1, HouseID: 1, UserID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db22"), Price: 100, Rooms: 2, Status: "created", ModeratorID: uuid.Nil},
+		{ID: 2, HouseID: 1, UserID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db24"), Price: 150, Rooms: 3, Status: "approved", ModeratorID: uuid.Nil},
+		{ID: 3, HouseID: 1, UserID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db24"), Price: 200, Rooms: 2, Status: "declined", ModeratorID: uuid.Nil},
 	}
 
-	poolMock.EXPECT().Query(context.Background(), gomock.Any(), 1).Return(rowsMock, nil)
+	poolMock.EXPECT().Query(context.Background(), gomock.Any(), 1, 0, 10).Return(rowsMock, nil)
 	rowsMock.EXPECT().Next()
 	rowsMock.EXPECT().Close().MinTimes(1)
 
-	_, err := houseRepo.GetFlatsByHouseID(context.Background(), 1, domain.AnyStatus, h.mockLg)
+	_, err := houseRepo.GetFlatsByHouseID(context.Background(), 1, domain.AnyStatus, 0, 10, h.mockLg)
 
 	t.Require().Nil(err)
 }
@@ -266,11 +387,11 @@ func (h *HouseRepoTest) TestNormalModeratingGetFlatsByHouseID(t provider.T) {
 	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
 	houseRepo := repo.NewPostgresHouseRepo(poolMock, retryAdapter)
 
-	poolMock.EXPECT().Query(context.Background(), gomock.Any(), 2, domain.ModeratingStatus).Return(rowsMock, nil)
+	poolMock.EXPECT().Query(context.Background(), gomock.Any(), 2, 0, domain.ModeratingStatus, 10).Return(rowsMock, nil)
 	rowsMock.EXPECT().Next()
 	rowsMock.EXPECT().Close()
 
-	_, err := houseRepo.GetFlatsByHouseID(context.Background(), 2, domain.ModeratingStatus, h.mockLg)
+	_, err := houseRepo.GetFlatsByHouseID(context.Background(), 2, domain.ModeratingStatus, 0, 10, h.mockLg)
 
 	t.Require().Nil(err)
 }
@@ -284,10 +405,10 @@ func (h *HouseRepoTest) TestContextTimeoutGetFlatsByHouseID(t provider.T) {
 	ctx, _ := context.WithTimeout(context.Background(), 1)
 	time.Sleep(1)
 
-	poolMock.EXPECT().Query(ctx, gomock.Any(), 2, domain.ModeratingStatus).Return(rowsMock, errors.New("expired context"))
+	poolMock.EXPECT().Query(ctx, gomock.Any(), 2, 0, domain.ModeratingStatus, 10).Return(rowsMock, errors.New("expired context"))
 	rowsMock.EXPECT().Close()
 
-	_, err := houseRepo.GetFlatsByHouseID(ctx, 2, domain.ModeratingStatus, h.mockLg)
+	_, err := houseRepo.GetFlatsByHouseID(ctx, 2, domain.ModeratingStatus, 0, 10, h.mockLg)
 
 	t.Require().Error(err)
 }
@@ -4,6 +4,7 @@
 package tests
 
 import (
+	"avito-test-task/internal/delivery/handlers"
 	"avito-test-task/internal/domain"
 	"avito-test-task/internal/ports"
 	"avito-test-task/internal/usecase"
@@ -16,22 +17,27 @@ import (
 	"github.com/ozontech/allure-go/pkg/framework/suite"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
 
 type HouseUsecaseTest struct {
 	suite.Suite
-	houseRepoMock  *mock_domain.MockHouseRepo
-	notifyRepoMock *mock_domain.MockNotifyRepo
-	mockLg         *zap.Logger
-	flatMother     *FlatMother
+	houseRepoMock     *mock_domain.MockHouseRepo
+	developerRepoMock *mock_domain.MockDeveloperRepo
+	notifyRepoMock    *mock_domain.MockNotifyRepo
+	mockLg            *zap.Logger
+	flatMother        *FlatMother
 }
 
 func (h *HouseUsecaseTest) BeforeAll(t provider.T) {
 	t.Log("Init mock")
 	ctrl := gomock.NewController(t)
 	h.houseRepoMock = mock_domain.NewMockHouseRepo(ctrl)
+	h.developerRepoMock = mock_domain.NewMockDeveloperRepo(ctrl)
 	h.notifyRepoMock = mock_domain.NewMockNotifyRepo(ctrl)
 	h.mockLg = pkg.CreateMockLogger()
 	h.flatMother = new(FlatMother)
@@ -41,7 +47,7 @@ func (h *HouseUsecaseTest) TestNormalCreateHouse(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	req := domain.CreateHouseRequest{
 		HomeID:    2,
@@ -68,6 +74,8 @@ func (h *HouseUsecaseTest) TestNormalCreateHouse(t provider.T) {
 		UpdateFlatDate:  time.Time{},
 	}
 
+	h.developerRepoMock.EXPECT().GetOrCreateByName(context.Background(), "ООО ТестСтрой", h.mockLg).
+		Return(domain.Developer{DeveloperID: 5, Name: "ООО ТестСтрой"}, nil)
 	h.houseRepoMock.EXPECT().Create(context.Background(), gomock.Any(), h.mockLg).Return(house, nil)
 
 	created, err := houseUsecase.Create(context.Background(), &req, h.mockLg)
@@ -83,7 +91,7 @@ func (h *HouseUsecaseTest) TestNilRequestCreateHouse(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	created, err := houseUsecase.Create(context.Background(), nil, h.mockLg)
 
@@ -95,7 +103,7 @@ func (h *HouseUsecaseTest) TestBadYearCreateHouse(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	req := domain.CreateHouseRequest{
 		HomeID:    0,
@@ -114,7 +122,7 @@ func (h *HouseUsecaseTest) TestBadDeveloperCreateHouse(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	req := domain.CreateHouseRequest{
 		HomeID:    0,
@@ -133,7 +141,7 @@ func (h *HouseUsecaseTest) TestBadAddressCreateHouse(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	req := domain.CreateHouseRequest{
 		HomeID:    0,
@@ -152,7 +160,7 @@ func (h *HouseUsecaseTest) TestBadRepoCallCreateHouse(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	req := domain.CreateHouseRequest{
 		HomeID:    2,
@@ -161,6 +169,8 @@ func (h *HouseUsecaseTest) TestBadRepoCallCreateHouse(t provider.T) {
 		Developer: "ООО ТестСтрой",
 	}
 
+	h.developerRepoMock.EXPECT().GetOrCreateByName(context.Background(), "ООО ТестСтрой", h.mockLg).
+		Return(domain.Developer{DeveloperID: 5, Name: "ООО ТестСтрой"}, nil)
 	h.houseRepoMock.EXPECT().Create(context.Background(), gomock.Any(), h.mockLg).Return(domain.House{}, errors.New("error"))
 
 	created, err := houseUsecase.Create(context.Background(), &req, h.mockLg)
@@ -173,7 +183,7 @@ func (h *HouseUsecaseTest) TestNormalGetFlatsByHouseID(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	flats := []domain.Flat{}
 	singleFlats := []domain.SingleFlatResponse{}
@@ -184,11 +194,11 @@ func (h *HouseUsecaseTest) TestNormalGetFlatsByHouseID(t provider.T) {
 		singleFlats = append(singleFlats, singleFlatResponse)
 	}
 
-	resp := domain.FlatsByHouseResponse{singleFlats}
+	resp := domain.FlatsByHouseResponse{Flats: singleFlats}
 
-	h.houseRepoMock.EXPECT().GetFlatsByHouseID(context.Background(), 10, domain.CreatedStatus, h.mockLg).Return(flats, nil)
+	h.houseRepoMock.EXPECT().GetFlatsByHouseID(context.Background(), 10, domain.CreatedStatus, 0, domain.DefaultPageLimit+1, h.mockLg).Return(flats, nil)
 
-	foundFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), 10, domain.CreatedStatus, h.mockLg)
+	foundFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), 10, domain.CreatedStatus, "", 0, h.mockLg)
 
 	t.Require().Nil(err)
 	t.Require().Equal(resp, foundFlats)
@@ -198,16 +208,16 @@ func (h *HouseUsecaseTest) TestBadRepoCallGetFlatsByHouseID(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	flats := []domain.Flat{}
 	for i := 1; i < 6; i++ {
 		flats = append(flats, h.flatMother.DefaultFlat(i, 10))
 	}
 
-	h.houseRepoMock.EXPECT().GetFlatsByHouseID(context.Background(), 10, domain.CreatedStatus, h.mockLg).Return([]domain.Flat{}, errors.New("error"))
+	h.houseRepoMock.EXPECT().GetFlatsByHouseID(context.Background(), 10, domain.CreatedStatus, 0, domain.DefaultPageLimit+1, h.mockLg).Return([]domain.Flat{}, errors.New("error"))
 
-	foundFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), 10, domain.CreatedStatus, h.mockLg)
+	foundFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), 10, domain.CreatedStatus, "", 0, h.mockLg)
 
 	t.Require().Error(err)
 	t.Require().Equal(domain.FlatsByHouseResponse{}, foundFlats)
@@ -217,9 +227,9 @@ func (h *HouseUsecaseTest) TestBadIDGetFlatsByHouseID(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
-	foundedFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), -1, domain.CreatedStatus, h.mockLg)
+	foundedFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), -1, domain.CreatedStatus, "", 0, h.mockLg)
 
 	t.Require().Error(err)
 	t.Require().Equal(domain.FlatsByHouseResponse{}, foundedFlats)
@@ -229,19 +239,105 @@ func (h *HouseUsecaseTest) TestBadStatusGetFlatsByHouseID(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
-	foundedFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), 10, "test", h.mockLg)
+	foundedFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), 10, "test", "", 0, h.mockLg)
 
 	t.Require().Error(err)
 	t.Require().Equal(domain.FlatsByHouseResponse{}, foundedFlats)
 }
 
+func (h *HouseUsecaseTest) TestNextPageGetFlatsByHouseID(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	flats := []domain.Flat{}
+	singleFlats := []domain.SingleFlatResponse{}
+	for i := 4; i < 7; i++ {
+		newFlat := h.flatMother.DefaultFlat(i, 11)
+		flats = append(flats, newFlat)
+		singleFlats = append(singleFlats, h.flatMother.DefaultSingleFlatResponse(&newFlat))
+	}
+
+	resp := domain.FlatsByHouseResponse{
+		Flats:      singleFlats[:2],
+		NextCursor: pkg.EncodeCursor(11, 5),
+	}
+
+	h.houseRepoMock.EXPECT().GetFlatsByHouseID(context.Background(), 11, domain.AnyStatus, 3, 3, h.mockLg).Return(flats, nil)
+
+	foundFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), 11, domain.AnyStatus, pkg.EncodeCursor(11, 3), 2, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(resp, foundFlats)
+}
+
+func (h *HouseUsecaseTest) TestBadCursorGetFlatsByHouseID(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	foundFlats, err := houseUsecase.GetFlatsByHouseID(context.Background(), 11, domain.AnyStatus, pkg.EncodeCursor(12, 3), 2, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_BadCursor)
+	t.Require().Equal(domain.FlatsByHouseResponse{}, foundFlats)
+}
+
+func (h *HouseUsecaseTest) TestNormalStreamFlatsByHouseID(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	flats := []domain.Flat{}
+	expected := []domain.SingleFlatResponse{}
+	for i := 1; i < 4; i++ {
+		newFlat := h.flatMother.DefaultFlat(i, 12)
+		flats = append(flats, newFlat)
+		expected = append(expected, h.flatMother.DefaultSingleFlatResponse(&newFlat))
+	}
+
+	h.houseRepoMock.EXPECT().StreamFlatsByHouseID(context.Background(), 12, domain.ApprovedStatus, gomock.Any(), h.mockLg).
+		DoAndReturn(func(ctx context.Context, id int, status string, handle func(flat domain.Flat) error, lg *zap.Logger) error {
+			for _, flat := range flats {
+				if err := handle(flat); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	var streamed []domain.SingleFlatResponse
+	err := houseUsecase.StreamFlatsByHouseID(context.Background(), 12, domain.ApprovedStatus, func(flat domain.SingleFlatResponse) error {
+		streamed = append(streamed, flat)
+		return nil
+	}, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(expected, streamed)
+}
+
+func (h *HouseUsecaseTest) TestBadStatusStreamFlatsByHouseID(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	err := houseUsecase.StreamFlatsByHouseID(context.Background(), 12, "test", func(flat domain.SingleFlatResponse) error {
+		return nil
+	}, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrFlat_BadStatus)
+}
+
 func (h *HouseUsecaseTest) TestNormalSubscribeByID(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	uid := uuid.New()
 	h.houseRepoMock.EXPECT().SubscribeByID(context.Background(), 1, uid, h.mockLg).Return(nil)
@@ -255,7 +351,7 @@ func (h *HouseUsecaseTest) TestBadIDSubscribeByID(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	uid := uuid.Nil
 	err := houseUsecase.SubscribeByID(context.Background(), 1, uid, h.mockLg)
@@ -267,7 +363,7 @@ func (h *HouseUsecaseTest) TestBadRepoCallSubscribeByID(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
 	done <- true
-	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
 
 	uid := uuid.New()
 	h.houseRepoMock.EXPECT().SubscribeByID(context.Background(), 1, uid, h.mockLg).Return(errors.New("error"))
@@ -277,14 +373,489 @@ func (h *HouseUsecaseTest) TestBadRepoCallSubscribeByID(t provider.T) {
 	t.Require().Error(err)
 }
 
+func (h *HouseUsecaseTest) TestPartialUpdateHouse(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	address := "ул. Исправленная, д. 5"
+	req := domain.UpdateHouseRequest{
+		Address: &address,
+	}
+
+	current := domain.House{
+		HouseID:       12,
+		Address:       "ул. Испраленая, д. 5",
+		ConstructYear: 2015,
+		Developer:     "ООО ТестСтрой",
+	}
+	updated := current
+	updated.Address = address
+
+	h.houseRepoMock.EXPECT().GetByID(context.Background(), 12, h.mockLg).Return(current, nil)
+	h.houseRepoMock.EXPECT().Update(context.Background(), &updated, h.mockLg).Return(nil)
+
+	resp, err := houseUsecase.Update(context.Background(), 12, &req, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(address, resp.Address)
+	t.Require().Equal(2015, resp.Year)
+	t.Require().Equal("ООО ТестСтрой", resp.Developer)
+}
+
+func (h *HouseUsecaseTest) TestCoordinatesUpdateHouse(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	lat, lon := 55.7558, 37.6173
+	req := domain.UpdateHouseRequest{
+		Latitude:  &lat,
+		Longitude: &lon,
+	}
+
+	current := domain.House{
+		HouseID:       13,
+		Address:       "ул. Ленина, д. 1",
+		ConstructYear: 2015,
+		Developer:     "ООО ТестСтрой",
+	}
+	updated := current
+	updated.Latitude = &lat
+	updated.Longitude = &lon
+
+	h.houseRepoMock.EXPECT().GetByID(context.Background(), 13, h.mockLg).Return(current, nil)
+	h.houseRepoMock.EXPECT().Update(context.Background(), &updated, h.mockLg).Return(nil)
+
+	resp, err := houseUsecase.Update(context.Background(), 13, &req, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(&lat, resp.Latitude)
+	t.Require().Equal(&lon, resp.Longitude)
+}
+
+func (h *HouseUsecaseTest) TestDeveloperIDUpdateHouse(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	developerID := 7
+	req := domain.UpdateHouseRequest{
+		DeveloperID: &developerID,
+	}
+
+	current := domain.House{
+		HouseID:       14,
+		Address:       "ул. Ленина, д. 2",
+		ConstructYear: 2015,
+		Developer:     "ООО ТестСтрой",
+		DeveloperID:   3,
+	}
+	updated := current
+	updated.Developer = "ПИК"
+	updated.DeveloperID = developerID
+
+	h.houseRepoMock.EXPECT().GetByID(context.Background(), 14, h.mockLg).Return(current, nil)
+	h.developerRepoMock.EXPECT().GetByID(context.Background(), developerID, h.mockLg).
+		Return(domain.Developer{DeveloperID: developerID, Name: "ПИК"}, nil)
+	h.houseRepoMock.EXPECT().Update(context.Background(), &updated, h.mockLg).Return(nil)
+
+	resp, err := houseUsecase.Update(context.Background(), 14, &req, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal("ПИК", resp.Developer)
+	t.Require().Equal(developerID, resp.DeveloperID)
+}
+
+func (h *HouseUsecaseTest) TestBadDeveloperUpdateHouse(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	developer := ""
+	req := domain.UpdateHouseRequest{
+		Developer: &developer,
+	}
+
+	resp, err := houseUsecase.Update(context.Background(), 12, &req, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrHouse_BadDeveloper)
+	t.Require().Equal(domain.CreateHouseResponse{}, resp)
+}
+
+func (h *HouseUsecaseTest) TestNotFoundUpdateHouse(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	year := 2020
+	req := domain.UpdateHouseRequest{
+		Year: &year,
+	}
+
+	h.houseRepoMock.EXPECT().GetByID(context.Background(), 1000, h.mockLg).Return(domain.House{}, domain.ErrHouse_NotFound)
+
+	resp, err := houseUsecase.Update(context.Background(), 1000, &req, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrHouse_NotFound)
+	t.Require().Equal(domain.CreateHouseResponse{}, resp)
+}
+
+func (h *HouseUsecaseTest) TestNormalListHouses(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	created := time.Date(2024, 8, 10, 12, 0, 0, 0, time.UTC)
+	houses := []domain.House{
+		{HouseID: 1, Address: "Москва, ул. Ленина, 1", ConstructYear: 2000, Developer: "ПИК",
+			CreateHouseDate: created, UpdateFlatDate: created},
+		{HouseID: 2, Address: "Москва, ул. Ленина, 2", ConstructYear: 2005, Developer: "ПИК",
+			CreateHouseDate: created.Add(time.Hour), UpdateFlatDate: created},
+		{HouseID: 3, Address: "Москва, ул. Ленина, 3", ConstructYear: 2010, Developer: "ПИК",
+			CreateHouseDate: created.Add(2 * time.Hour), UpdateFlatDate: created},
+	}
+	req := domain.ListHousesRequest{Developer: "ПИК", MinYear: 2000, Limit: 2}
+	filter := domain.HouseFilter{
+		Developer: "ПИК",
+		MinYear:   2000,
+		SortBy:    domain.HouseSortByCreated,
+		Order:     domain.AscOrder,
+		After:     domain.HouseCursor{Date: time.UnixMicro(0).UTC()},
+		Limit:     3,
+	}
+
+	h.houseRepoMock.EXPECT().Search(context.Background(), &filter, h.mockLg).Return(houses, nil)
+
+	resp, err := houseUsecase.List(context.Background(), &req, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Len(resp.Houses, 2)
+	t.Require().Equal(2, resp.Houses[1].HomeID)
+	t.Require().Equal(pkg.EncodeCursor(int(houses[1].CreateHouseDate.UnixMicro()), 2, 0, 0), resp.NextCursor)
+}
+
+func (h *HouseUsecaseTest) TestBadSortFieldListHouses(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	req := domain.ListHousesRequest{SortBy: "address"}
+
+	resp, err := houseUsecase.List(context.Background(), &req, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrHouse_BadSortField)
+	t.Require().Equal(domain.ListHousesResponse{}, resp)
+}
+
+func (h *HouseUsecaseTest) TestBadCursorListHouses(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	req := domain.ListHousesRequest{Cursor: "not a cursor"}
+
+	resp, err := houseUsecase.List(context.Background(), &req, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrHouse_BadCursor)
+	t.Require().Equal(domain.ListHousesResponse{}, resp)
+}
+
+func (h *HouseUsecaseTest) TestOtherSortCursorListHouses(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	created := time.Date(2024, 8, 10, 12, 0, 0, 0, time.UTC)
+	req := domain.ListHousesRequest{
+		SortBy: domain.HouseSortByUpdated,
+		Order:  domain.DescOrder,
+		Cursor: pkg.EncodeCursor(int(created.UnixMicro()), 2, 0, 0),
+	}
+
+	resp, err := houseUsecase.List(context.Background(), &req, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrHouse_BadCursor)
+	t.Require().Equal(http.StatusBadRequest, handlers.GetReturnHTTPCode(httptest.NewRecorder(), err))
+	t.Require().Equal(domain.ListHousesResponse{}, resp)
+}
+
+func (h *HouseUsecaseTest) TestTransliteratedSearchHousesByAddress(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	matches := []domain.HouseMatch{
+		{House: domain.House{HouseID: 4, Address: "Москва, ул. Ленина, д. 5", ConstructYear: 2010, Developer: "ПИК",
+			CreateHouseDate: now, UpdateFlatDate: now}, Score: 0.8},
+	}
+
+	h.houseRepoMock.EXPECT().SearchByAddress(context.Background(), "ulitsa Lenina", "улица ленина",
+		domain.DefaultSearchLimit, h.mockLg).Return(matches, nil)
+
+	resp, err := houseUsecase.SearchByAddress(context.Background(), " ulitsa Lenina ", 0, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Len(resp.Houses, 1)
+	t.Require().Equal(4, resp.Houses[0].HomeID)
+	t.Require().Equal(0.8, resp.Houses[0].Score)
+}
+
+func (h *HouseUsecaseTest) TestEmptyQuerySearchHousesByAddress(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	resp, err := houseUsecase.SearchByAddress(context.Background(), "   ", 0, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrHouse_BadQuery)
+	t.Require().Equal(domain.SearchHousesResponse{}, resp)
+}
+
+func (h *HouseUsecaseTest) TestBadCoordinatesCreateHouse(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	latitude := 55.75
+	req := domain.CreateHouseRequest{
+		Address:   "ул. Тестовая, д. 3",
+		Year:      2021,
+		Developer: "ООО ТестСтрой",
+		Latitude:  &latitude,
+	}
+
+	resp, err := houseUsecase.Create(context.Background(), &req, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrHouse_BadCoords)
+	t.Require().Equal(domain.CreateHouseResponse{}, resp)
+}
+
+func (h *HouseUsecaseTest) TestNormalGetNearbyHouses(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	latitude, longitude := 55.7601, 37.6186
+	houses := []domain.HouseDistance{
+		{House: domain.House{HouseID: 3, Address: "Театральная пл., 1", Developer: "ПИК",
+			Latitude: &latitude, Longitude: &longitude}, DistanceKm: 0.45678},
+	}
+	req := domain.NearbyHousesRequest{Latitude: 55.7558, Longitude: 37.6173, RadiusKm: 2}
+
+	h.houseRepoMock.EXPECT().GetNearby(context.Background(), domain.GeoPoint{Latitude: 55.7558, Longitude: 37.6173},
+		2.0, domain.DefaultSearchLimit, h.mockLg).Return(houses, nil)
+
+	resp, err := houseUsecase.GetNearby(context.Background(), &req, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Len(resp.Houses, 1)
+	t.Require().Equal(3, resp.Houses[0].HomeID)
+	t.Require().Equal(0.457, resp.Houses[0].DistanceKm)
+	t.Require().Equal(&latitude, resp.Houses[0].Latitude)
+}
+
+func (h *HouseUsecaseTest) TestBadRadiusGetNearbyHouses(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	req := domain.NearbyHousesRequest{Latitude: 55.7558, Longitude: 37.6173, RadiusKm: 0}
+
+	resp, err := houseUsecase.GetNearby(context.Background(), &req, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrHouse_BadRadius)
+	t.Require().Equal(domain.NearbyHousesResponse{}, resp)
+}
+
+func (h *HouseUsecaseTest) TestNormalExportGeoJSON(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	latitude, longitude := 55.7601, 37.6186
+	houses := []domain.HouseFlatsCount{
+		{House: domain.House{HouseID: 3, Address: "Театральная пл., 1", ConstructYear: 1825, Developer: "ПИК",
+			Latitude: &latitude, Longitude: &longitude}, ApprovedFlats: 4},
+	}
+
+	h.houseRepoMock.EXPECT().GetWithApprovedFlats(context.Background(), h.mockLg).Return(houses, nil)
+
+	collection, err := houseUsecase.ExportGeoJSON(context.Background(), h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(domain.GeoJSONFeatureCollectionType, collection.Type)
+	t.Require().Len(collection.Features, 1)
+	t.Require().Equal([2]float64{longitude, latitude}, collection.Features[0].Geometry.Coordinates)
+	t.Require().Equal(4, collection.Features[0].Properties.ApprovedFlats)
+}
+
+func (h *HouseUsecaseTest) TestDeveloperIDCreateHouse(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	req := domain.CreateHouseRequest{
+		Address:     "ул. Тестовая, д. 3",
+		Year:        2021,
+		DeveloperID: 7,
+		Developer:   "PIK Group",
+	}
+
+	h.developerRepoMock.EXPECT().GetByID(context.Background(), 7, h.mockLg).
+		Return(domain.Developer{DeveloperID: 7, Name: "ПИК"}, nil)
+	h.houseRepoMock.EXPECT().Create(context.Background(), gomock.Any(), h.mockLg).
+		DoAndReturn(func(ctx context.Context, house *domain.House, lg *zap.Logger) (domain.House, error) {
+			t.Require().Equal(7, house.DeveloperID)
+			t.Require().Equal("ПИК", house.Developer)
+			house.HouseID = 3
+			return *house, nil
+		})
+
+	created, err := houseUsecase.Create(context.Background(), &req, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(7, created.DeveloperID)
+	t.Require().Equal("ПИК", created.Developer)
+}
+
+func (h *HouseUsecaseTest) TestUnknownDeveloperCreateHouse(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	req := domain.CreateHouseRequest{
+		Address:     "ул. Тестовая, д. 3",
+		Year:        2021,
+		DeveloperID: 1000,
+	}
+
+	h.developerRepoMock.EXPECT().GetByID(context.Background(), 1000, h.mockLg).
+		Return(domain.Developer{}, domain.ErrDeveloper_NotFound)
+
+	created, err := houseUsecase.Create(context.Background(), &req, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrDeveloper_NotFound)
+	t.Require().Equal(domain.CreateHouseResponse{}, created)
+}
+
+func (h *HouseUsecaseTest) TestModeratorGetHouseStats(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	stats := domain.HouseStats{
+		Total:        3,
+		StatusCounts: map[string]int{domain.ApprovedStatus: 2, domain.CreatedStatus: 1},
+		Price:        domain.PriceStats{Min: 100, Median: 200, Max: 400},
+		PricePerRoom: domain.PriceStats{Min: 50, Median: 100, Max: 200},
+		RoomCounts:   map[int]int{1: 1, 2: 2},
+	}
+
+	h.houseRepoMock.EXPECT().GetByID(context.Background(), 6, h.mockLg).Return(domain.House{HouseID: 6}, nil)
+	h.houseRepoMock.EXPECT().GetStats(context.Background(), 6, domain.AnyStatus, h.mockLg).Return(stats, nil)
+
+	resp, err := houseUsecase.GetStats(context.Background(), 6, domain.AnyStatus, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(3, resp.Total)
+	t.Require().Len(resp.ByStatus, len(domain.FlatStatuses))
+	t.Require().Equal(2, resp.ByStatus[domain.ApprovedStatus])
+	t.Require().Equal(0, resp.ByStatus[domain.DeclinedStatus])
+	t.Require().Equal(200.0, resp.Price.Median)
+	t.Require().Equal(map[int]int{1: 1, 2: 2}, resp.Rooms)
+}
+
+func (h *HouseUsecaseTest) TestClientGetHouseStats(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	h.houseRepoMock.EXPECT().GetByID(context.Background(), 6, h.mockLg).Return(domain.House{HouseID: 6}, nil)
+	h.houseRepoMock.EXPECT().GetStats(context.Background(), 6, domain.ApprovedStatus, h.mockLg).
+		Return(domain.HouseStats{}, nil)
+
+	resp, err := houseUsecase.GetStats(context.Background(), 6, domain.ApprovedStatus, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(map[string]int{domain.ApprovedStatus: 0}, resp.ByStatus)
+	t.Require().Equal(map[int]int{}, resp.Rooms)
+}
+
+func (h *HouseUsecaseTest) TestNotFoundGetHouseStats(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	h.houseRepoMock.EXPECT().GetByID(context.Background(), 1000, h.mockLg).Return(domain.House{}, domain.ErrHouse_NotFound)
+
+	resp, err := houseUsecase.GetStats(context.Background(), 1000, domain.AnyStatus, h.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrHouse_NotFound)
+	t.Require().Equal(domain.HouseStatsResponse{}, resp)
+}
+
 func (h *HouseUsecaseTest) TestNormalNotifying(t provider.T) {
 	notifySender := ports.NewSender()
 	done := make(chan bool, 1)
-	_ = usecase.NewHouseUsecase(h.houseRepoMock, notifySender, h.notifyRepoMock, done, time.Minute, time.Minute, h.mockLg)
+	_ = usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Minute, time.Minute, h.mockLg)
 
 	h.notifyRepoMock.EXPECT().GetNoSendNotifies(gomock.Any(), h.mockLg).Times(1).Return(nil, nil)
 }
 
+func (h *HouseUsecaseTest) TestJSONLImportHouses(t provider.T) {
+	notifySender := ports.NewSender()
+	done := make(chan bool, 1)
+	done <- true
+	houseUsecase := usecase.NewHouseUsecase(h.houseRepoMock, h.developerRepoMock, notifySender, h.notifyRepoMock, done, time.Second, time.Second, h.mockLg)
+
+	file := strings.NewReader(`{"address": "ул. Тестовая, д. 3", "year": 2021, "developer": "ООО ТестСтрой"}
+{"address": "", "year": 2021, "developer": "ООО ТестСтрой"}
+
+{"address": "ул. Тестовая, д. 5", "developer": "ООО ТестСтрой"}
+{"address": "ул. Тестовая, д. 7"
+`)
+	developer := domain.Developer{DeveloperID: 3, Name: "ООО ТестСтрой"}
+	copied := []domain.House{
+		{HouseID: 11, Address: "ул. Тестовая, д. 3", ConstructYear: 2021, Developer: "ООО ТестСтрой", DeveloperID: 3},
+		{HouseID: 12, Address: "ул. Тестовая, д. 5", Developer: "ООО ТестСтрой", DeveloperID: 3},
+	}
+
+	h.developerRepoMock.EXPECT().GetOrCreateByName(gomock.Any(), "ООО ТестСтрой", h.mockLg).Return(developer, nil).Times(1)
+	h.houseRepoMock.EXPECT().CopyHouses(gomock.Any(), gomock.Len(2), h.mockLg).Return(copied, nil)
+
+	report, err := houseUsecase.Import(context.Background(), domain.ImportFormatJSONL, file, h.mockLg)
+
+	t.Require().Nil(err)
+	t.Require().Equal(4, report.Total)
+	t.Require().Equal(2, report.Imported)
+	t.Require().Equal([]domain.ImportedHouse{{Line: 1, ID: 11}, {Line: 4, ID: 12}}, report.Houses)
+	t.Require().Len(report.Errors, 2)
+	t.Require().Equal(domain.ImportRowError{Line: 2, Error: domain.ErrHouse_BadAddress.Error()}, report.Errors[0])
+	t.Require().Equal(5, report.Errors[1].Line)
+}
+
 func TestHouseUsecaseSuiteRunner(t *testing.T) {
 	suite.RunSuite(t, new(HouseUsecaseTest))
 }
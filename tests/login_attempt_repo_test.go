@@ -0,0 +1,62 @@
+//go:build unit
+// +build unit
+
+package tests
+
+import (
+	"avito-test-task/internal/domain"
+	"avito-test-task/internal/repo"
+	"avito-test-task/pkg"
+	mock_domain "avito-test-task/tests/mocks"
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type LoginAttemptRepoTest struct {
+	suite.Suite
+	mockLg *zap.Logger
+}
+
+func (l *LoginAttemptRepoTest) BeforeAll(t provider.T) {
+	t.Log("Init log")
+	l.mockLg = pkg.CreateMockLogger()
+}
+
+func (l *LoginAttemptRepoTest) TestBadQueryForgiveAttempt(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	attemptRepo := repo.NewPostgresLoginAttemptRepo(poolMock, nil)
+
+	poolMock.EXPECT().Exec(context.Background(), gomock.Any(), domain.LoginScopeIP, "10.0.0.1").
+		Return(pgconn.CommandTag{}, errors.New("error"))
+
+	err := attemptRepo.ForgiveAttempt(context.Background(), domain.LoginScopeIP, "10.0.0.1", l.mockLg)
+
+	t.Require().Error(err)
+}
+
+func (l *LoginAttemptRepoTest) TestBadQueryRegisterAttempt(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowMock := mock_domain.NewMockRow(ctrl)
+	attemptRepo := repo.NewPostgresLoginAttemptRepo(poolMock, nil)
+
+	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), domain.LoginScopeAccount, "user",
+		time.Hour.Seconds()).Return(rowMock)
+	rowMock.EXPECT().Scan(gomock.Any()).Return(errors.New("error"))
+
+	_, err := attemptRepo.RegisterAttempt(context.Background(), domain.LoginScopeAccount, "user", time.Hour, l.mockLg)
+
+	t.Require().Error(err)
+}
+
+func TestLoginAttemptRepoSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(LoginAttemptRepoTest))
+}
@@ -0,0 +1,242 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: developer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=developer.go
+//
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	domain "avito-test-task/internal/domain"
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	zap "go.uber.org/zap"
+)
+
+// MockDeveloperUsecase is a mock of DeveloperUsecase interface.
+type MockDeveloperUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeveloperUsecaseMockRecorder
+}
+
+// MockDeveloperUsecaseMockRecorder is the mock recorder for MockDeveloperUsecase.
+type MockDeveloperUsecaseMockRecorder struct {
+	mock *MockDeveloperUsecase
+}
+
+// NewMockDeveloperUsecase creates a new mock instance.
+func NewMockDeveloperUsecase(ctrl *gomock.Controller) *MockDeveloperUsecase {
+	mock := &MockDeveloperUsecase{ctrl: ctrl}
+	mock.recorder = &MockDeveloperUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeveloperUsecase) EXPECT() *MockDeveloperUsecaseMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeveloperUsecase) Create(ctx context.Context, req *domain.DeveloperRequest, lg *zap.Logger) (domain.DeveloperResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, req, lg)
+	ret0, _ := ret[0].(domain.DeveloperResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeveloperUsecaseMockRecorder) Create(ctx, req, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeveloperUsecase)(nil).Create), ctx, req, lg)
+}
+
+// DeleteByID mocks base method.
+func (m *MockDeveloperUsecase) DeleteByID(ctx context.Context, id int, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", ctx, id, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByID indicates an expected call of DeleteByID.
+func (mr *MockDeveloperUsecaseMockRecorder) DeleteByID(ctx, id, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockDeveloperUsecase)(nil).DeleteByID), ctx, id, lg)
+}
+
+// GetAll mocks base method.
+func (m *MockDeveloperUsecase) GetAll(ctx context.Context, cursor string, limit int, lg *zap.Logger) (domain.DevelopersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, cursor, limit, lg)
+	ret0, _ := ret[0].(domain.DevelopersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDeveloperUsecaseMockRecorder) GetAll(ctx, cursor, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDeveloperUsecase)(nil).GetAll), ctx, cursor, limit, lg)
+}
+
+// GetByID mocks base method.
+func (m *MockDeveloperUsecase) GetByID(ctx context.Context, id int, lg *zap.Logger) (domain.DeveloperResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, lg)
+	ret0, _ := ret[0].(domain.DeveloperResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDeveloperUsecaseMockRecorder) GetByID(ctx, id, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDeveloperUsecase)(nil).GetByID), ctx, id, lg)
+}
+
+// GetHouses mocks base method.
+func (m *MockDeveloperUsecase) GetHouses(ctx context.Context, id int, cursor string, limit int, lg *zap.Logger) (domain.DeveloperHousesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouses", ctx, id, cursor, limit, lg)
+	ret0, _ := ret[0].(domain.DeveloperHousesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouses indicates an expected call of GetHouses.
+func (mr *MockDeveloperUsecaseMockRecorder) GetHouses(ctx, id, cursor, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouses", reflect.TypeOf((*MockDeveloperUsecase)(nil).GetHouses), ctx, id, cursor, limit, lg)
+}
+
+// Update mocks base method.
+func (m *MockDeveloperUsecase) Update(ctx context.Context, id int, req *domain.DeveloperRequest, lg *zap.Logger) (domain.DeveloperResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, req, lg)
+	ret0, _ := ret[0].(domain.DeveloperResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeveloperUsecaseMockRecorder) Update(ctx, id, req, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeveloperUsecase)(nil).Update), ctx, id, req, lg)
+}
+
+// MockDeveloperRepo is a mock of DeveloperRepo interface.
+type MockDeveloperRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeveloperRepoMockRecorder
+}
+
+// MockDeveloperRepoMockRecorder is the mock recorder for MockDeveloperRepo.
+type MockDeveloperRepoMockRecorder struct {
+	mock *MockDeveloperRepo
+}
+
+// NewMockDeveloperRepo creates a new mock instance.
+func NewMockDeveloperRepo(ctrl *gomock.Controller) *MockDeveloperRepo {
+	mock := &MockDeveloperRepo{ctrl: ctrl}
+	mock.recorder = &MockDeveloperRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeveloperRepo) EXPECT() *MockDeveloperRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockDeveloperRepo) Create(ctx context.Context, developer *domain.Developer, lg *zap.Logger) (domain.Developer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, developer, lg)
+	ret0, _ := ret[0].(domain.Developer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeveloperRepoMockRecorder) Create(ctx, developer, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeveloperRepo)(nil).Create), ctx, developer, lg)
+}
+
+// DeleteByID mocks base method.
+func (m *MockDeveloperRepo) DeleteByID(ctx context.Context, id int, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByID", ctx, id, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByID indicates an expected call of DeleteByID.
+func (mr *MockDeveloperRepoMockRecorder) DeleteByID(ctx, id, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockDeveloperRepo)(nil).DeleteByID), ctx, id, lg)
+}
+
+// GetAll mocks base method.
+func (m *MockDeveloperRepo) GetAll(ctx context.Context, afterID, limit int, lg *zap.Logger) ([]domain.Developer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, afterID, limit, lg)
+	ret0, _ := ret[0].([]domain.Developer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDeveloperRepoMockRecorder) GetAll(ctx, afterID, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDeveloperRepo)(nil).GetAll), ctx, afterID, limit, lg)
+}
+
+// GetByID mocks base method.
+func (m *MockDeveloperRepo) GetByID(ctx context.Context, id int, lg *zap.Logger) (domain.Developer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, lg)
+	ret0, _ := ret[0].(domain.Developer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockDeveloperRepoMockRecorder) GetByID(ctx, id, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockDeveloperRepo)(nil).GetByID), ctx, id, lg)
+}
+
+// GetOrCreateByName mocks base method.
+func (m *MockDeveloperRepo) GetOrCreateByName(ctx context.Context, name string, lg *zap.Logger) (domain.Developer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrCreateByName", ctx, name, lg)
+	ret0, _ := ret[0].(domain.Developer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrCreateByName indicates an expected call of GetOrCreateByName.
+func (mr *MockDeveloperRepoMockRecorder) GetOrCreateByName(ctx, name, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrCreateByName", reflect.TypeOf((*MockDeveloperRepo)(nil).GetOrCreateByName), ctx, name, lg)
+}
+
+// Update mocks base method.
+func (m *MockDeveloperRepo) Update(ctx context.Context, developer *domain.Developer, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, developer, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockDeveloperRepoMockRecorder) Update(ctx, developer, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDeveloperRepo)(nil).Update), ctx, developer, lg)
+}
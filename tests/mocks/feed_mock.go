@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=feed.go
+//
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	domain "avito-test-task/internal/domain"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+	zap "go.uber.org/zap"
+)
+
+// MockFeedUsecase is a mock of FeedUsecase interface.
+type MockFeedUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedUsecaseMockRecorder
+}
+
+// MockFeedUsecaseMockRecorder is the mock recorder for MockFeedUsecase.
+type MockFeedUsecaseMockRecorder struct {
+	mock *MockFeedUsecase
+}
+
+// NewMockFeedUsecase creates a new mock instance.
+func NewMockFeedUsecase(ctrl *gomock.Controller) *MockFeedUsecase {
+	mock := &MockFeedUsecase{ctrl: ctrl}
+	mock.recorder = &MockFeedUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedUsecase) EXPECT() *MockFeedUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetVersion mocks base method.
+func (m *MockFeedUsecase) GetVersion(ctx context.Context, req *domain.FeedRequest, lg *zap.Logger) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", ctx, req, lg)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockFeedUsecaseMockRecorder) GetVersion(ctx, req, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockFeedUsecase)(nil).GetVersion), ctx, req, lg)
+}
+
+// RegenerateOffers mocks base method.
+func (m *MockFeedUsecase) RegenerateOffers(ctx context.Context, lg *zap.Logger) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateOffers", ctx, lg)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateOffers indicates an expected call of RegenerateOffers.
+func (mr *MockFeedUsecaseMockRecorder) RegenerateOffers(ctx, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateOffers", reflect.TypeOf((*MockFeedUsecase)(nil).RegenerateOffers), ctx, lg)
+}
+
+// Stream mocks base method.
+func (m *MockFeedUsecase) Stream(ctx context.Context, req *domain.FeedRequest, handle func(string) error, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, req, handle, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *MockFeedUsecaseMockRecorder) Stream(ctx, req, handle, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockFeedUsecase)(nil).Stream), ctx, req, handle, lg)
+}
+
+// MockFeedRepo is a mock of FeedRepo interface.
+type MockFeedRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeedRepoMockRecorder
+}
+
+// MockFeedRepoMockRecorder is the mock recorder for MockFeedRepo.
+type MockFeedRepoMockRecorder struct {
+	mock *MockFeedRepo
+}
+
+// NewMockFeedRepo creates a new mock instance.
+func NewMockFeedRepo(ctrl *gomock.Controller) *MockFeedRepo {
+	mock := &MockFeedRepo{ctrl: ctrl}
+	mock.recorder = &MockFeedRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeedRepo) EXPECT() *MockFeedRepoMockRecorder {
+	return m.recorder
+}
+
+// GetChangedFlats mocks base method.
+func (m *MockFeedRepo) GetChangedFlats(ctx context.Context, limit int, lg *zap.Logger) ([]domain.FeedFlat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangedFlats", ctx, limit, lg)
+	ret0, _ := ret[0].([]domain.FeedFlat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangedFlats indicates an expected call of GetChangedFlats.
+func (mr *MockFeedRepoMockRecorder) GetChangedFlats(ctx, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangedFlats", reflect.TypeOf((*MockFeedRepo)(nil).GetChangedFlats), ctx, limit, lg)
+}
+
+// GetVersion mocks base method.
+func (m *MockFeedRepo) GetVersion(ctx context.Context, scope string, id int, lg *zap.Logger) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", ctx, scope, id, lg)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockFeedRepoMockRecorder) GetVersion(ctx, scope, id, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockFeedRepo)(nil).GetVersion), ctx, scope, id, lg)
+}
+
+// SaveOffers mocks base method.
+func (m *MockFeedRepo) SaveOffers(ctx context.Context, flats []domain.FeedFlat, offers []domain.FeedOffer, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOffers", ctx, flats, offers, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOffers indicates an expected call of SaveOffers.
+func (mr *MockFeedRepoMockRecorder) SaveOffers(ctx, flats, offers, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOffers", reflect.TypeOf((*MockFeedRepo)(nil).SaveOffers), ctx, flats, offers, lg)
+}
+
+// StreamOffers mocks base method.
+func (m *MockFeedRepo) StreamOffers(ctx context.Context, format, scope string, id int, handle func(string) error, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamOffers", ctx, format, scope, id, handle, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamOffers indicates an expected call of StreamOffers.
+func (mr *MockFeedRepoMockRecorder) StreamOffers(ctx, format, scope, id, handle, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamOffers", reflect.TypeOf((*MockFeedRepo)(nil).StreamOffers), ctx, format, scope, id, handle, lg)
+}
@@ -12,7 +12,9 @@ package mock_domain
 import (
 	domain "avito-test-task/internal/domain"
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -42,6 +44,21 @@ func (m *MockFlatUsecase) EXPECT() *MockFlatUsecaseMockRecorder {
 	return m.recorder
 }
 
+// ClaimNext mocks base method.
+func (m *MockFlatUsecase) ClaimNext(ctx context.Context, moderatorID uuid.UUID, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNext", ctx, moderatorID, lg)
+	ret0, _ := ret[0].(domain.CreateFlatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNext indicates an expected call of ClaimNext.
+func (mr *MockFlatUsecaseMockRecorder) ClaimNext(ctx, moderatorID, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNext", reflect.TypeOf((*MockFlatUsecase)(nil).ClaimNext), ctx, moderatorID, lg)
+}
+
 // Create mocks base method.
 func (m *MockFlatUsecase) Create(ctx context.Context, userID uuid.UUID, flatReq *domain.CreateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +74,123 @@ func (mr *MockFlatUsecaseMockRecorder) Create(ctx, userID, flatReq, lg any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFlatUsecase)(nil).Create), ctx, userID, flatReq, lg)
 }
 
+// Edit mocks base method.
+func (m *MockFlatUsecase) Edit(ctx context.Context, userID uuid.UUID, editFlatData *domain.EditFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, userID, editFlatData, lg)
+	ret0, _ := ret[0].(domain.CreateFlatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockFlatUsecaseMockRecorder) Edit(ctx, userID, editFlatData, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockFlatUsecase)(nil).Edit), ctx, userID, editFlatData, lg)
+}
+
+// ExtendLease mocks base method.
+func (m *MockFlatUsecase) ExtendLease(ctx context.Context, moderatorID uuid.UUID, leaseData *domain.UpdateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendLease", ctx, moderatorID, leaseData, lg)
+	ret0, _ := ret[0].(domain.CreateFlatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtendLease indicates an expected call of ExtendLease.
+func (mr *MockFlatUsecaseMockRecorder) ExtendLease(ctx, moderatorID, leaseData, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendLease", reflect.TypeOf((*MockFlatUsecase)(nil).ExtendLease), ctx, moderatorID, leaseData, lg)
+}
+
+// GetPriceHistory mocks base method.
+func (m *MockFlatUsecase) GetPriceHistory(ctx context.Context, userID uuid.UUID, role string, flatID, houseID int, lg *zap.Logger) (domain.FlatPriceHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceHistory", ctx, userID, role, flatID, houseID, lg)
+	ret0, _ := ret[0].(domain.FlatPriceHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceHistory indicates an expected call of GetPriceHistory.
+func (mr *MockFlatUsecaseMockRecorder) GetPriceHistory(ctx, userID, role, flatID, houseID, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceHistory", reflect.TypeOf((*MockFlatUsecase)(nil).GetPriceHistory), ctx, userID, role, flatID, houseID, lg)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockFlatUsecase) GetStatusHistory(ctx context.Context, userID uuid.UUID, role string, flatID, houseID int, lg *zap.Logger) (domain.FlatStatusHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, userID, role, flatID, houseID, lg)
+	ret0, _ := ret[0].(domain.FlatStatusHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockFlatUsecaseMockRecorder) GetStatusHistory(ctx, userID, role, flatID, houseID, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockFlatUsecase)(nil).GetStatusHistory), ctx, userID, role, flatID, houseID, lg)
+}
+
+// Import mocks base method.
+func (m *MockFlatUsecase) Import(ctx context.Context, userID uuid.UUID, format string, file io.Reader, lg *zap.Logger) (domain.ImportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, userID, format, file, lg)
+	ret0, _ := ret[0].(domain.ImportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockFlatUsecaseMockRecorder) Import(ctx, userID, format, file, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockFlatUsecase)(nil).Import), ctx, userID, format, file, lg)
+}
+
+// ReclaimingLeases mocks base method.
+func (m *MockFlatUsecase) ReclaimingLeases(done chan bool, frequency, timeout time.Duration, lg *zap.Logger) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReclaimingLeases", done, frequency, timeout, lg)
+}
+
+// ReclaimingLeases indicates an expected call of ReclaimingLeases.
+func (mr *MockFlatUsecaseMockRecorder) ReclaimingLeases(done, frequency, timeout, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReclaimingLeases", reflect.TypeOf((*MockFlatUsecase)(nil).ReclaimingLeases), done, frequency, timeout, lg)
+}
+
+// Restore mocks base method.
+func (m *MockFlatUsecase) Restore(ctx context.Context, userID uuid.UUID, role string, restoreData *domain.UpdateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, userID, role, restoreData, lg)
+	ret0, _ := ret[0].(domain.CreateFlatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockFlatUsecaseMockRecorder) Restore(ctx, userID, role, restoreData, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockFlatUsecase)(nil).Restore), ctx, userID, role, restoreData, lg)
+}
+
+// Search mocks base method.
+func (m *MockFlatUsecase) Search(ctx context.Context, req *domain.SearchFlatRequest, status string, lg *zap.Logger) (domain.SearchFlatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, req, status, lg)
+	ret0, _ := ret[0].(domain.SearchFlatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockFlatUsecaseMockRecorder) Search(ctx, req, status, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockFlatUsecase)(nil).Search), ctx, req, status, lg)
+}
+
 // Update mocks base method.
 func (m *MockFlatUsecase) Update(ctx context.Context, moderatorID uuid.UUID, newFlatData *domain.UpdateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +206,21 @@ func (mr *MockFlatUsecaseMockRecorder) Update(ctx, moderatorID, newFlatData, lg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFlatUsecase)(nil).Update), ctx, moderatorID, newFlatData, lg)
 }
 
+// Withdraw mocks base method.
+func (m *MockFlatUsecase) Withdraw(ctx context.Context, userID uuid.UUID, role string, withdrawData *domain.UpdateFlatRequest, lg *zap.Logger) (domain.CreateFlatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, userID, role, withdrawData, lg)
+	ret0, _ := ret[0].(domain.CreateFlatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockFlatUsecaseMockRecorder) Withdraw(ctx, userID, role, withdrawData, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockFlatUsecase)(nil).Withdraw), ctx, userID, role, withdrawData, lg)
+}
+
 // MockFlatRepo is a mock of FlatRepo interface.
 type MockFlatRepo struct {
 	ctrl     *gomock.Controller
@@ -95,6 +244,36 @@ func (m *MockFlatRepo) EXPECT() *MockFlatRepoMockRecorder {
 	return m.recorder
 }
 
+// ClaimNext mocks base method.
+func (m *MockFlatRepo) ClaimNext(ctx context.Context, moderatorID uuid.UUID, leaseTTL time.Duration, lg *zap.Logger) (domain.Flat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNext", ctx, moderatorID, leaseTTL, lg)
+	ret0, _ := ret[0].(domain.Flat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNext indicates an expected call of ClaimNext.
+func (mr *MockFlatRepoMockRecorder) ClaimNext(ctx, moderatorID, leaseTTL, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNext", reflect.TypeOf((*MockFlatRepo)(nil).ClaimNext), ctx, moderatorID, leaseTTL, lg)
+}
+
+// CopyFlats mocks base method.
+func (m *MockFlatRepo) CopyFlats(ctx context.Context, flats []domain.Flat, lg *zap.Logger) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFlats", ctx, flats, lg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyFlats indicates an expected call of CopyFlats.
+func (mr *MockFlatRepoMockRecorder) CopyFlats(ctx, flats, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFlats", reflect.TypeOf((*MockFlatRepo)(nil).CopyFlats), ctx, flats, lg)
+}
+
 // Create mocks base method.
 func (m *MockFlatRepo) Create(ctx context.Context, flat *domain.Flat, lg *zap.Logger) (domain.Flat, error) {
 	m.ctrl.T.Helper()
@@ -124,19 +303,49 @@ func (mr *MockFlatRepoMockRecorder) DeleteByID(ctx, id, houseID, lg any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByID", reflect.TypeOf((*MockFlatRepo)(nil).DeleteByID), ctx, id, houseID, lg)
 }
 
+// ExtendLease mocks base method.
+func (m *MockFlatRepo) ExtendLease(ctx context.Context, moderatorID uuid.UUID, flat *domain.Flat, leaseTTL time.Duration, lg *zap.Logger) (domain.Flat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendLease", ctx, moderatorID, flat, leaseTTL, lg)
+	ret0, _ := ret[0].(domain.Flat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtendLease indicates an expected call of ExtendLease.
+func (mr *MockFlatRepoMockRecorder) ExtendLease(ctx, moderatorID, flat, leaseTTL, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendLease", reflect.TypeOf((*MockFlatRepo)(nil).ExtendLease), ctx, moderatorID, flat, leaseTTL, lg)
+}
+
+// FindImportConflicts mocks base method.
+func (m *MockFlatRepo) FindImportConflicts(ctx context.Context, flats []domain.Flat, lg *zap.Logger) (domain.FlatImportConflicts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindImportConflicts", ctx, flats, lg)
+	ret0, _ := ret[0].(domain.FlatImportConflicts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindImportConflicts indicates an expected call of FindImportConflicts.
+func (mr *MockFlatRepoMockRecorder) FindImportConflicts(ctx, flats, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindImportConflicts", reflect.TypeOf((*MockFlatRepo)(nil).FindImportConflicts), ctx, flats, lg)
+}
+
 // GetAll mocks base method.
-func (m *MockFlatRepo) GetAll(ctx context.Context, offset, limit int, lg *zap.Logger) ([]domain.Flat, error) {
+func (m *MockFlatRepo) GetAll(ctx context.Context, after domain.FlatCursor, limit int, lg *zap.Logger) ([]domain.Flat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx, offset, limit, lg)
+	ret := m.ctrl.Call(m, "GetAll", ctx, after, limit, lg)
 	ret0, _ := ret[0].([]domain.Flat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockFlatRepoMockRecorder) GetAll(ctx, offset, limit, lg any) *gomock.Call {
+func (mr *MockFlatRepoMockRecorder) GetAll(ctx, after, limit, lg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockFlatRepo)(nil).GetAll), ctx, offset, limit, lg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockFlatRepo)(nil).GetAll), ctx, after, limit, lg)
 }
 
 // GetByID mocks base method.
@@ -154,17 +363,107 @@ func (mr *MockFlatRepoMockRecorder) GetByID(ctx, id, houseID, lg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockFlatRepo)(nil).GetByID), ctx, id, houseID, lg)
 }
 
+// GetPriceHistory mocks base method.
+func (m *MockFlatRepo) GetPriceHistory(ctx context.Context, flatID, houseID int, lg *zap.Logger) ([]domain.FlatPriceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceHistory", ctx, flatID, houseID, lg)
+	ret0, _ := ret[0].([]domain.FlatPriceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceHistory indicates an expected call of GetPriceHistory.
+func (mr *MockFlatRepoMockRecorder) GetPriceHistory(ctx, flatID, houseID, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceHistory", reflect.TypeOf((*MockFlatRepo)(nil).GetPriceHistory), ctx, flatID, houseID, lg)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockFlatRepo) GetStatusHistory(ctx context.Context, flatID, houseID int, lg *zap.Logger) ([]domain.FlatStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, flatID, houseID, lg)
+	ret0, _ := ret[0].([]domain.FlatStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockFlatRepoMockRecorder) GetStatusHistory(ctx, flatID, houseID, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockFlatRepo)(nil).GetStatusHistory), ctx, flatID, houseID, lg)
+}
+
+// ReclaimExpiredLeases mocks base method.
+func (m *MockFlatRepo) ReclaimExpiredLeases(ctx context.Context, lg *zap.Logger) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReclaimExpiredLeases", ctx, lg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReclaimExpiredLeases indicates an expected call of ReclaimExpiredLeases.
+func (mr *MockFlatRepoMockRecorder) ReclaimExpiredLeases(ctx, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReclaimExpiredLeases", reflect.TypeOf((*MockFlatRepo)(nil).ReclaimExpiredLeases), ctx, lg)
+}
+
+// Search mocks base method.
+func (m *MockFlatRepo) Search(ctx context.Context, filter *domain.FlatFilter, lg *zap.Logger) ([]domain.Flat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, filter, lg)
+	ret0, _ := ret[0].([]domain.Flat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockFlatRepoMockRecorder) Search(ctx, filter, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockFlatRepo)(nil).Search), ctx, filter, lg)
+}
+
 // Update mocks base method.
-func (m *MockFlatRepo) Update(ctx context.Context, moderatorID uuid.UUID, newFlatData *domain.Flat, lg *zap.Logger) (domain.Flat, error) {
+func (m *MockFlatRepo) Update(ctx context.Context, moderatorID uuid.UUID, newFlatData *domain.Flat, expectedStatus string, leaseTTL time.Duration, lg *zap.Logger) (domain.Flat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, moderatorID, newFlatData, lg)
+	ret := m.ctrl.Call(m, "Update", ctx, moderatorID, newFlatData, expectedStatus, leaseTTL, lg)
 	ret0, _ := ret[0].(domain.Flat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockFlatRepoMockRecorder) Update(ctx, moderatorID, newFlatData, lg any) *gomock.Call {
+func (mr *MockFlatRepoMockRecorder) Update(ctx, moderatorID, newFlatData, expectedStatus, leaseTTL, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFlatRepo)(nil).Update), ctx, moderatorID, newFlatData, expectedStatus, leaseTTL, lg)
+}
+
+// UpdateByOwner mocks base method.
+func (m *MockFlatRepo) UpdateByOwner(ctx context.Context, ownerID uuid.UUID, newFlatData *domain.Flat, expectedStatus string, lg *zap.Logger) (domain.Flat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateByOwner", ctx, ownerID, newFlatData, expectedStatus, lg)
+	ret0, _ := ret[0].(domain.Flat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateByOwner indicates an expected call of UpdateByOwner.
+func (mr *MockFlatRepoMockRecorder) UpdateByOwner(ctx, ownerID, newFlatData, expectedStatus, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateByOwner", reflect.TypeOf((*MockFlatRepo)(nil).UpdateByOwner), ctx, ownerID, newFlatData, expectedStatus, lg)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockFlatRepo) UpdateStatus(ctx context.Context, actorID uuid.UUID, newFlatData *domain.Flat, expectedStatus string, lg *zap.Logger) (domain.Flat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, actorID, newFlatData, expectedStatus, lg)
+	ret0, _ := ret[0].(domain.Flat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockFlatRepoMockRecorder) UpdateStatus(ctx, actorID, newFlatData, expectedStatus, lg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFlatRepo)(nil).Update), ctx, moderatorID, newFlatData, lg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockFlatRepo)(nil).UpdateStatus), ctx, actorID, newFlatData, expectedStatus, lg)
 }
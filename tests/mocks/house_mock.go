@@ -12,6 +12,7 @@ package mock_domain
 import (
 	domain "avito-test-task/internal/domain"
 	context "context"
+	io "io"
 	reflect "reflect"
 	time "time"
 
@@ -58,19 +59,94 @@ func (mr *MockHouseUsecaseMockRecorder) Create(ctx, req, lg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockHouseUsecase)(nil).Create), ctx, req, lg)
 }
 
+// ExportGeoJSON mocks base method.
+func (m *MockHouseUsecase) ExportGeoJSON(ctx context.Context, lg *zap.Logger) (domain.GeoJSONFeatureCollection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportGeoJSON", ctx, lg)
+	ret0, _ := ret[0].(domain.GeoJSONFeatureCollection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportGeoJSON indicates an expected call of ExportGeoJSON.
+func (mr *MockHouseUsecaseMockRecorder) ExportGeoJSON(ctx, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportGeoJSON", reflect.TypeOf((*MockHouseUsecase)(nil).ExportGeoJSON), ctx, lg)
+}
+
 // GetFlatsByHouseID mocks base method.
-func (m *MockHouseUsecase) GetFlatsByHouseID(ctx context.Context, id int, status string, lg *zap.Logger) (domain.FlatsByHouseResponse, error) {
+func (m *MockHouseUsecase) GetFlatsByHouseID(ctx context.Context, id int, status, cursor string, limit int, lg *zap.Logger) (domain.FlatsByHouseResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFlatsByHouseID", ctx, id, status, lg)
+	ret := m.ctrl.Call(m, "GetFlatsByHouseID", ctx, id, status, cursor, limit, lg)
 	ret0, _ := ret[0].(domain.FlatsByHouseResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFlatsByHouseID indicates an expected call of GetFlatsByHouseID.
-func (mr *MockHouseUsecaseMockRecorder) GetFlatsByHouseID(ctx, id, status, lg any) *gomock.Call {
+func (mr *MockHouseUsecaseMockRecorder) GetFlatsByHouseID(ctx, id, status, cursor, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlatsByHouseID", reflect.TypeOf((*MockHouseUsecase)(nil).GetFlatsByHouseID), ctx, id, status, cursor, limit, lg)
+}
+
+// GetNearby mocks base method.
+func (m *MockHouseUsecase) GetNearby(ctx context.Context, req *domain.NearbyHousesRequest, lg *zap.Logger) (domain.NearbyHousesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNearby", ctx, req, lg)
+	ret0, _ := ret[0].(domain.NearbyHousesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNearby indicates an expected call of GetNearby.
+func (mr *MockHouseUsecaseMockRecorder) GetNearby(ctx, req, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNearby", reflect.TypeOf((*MockHouseUsecase)(nil).GetNearby), ctx, req, lg)
+}
+
+// GetStats mocks base method.
+func (m *MockHouseUsecase) GetStats(ctx context.Context, id int, status string, lg *zap.Logger) (domain.HouseStatsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx, id, status, lg)
+	ret0, _ := ret[0].(domain.HouseStatsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockHouseUsecaseMockRecorder) GetStats(ctx, id, status, lg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlatsByHouseID", reflect.TypeOf((*MockHouseUsecase)(nil).GetFlatsByHouseID), ctx, id, status, lg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockHouseUsecase)(nil).GetStats), ctx, id, status, lg)
+}
+
+// Import mocks base method.
+func (m *MockHouseUsecase) Import(ctx context.Context, format string, file io.Reader, lg *zap.Logger) (domain.ImportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, format, file, lg)
+	ret0, _ := ret[0].(domain.ImportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockHouseUsecaseMockRecorder) Import(ctx, format, file, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockHouseUsecase)(nil).Import), ctx, format, file, lg)
+}
+
+// List mocks base method.
+func (m *MockHouseUsecase) List(ctx context.Context, req *domain.ListHousesRequest, lg *zap.Logger) (domain.ListHousesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, req, lg)
+	ret0, _ := ret[0].(domain.ListHousesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockHouseUsecaseMockRecorder) List(ctx, req, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockHouseUsecase)(nil).List), ctx, req, lg)
 }
 
 // Notifying mocks base method.
@@ -85,6 +161,35 @@ func (mr *MockHouseUsecaseMockRecorder) Notifying(done, frequency, timeout, lg a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notifying", reflect.TypeOf((*MockHouseUsecase)(nil).Notifying), done, frequency, timeout, lg)
 }
 
+// SearchByAddress mocks base method.
+func (m *MockHouseUsecase) SearchByAddress(ctx context.Context, query string, limit int, lg *zap.Logger) (domain.SearchHousesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchByAddress", ctx, query, limit, lg)
+	ret0, _ := ret[0].(domain.SearchHousesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchByAddress indicates an expected call of SearchByAddress.
+func (mr *MockHouseUsecaseMockRecorder) SearchByAddress(ctx, query, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchByAddress", reflect.TypeOf((*MockHouseUsecase)(nil).SearchByAddress), ctx, query, limit, lg)
+}
+
+// StreamFlatsByHouseID mocks base method.
+func (m *MockHouseUsecase) StreamFlatsByHouseID(ctx context.Context, id int, status string, handle func(domain.SingleFlatResponse) error, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamFlatsByHouseID", ctx, id, status, handle, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamFlatsByHouseID indicates an expected call of StreamFlatsByHouseID.
+func (mr *MockHouseUsecaseMockRecorder) StreamFlatsByHouseID(ctx, id, status, handle, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamFlatsByHouseID", reflect.TypeOf((*MockHouseUsecase)(nil).StreamFlatsByHouseID), ctx, id, status, handle, lg)
+}
+
 // SubscribeByID mocks base method.
 func (m *MockHouseUsecase) SubscribeByID(ctx context.Context, id int, userID uuid.UUID, lg *zap.Logger) error {
 	m.ctrl.T.Helper()
@@ -99,6 +204,21 @@ func (mr *MockHouseUsecaseMockRecorder) SubscribeByID(ctx, id, userID, lg any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeByID", reflect.TypeOf((*MockHouseUsecase)(nil).SubscribeByID), ctx, id, userID, lg)
 }
 
+// Update mocks base method.
+func (m *MockHouseUsecase) Update(ctx context.Context, id int, req *domain.UpdateHouseRequest, lg *zap.Logger) (domain.CreateHouseResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, id, req, lg)
+	ret0, _ := ret[0].(domain.CreateHouseResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockHouseUsecaseMockRecorder) Update(ctx, id, req, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockHouseUsecase)(nil).Update), ctx, id, req, lg)
+}
+
 // MockHouseRepo is a mock of HouseRepo interface.
 type MockHouseRepo struct {
 	ctrl     *gomock.Controller
@@ -122,6 +242,21 @@ func (m *MockHouseRepo) EXPECT() *MockHouseRepoMockRecorder {
 	return m.recorder
 }
 
+// CopyHouses mocks base method.
+func (m *MockHouseRepo) CopyHouses(ctx context.Context, houses []domain.House, lg *zap.Logger) ([]domain.House, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyHouses", ctx, houses, lg)
+	ret0, _ := ret[0].([]domain.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopyHouses indicates an expected call of CopyHouses.
+func (mr *MockHouseRepoMockRecorder) CopyHouses(ctx, houses, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyHouses", reflect.TypeOf((*MockHouseRepo)(nil).CopyHouses), ctx, houses, lg)
+}
+
 // Create mocks base method.
 func (m *MockHouseRepo) Create(ctx context.Context, house *domain.House, lg *zap.Logger) (domain.House, error) {
 	m.ctrl.T.Helper()
@@ -152,18 +287,33 @@ func (mr *MockHouseRepoMockRecorder) DeleteByID(ctx, id, lg any) *gomock.Call {
 }
 
 // GetAll mocks base method.
-func (m *MockHouseRepo) GetAll(ctx context.Context, offset, limit int, lg *zap.Logger) ([]domain.House, error) {
+func (m *MockHouseRepo) GetAll(ctx context.Context, afterID, limit int, lg *zap.Logger) ([]domain.House, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx, offset, limit, lg)
+	ret := m.ctrl.Call(m, "GetAll", ctx, afterID, limit, lg)
 	ret0, _ := ret[0].([]domain.House)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockHouseRepoMockRecorder) GetAll(ctx, offset, limit, lg any) *gomock.Call {
+func (mr *MockHouseRepoMockRecorder) GetAll(ctx, afterID, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockHouseRepo)(nil).GetAll), ctx, afterID, limit, lg)
+}
+
+// GetByDeveloperID mocks base method.
+func (m *MockHouseRepo) GetByDeveloperID(ctx context.Context, developerID, afterID, limit int, lg *zap.Logger) ([]domain.House, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDeveloperID", ctx, developerID, afterID, limit, lg)
+	ret0, _ := ret[0].([]domain.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDeveloperID indicates an expected call of GetByDeveloperID.
+func (mr *MockHouseRepoMockRecorder) GetByDeveloperID(ctx, developerID, afterID, limit, lg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockHouseRepo)(nil).GetAll), ctx, offset, limit, lg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDeveloperID", reflect.TypeOf((*MockHouseRepo)(nil).GetByDeveloperID), ctx, developerID, afterID, limit, lg)
 }
 
 // GetByID mocks base method.
@@ -182,18 +332,107 @@ func (mr *MockHouseRepoMockRecorder) GetByID(ctx, id, lg any) *gomock.Call {
 }
 
 // GetFlatsByHouseID mocks base method.
-func (m *MockHouseRepo) GetFlatsByHouseID(ctx context.Context, id int, status string, lg *zap.Logger) ([]domain.Flat, error) {
+func (m *MockHouseRepo) GetFlatsByHouseID(ctx context.Context, id int, status string, afterFlatID, limit int, lg *zap.Logger) ([]domain.Flat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFlatsByHouseID", ctx, id, status, lg)
+	ret := m.ctrl.Call(m, "GetFlatsByHouseID", ctx, id, status, afterFlatID, limit, lg)
 	ret0, _ := ret[0].([]domain.Flat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFlatsByHouseID indicates an expected call of GetFlatsByHouseID.
-func (mr *MockHouseRepoMockRecorder) GetFlatsByHouseID(ctx, id, status, lg any) *gomock.Call {
+func (mr *MockHouseRepoMockRecorder) GetFlatsByHouseID(ctx, id, status, afterFlatID, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlatsByHouseID", reflect.TypeOf((*MockHouseRepo)(nil).GetFlatsByHouseID), ctx, id, status, afterFlatID, limit, lg)
+}
+
+// GetNearby mocks base method.
+func (m *MockHouseRepo) GetNearby(ctx context.Context, center domain.GeoPoint, radiusKm float64, limit int, lg *zap.Logger) ([]domain.HouseDistance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNearby", ctx, center, radiusKm, limit, lg)
+	ret0, _ := ret[0].([]domain.HouseDistance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNearby indicates an expected call of GetNearby.
+func (mr *MockHouseRepoMockRecorder) GetNearby(ctx, center, radiusKm, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNearby", reflect.TypeOf((*MockHouseRepo)(nil).GetNearby), ctx, center, radiusKm, limit, lg)
+}
+
+// GetStats mocks base method.
+func (m *MockHouseRepo) GetStats(ctx context.Context, id int, status string, lg *zap.Logger) (domain.HouseStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx, id, status, lg)
+	ret0, _ := ret[0].(domain.HouseStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockHouseRepoMockRecorder) GetStats(ctx, id, status, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockHouseRepo)(nil).GetStats), ctx, id, status, lg)
+}
+
+// GetWithApprovedFlats mocks base method.
+func (m *MockHouseRepo) GetWithApprovedFlats(ctx context.Context, lg *zap.Logger) ([]domain.HouseFlatsCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithApprovedFlats", ctx, lg)
+	ret0, _ := ret[0].([]domain.HouseFlatsCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithApprovedFlats indicates an expected call of GetWithApprovedFlats.
+func (mr *MockHouseRepoMockRecorder) GetWithApprovedFlats(ctx, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithApprovedFlats", reflect.TypeOf((*MockHouseRepo)(nil).GetWithApprovedFlats), ctx, lg)
+}
+
+// Search mocks base method.
+func (m *MockHouseRepo) Search(ctx context.Context, filter *domain.HouseFilter, lg *zap.Logger) ([]domain.House, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, filter, lg)
+	ret0, _ := ret[0].([]domain.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockHouseRepoMockRecorder) Search(ctx, filter, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockHouseRepo)(nil).Search), ctx, filter, lg)
+}
+
+// SearchByAddress mocks base method.
+func (m *MockHouseRepo) SearchByAddress(ctx context.Context, query, altQuery string, limit int, lg *zap.Logger) ([]domain.HouseMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchByAddress", ctx, query, altQuery, limit, lg)
+	ret0, _ := ret[0].([]domain.HouseMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchByAddress indicates an expected call of SearchByAddress.
+func (mr *MockHouseRepoMockRecorder) SearchByAddress(ctx, query, altQuery, limit, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchByAddress", reflect.TypeOf((*MockHouseRepo)(nil).SearchByAddress), ctx, query, altQuery, limit, lg)
+}
+
+// StreamFlatsByHouseID mocks base method.
+func (m *MockHouseRepo) StreamFlatsByHouseID(ctx context.Context, id int, status string, handle func(domain.Flat) error, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamFlatsByHouseID", ctx, id, status, handle, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamFlatsByHouseID indicates an expected call of StreamFlatsByHouseID.
+func (mr *MockHouseRepoMockRecorder) StreamFlatsByHouseID(ctx, id, status, handle, lg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlatsByHouseID", reflect.TypeOf((*MockHouseRepo)(nil).GetFlatsByHouseID), ctx, id, status, lg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamFlatsByHouseID", reflect.TypeOf((*MockHouseRepo)(nil).StreamFlatsByHouseID), ctx, id, status, handle, lg)
 }
 
 // SubscribeByID mocks base method.
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: login_attempt.go
+//
+// Generated by this command:
+//
+//	mockgen -source=login_attempt.go
+//
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	domain "avito-test-task/internal/domain"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+	zap "go.uber.org/zap"
+)
+
+// MockLoginAttemptRepo is a mock of LoginAttemptRepo interface.
+type MockLoginAttemptRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptRepoMockRecorder
+}
+
+// MockLoginAttemptRepoMockRecorder is the mock recorder for MockLoginAttemptRepo.
+type MockLoginAttemptRepoMockRecorder struct {
+	mock *MockLoginAttemptRepo
+}
+
+// NewMockLoginAttemptRepo creates a new mock instance.
+func NewMockLoginAttemptRepo(ctrl *gomock.Controller) *MockLoginAttemptRepo {
+	mock := &MockLoginAttemptRepo{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptRepo) EXPECT() *MockLoginAttemptRepoMockRecorder {
+	return m.recorder
+}
+
+// DeleteStale mocks base method.
+func (m *MockLoginAttemptRepo) DeleteStale(ctx context.Context, window time.Duration, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStale", ctx, window, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStale indicates an expected call of DeleteStale.
+func (mr *MockLoginAttemptRepoMockRecorder) DeleteStale(ctx, window, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStale", reflect.TypeOf((*MockLoginAttemptRepo)(nil).DeleteStale), ctx, window, lg)
+}
+
+// ForgiveAttempt mocks base method.
+func (m *MockLoginAttemptRepo) ForgiveAttempt(ctx context.Context, scope, subject string, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgiveAttempt", ctx, scope, subject, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgiveAttempt indicates an expected call of ForgiveAttempt.
+func (mr *MockLoginAttemptRepoMockRecorder) ForgiveAttempt(ctx, scope, subject, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgiveAttempt", reflect.TypeOf((*MockLoginAttemptRepo)(nil).ForgiveAttempt), ctx, scope, subject, lg)
+}
+
+// RegisterAttempt mocks base method.
+func (m *MockLoginAttemptRepo) RegisterAttempt(ctx context.Context, scope, subject string, window time.Duration, lg *zap.Logger) (domain.LoginFailures, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterAttempt", ctx, scope, subject, window, lg)
+	ret0, _ := ret[0].(domain.LoginFailures)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterAttempt indicates an expected call of RegisterAttempt.
+func (mr *MockLoginAttemptRepoMockRecorder) RegisterAttempt(ctx, scope, subject, window, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAttempt", reflect.TypeOf((*MockLoginAttemptRepo)(nil).RegisterAttempt), ctx, scope, subject, window, lg)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttemptRepo) Reset(ctx context.Context, scope, subject string, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, scope, subject, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptRepoMockRecorder) Reset(ctx, scope, subject, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttemptRepo)(nil).Reset), ctx, scope, subject, lg)
+}
@@ -0,0 +1,179 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: token.go
+//
+// Generated by this command:
+//
+//	mockgen -source=token.go
+//
+
+// Package mock_domain is a generated GoMock package.
+package mock_domain
+
+import (
+	domain "avito-test-task/internal/domain"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+	zap "go.uber.org/zap"
+)
+
+// MockTokenDenyList is a mock of TokenDenyList interface.
+type MockTokenDenyList struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenDenyListMockRecorder
+}
+
+// MockTokenDenyListMockRecorder is the mock recorder for MockTokenDenyList.
+type MockTokenDenyListMockRecorder struct {
+	mock *MockTokenDenyList
+}
+
+// NewMockTokenDenyList creates a new mock instance.
+func NewMockTokenDenyList(ctrl *gomock.Controller) *MockTokenDenyList {
+	mock := &MockTokenDenyList{ctrl: ctrl}
+	mock.recorder = &MockTokenDenyListMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenDenyList) EXPECT() *MockTokenDenyListMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method.
+func (m *MockTokenDenyList) IsRevoked(tokenID string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", tokenID)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockTokenDenyListMockRecorder) IsRevoked(tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockTokenDenyList)(nil).IsRevoked), tokenID)
+}
+
+// Revoke mocks base method.
+func (m *MockTokenDenyList) Revoke(tokenID string, expiresAt time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Revoke", tokenID, expiresAt)
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockTokenDenyListMockRecorder) Revoke(tokenID, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockTokenDenyList)(nil).Revoke), tokenID, expiresAt)
+}
+
+// MockTokenRepo is a mock of TokenRepo interface.
+type MockTokenRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenRepoMockRecorder
+}
+
+// MockTokenRepoMockRecorder is the mock recorder for MockTokenRepo.
+type MockTokenRepoMockRecorder struct {
+	mock *MockTokenRepo
+}
+
+// NewMockTokenRepo creates a new mock instance.
+func NewMockTokenRepo(ctrl *gomock.Controller) *MockTokenRepo {
+	mock := &MockTokenRepo{ctrl: ctrl}
+	mock.recorder = &MockTokenRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenRepo) EXPECT() *MockTokenRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTokenRepo) Create(ctx context.Context, token *domain.RefreshToken, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTokenRepoMockRecorder) Create(ctx, token, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTokenRepo)(nil).Create), ctx, token, lg)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockTokenRepo) DeleteExpired(ctx context.Context, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockTokenRepoMockRecorder) DeleteExpired(ctx, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockTokenRepo)(nil).DeleteExpired), ctx, lg)
+}
+
+// GetRevokedAccessTokens mocks base method.
+func (m *MockTokenRepo) GetRevokedAccessTokens(ctx context.Context, since time.Time, lg *zap.Logger) ([]domain.RevokedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevokedAccessTokens", ctx, since, lg)
+	ret0, _ := ret[0].([]domain.RevokedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevokedAccessTokens indicates an expected call of GetRevokedAccessTokens.
+func (mr *MockTokenRepoMockRecorder) GetRevokedAccessTokens(ctx, since, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevokedAccessTokens", reflect.TypeOf((*MockTokenRepo)(nil).GetRevokedAccessTokens), ctx, since, lg)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockTokenRepo) RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, tokenID, expiresAt, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockTokenRepoMockRecorder) RevokeAccessToken(ctx, tokenID, expiresAt, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockTokenRepo)(nil).RevokeAccessToken), ctx, tokenID, expiresAt, lg)
+}
+
+// RevokeFamily mocks base method.
+func (m *MockTokenRepo) RevokeFamily(ctx context.Context, tokenHash string, userID uuid.UUID, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", ctx, tokenHash, userID, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockTokenRepoMockRecorder) RevokeFamily(ctx, tokenHash, userID, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockTokenRepo)(nil).RevokeFamily), ctx, tokenHash, userID, lg)
+}
+
+// Use mocks base method.
+func (m *MockTokenRepo) Use(ctx context.Context, tokenHash string, lg *zap.Logger) (domain.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Use", ctx, tokenHash, lg)
+	ret0, _ := ret[0].(domain.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Use indicates an expected call of Use.
+func (mr *MockTokenRepoMockRecorder) Use(ctx, tokenHash, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockTokenRepo)(nil).Use), ctx, tokenHash, lg)
+}
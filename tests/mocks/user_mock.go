@@ -72,6 +72,35 @@ func (mr *MockUserUsecaseMockRecorder) Login(ctx, userReq, lg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUsecase)(nil).Login), ctx, userReq, lg)
 }
 
+// Logout mocks base method.
+func (m *MockUserUsecase) Logout(ctx context.Context, accessToken string, req *domain.LogoutRequest, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, accessToken, req, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserUsecaseMockRecorder) Logout(ctx, accessToken, req, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserUsecase)(nil).Logout), ctx, accessToken, req, lg)
+}
+
+// Refresh mocks base method.
+func (m *MockUserUsecase) Refresh(ctx context.Context, req *domain.RefreshTokenRequest, lg *zap.Logger) (domain.LoginUserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, req, lg)
+	ret0, _ := ret[0].(domain.LoginUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserUsecaseMockRecorder) Refresh(ctx, req, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserUsecase)(nil).Refresh), ctx, req, lg)
+}
+
 // Register mocks base method.
 func (m *MockUserUsecase) Register(ctx context.Context, userReq *domain.RegisterUserRequest, lg *zap.Logger) (domain.RegisterUserResponse, error) {
 	m.ctrl.T.Helper()
@@ -87,6 +116,20 @@ func (mr *MockUserUsecaseMockRecorder) Register(ctx, userReq, lg any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUsecase)(nil).Register), ctx, userReq, lg)
 }
 
+// Unlock mocks base method.
+func (m *MockUserUsecase) Unlock(ctx context.Context, userID uuid.UUID, lg *zap.Logger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, userID, lg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockUserUsecaseMockRecorder) Unlock(ctx, userID, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockUserUsecase)(nil).Unlock), ctx, userID, lg)
+}
+
 // MockUserRepo is a mock of UserRepo interface.
 type MockUserRepo struct {
 	ctrl     *gomock.Controller
@@ -168,6 +211,21 @@ func (mr *MockUserRepoMockRecorder) GetByID(ctx, id, lg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepo)(nil).GetByID), ctx, id, lg)
 }
 
+// GetByMail mocks base method.
+func (m *MockUserRepo) GetByMail(ctx context.Context, mail string, lg *zap.Logger) (domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByMail", ctx, mail, lg)
+	ret0, _ := ret[0].(domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByMail indicates an expected call of GetByMail.
+func (mr *MockUserRepoMockRecorder) GetByMail(ctx, mail, lg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByMail", reflect.TypeOf((*MockUserRepo)(nil).GetByMail), ctx, mail, lg)
+}
+
 // Update mocks base method.
 func (m *MockUserRepo) Update(ctx context.Context, newUserData *domain.User, lg *zap.Logger) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,104 @@
+//go:build unit
+// +build unit
+
+package tests
+
+import (
+	"avito-test-task/internal/domain"
+	"avito-test-task/internal/repo"
+	"avito-test-task/internal/usecase"
+	"avito-test-task/pkg"
+	mock_domain "avito-test-task/tests/mocks"
+	"context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type TokenRepoTest struct {
+	suite.Suite
+	mockLg *zap.Logger
+}
+
+func (r *TokenRepoTest) BeforeAll(t provider.T) {
+	t.Log("Init log")
+	r.mockLg = pkg.CreateMockLogger()
+}
+
+func (r *TokenRepoTest) TestUnknownUseToken(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	usedRowMock := mock_domain.NewMockRow(ctrl)
+	stateRowMock := mock_domain.NewMockRow(ctrl)
+	tokenRepo := repo.NewPostgresTokenRepo(poolMock, nil)
+
+	gomock.InOrder(
+		poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), "hash").Return(usedRowMock),
+		poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), "hash").Return(stateRowMock),
+	)
+	usedRowMock.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+	stateRowMock.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+
+	_, err := tokenRepo.Use(context.Background(), "hash", r.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrToken_BadRefresh)
+}
+
+func (r *TokenRepoTest) TestReusedUseToken(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	usedRowMock := mock_domain.NewMockRow(ctrl)
+	stateRowMock := mock_domain.NewMockRow(ctrl)
+	tokenRepo := repo.NewPostgresTokenRepo(poolMock, nil)
+
+	gomock.InOrder(
+		poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), "hash").Return(usedRowMock),
+		poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), "hash").Return(stateRowMock),
+	)
+	usedRowMock.EXPECT().Scan(gomock.Any()).Return(pgx.ErrNoRows)
+	stateRowMock.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...any) error {
+		*dest[1].(*bool) = true
+		return nil
+	})
+	poolMock.EXPECT().Exec(context.Background(), gomock.Any(), gomock.Any()).
+		Return(pgconn.NewCommandTag("UPDATE 2"), nil)
+
+	_, err := tokenRepo.Use(context.Background(), "hash", r.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrToken_Reused)
+}
+
+func (r *TokenRepoTest) TestSyncTokenDenyList(t provider.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepoMock := mock_domain.NewMockTokenRepo(ctrl)
+	done := make(chan bool, 1)
+	done <- true
+	denyList := usecase.NewTokenDenyList(tokenRepoMock, done, time.Second, time.Second, r.mockLg)
+
+	revokedAt := time.Now()
+	tokenRepoMock.EXPECT().GetRevokedAccessTokens(context.Background(), time.Time{}, r.mockLg).
+		Return([]domain.RevokedToken{
+			{TokenID: "revoked", ExpiresAt: revokedAt.Add(time.Minute), RevokedAt: revokedAt},
+			{TokenID: "expired", ExpiresAt: revokedAt.Add(-time.Minute), RevokedAt: revokedAt},
+		}, nil)
+	tokenRepoMock.EXPECT().GetRevokedAccessTokens(context.Background(), revokedAt.Add(-time.Minute), r.mockLg).
+		Return(nil, nil)
+
+	err := denyList.Sync(context.Background(), r.mockLg)
+	t.Require().Nil(err)
+	err = denyList.Sync(context.Background(), r.mockLg)
+	t.Require().Nil(err)
+
+	t.Require().True(denyList.IsRevoked("revoked"))
+	t.Require().False(denyList.IsRevoked("expired"))
+	t.Require().False(denyList.IsRevoked("active"))
+}
+
+func TestTokenRepoSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TokenRepoTest))
+}
@@ -0,0 +1,243 @@
+//go:build unit
+// +build unit
+
+package tests
+
+import (
+	"avito-test-task/internal/domain"
+	"avito-test-task/pkg"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type TokenTest struct {
+	suite.Suite
+	userID uuid.UUID
+}
+
+func (s *TokenTest) BeforeAll(t provider.T) {
+	s.userID = uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db40")
+}
+
+func (s *TokenTest) claims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":  pkg.Issuer,
+		"aud":  pkg.Audience,
+		"sub":  s.userID.String(),
+		"jti":  uuid.NewString(),
+		"iat":  jwt.NewNumericDate(now),
+		"nbf":  jwt.NewNumericDate(now),
+		"exp":  jwt.NewNumericDate(now.Add(time.Minute)),
+		"role": domain.Client,
+	}
+}
+
+func signToken(method jwt.SigningMethod, kid string, claims jwt.MapClaims, key any) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	tokenString, _ := token.SignedString(key)
+	return tokenString
+}
+
+func (s *TokenTest) TestNormalValidateToken(t provider.T) {
+	token, tokenID, _, err := pkg.GenerateAccessToken(s.userID, domain.Moderator)
+	t.Require().Nil(err)
+
+	claims, err := pkg.ValidateJWTToken(token)
+
+	t.Require().Nil(err)
+	t.Require().Equal(s.userID.String(), (*claims)["sub"])
+	t.Require().Equal(tokenID, (*claims)["jti"])
+	t.Require().Equal(domain.Moderator, (*claims)["role"])
+}
+
+func (s *TokenTest) TestExpiredValidateToken(t provider.T) {
+	claims := s.claims()
+	claims["iat"] = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	claims["exp"] = jwt.NewNumericDate(time.Now().Add(-pkg.Leeway - time.Second))
+
+	_, err := pkg.ValidateJWTToken(signToken(jwt.SigningMethodHS256, pkg.KeyID, claims, []byte(pkg.Key)))
+
+	t.Require().ErrorIs(err, jwt.ErrTokenExpired)
+}
+
+func (s *TokenTest) TestLeewayValidateToken(t provider.T) {
+	claims := s.claims()
+	claims["exp"] = jwt.NewNumericDate(time.Now().Add(-pkg.Leeway / 2))
+
+	_, err := pkg.ValidateJWTToken(signToken(jwt.SigningMethodHS256, pkg.KeyID, claims, []byte(pkg.Key)))
+
+	t.Require().Nil(err)
+}
+
+func (s *TokenTest) TestNoExpValidateToken(t provider.T) {
+	claims := s.claims()
+	delete(claims, "exp")
+
+	_, err := pkg.ValidateJWTToken(signToken(jwt.SigningMethodHS256, pkg.KeyID, claims, []byte(pkg.Key)))
+
+	t.Require().ErrorIs(err, jwt.ErrTokenRequiredClaimMissing)
+}
+
+func (s *TokenTest) TestBadAudienceValidateToken(t provider.T) {
+	claims := s.claims()
+	claims["aud"] = "other-service"
+
+	_, err := pkg.ValidateJWTToken(signToken(jwt.SigningMethodHS256, pkg.KeyID, claims, []byte(pkg.Key)))
+
+	t.Require().ErrorIs(err, jwt.ErrTokenInvalidAudience)
+}
+
+func (s *TokenTest) TestNotAllowedAlgValidateToken(t provider.T) {
+	hs512Token := signToken(jwt.SigningMethodHS512, pkg.KeyID, s.claims(), []byte(pkg.Key))
+	noneToken := signToken(jwt.SigningMethodNone, pkg.KeyID, s.claims(), jwt.UnsafeAllowNoneSignatureType)
+
+	_, hs512Err := pkg.ValidateJWTToken(hs512Token)
+	_, noneErr := pkg.ValidateJWTToken(noneToken)
+
+	t.Require().ErrorIs(hs512Err, jwt.ErrTokenSignatureInvalid)
+	t.Require().ErrorIs(noneErr, jwt.ErrTokenSignatureInvalid)
+}
+
+func (s *TokenTest) TestRotatedKeyValidateToken(t provider.T) {
+	key, keyID, previousKeys := pkg.Key, pkg.KeyID, pkg.PreviousKeys
+	defer func() {
+		pkg.Key, pkg.KeyID, pkg.PreviousKeys = key, keyID, previousKeys
+	}()
+
+	pkg.Key, pkg.KeyID = "old-secret", "old"
+	oldToken, _, _, err := pkg.GenerateAccessToken(s.userID, domain.Client)
+	t.Require().Nil(err)
+
+	pkg.Key, pkg.KeyID = "new-secret", "new"
+	_, err = pkg.ValidateJWTToken(oldToken)
+	t.Require().ErrorIs(err, jwt.ErrTokenUnverifiable)
+
+	pkg.PreviousKeys = map[string]string{"old": "old-secret"}
+	_, err = pkg.ValidateJWTToken(oldToken)
+	t.Require().Nil(err)
+
+	newToken, _, _, err := pkg.GenerateAccessToken(s.userID, domain.Client)
+	t.Require().Nil(err)
+	_, err = pkg.ValidateJWTToken(newToken)
+	t.Require().Nil(err)
+}
+
+// writeKeyFiles stores the keys as PEM files and returns the paths of the private and the public one.
+func writeKeyFiles(t provider.T, privateKey crypto.Signer) (string, string) {
+	dir, err := os.MkdirTemp("", "keys")
+	t.Require().Nil(err)
+
+	privateDer, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	t.Require().Nil(err)
+	publicDer, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	t.Require().Nil(err)
+
+	privatePath := filepath.Join(dir, "private.pem")
+	publicPath := filepath.Join(dir, "public.pem")
+	err = os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDer}), 0600)
+	t.Require().Nil(err)
+	err = os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}), 0600)
+	t.Require().Nil(err)
+
+	return privatePath, publicPath
+}
+
+func restoreSigningKeys() func() {
+	key, keyID, algorithm := pkg.Key, pkg.KeyID, pkg.Algorithm
+	privateKey, previousPublicKeys := pkg.PrivateKey, pkg.PreviousPublicKeys
+
+	return func() {
+		pkg.Key, pkg.KeyID, pkg.Algorithm = key, keyID, algorithm
+		pkg.PrivateKey, pkg.PreviousPublicKeys = privateKey, previousPublicKeys
+	}
+}
+
+func (s *TokenTest) TestRS256ValidateToken(t provider.T) {
+	defer restoreSigningKeys()()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	t.Require().Nil(err)
+	privatePath, _ := writeKeyFiles(t, rsaKey)
+	err = pkg.LoadSigningKeys(pkg.AlgorithmRS256, privatePath, nil)
+	t.Require().Nil(err)
+
+	token, _, _, err := pkg.GenerateAccessToken(s.userID, domain.Client)
+	t.Require().Nil(err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	t.Require().Nil(err)
+	t.Require().Equal(pkg.AlgorithmRS256, parsed.Method.Alg())
+
+	_, err = pkg.ValidateJWTToken(token)
+	t.Require().Nil(err)
+
+	// The public key is published, so it must not pass for an HMAC secret.
+	publicDer, _ := x509.MarshalPKIXPublicKey(rsaKey.Public())
+	forged := signToken(jwt.SigningMethodHS256, pkg.KeyID, s.claims(),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer}))
+	_, err = pkg.ValidateJWTToken(forged)
+	t.Require().ErrorIs(err, jwt.ErrTokenSignatureInvalid)
+}
+
+func (s *TokenTest) TestRotatedEdDSAValidateToken(t provider.T) {
+	defer restoreSigningKeys()()
+
+	_, oldKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, newKey, _ := ed25519.GenerateKey(rand.Reader)
+	oldPrivatePath, oldPublicPath := writeKeyFiles(t, oldKey)
+	newPrivatePath, _ := writeKeyFiles(t, newKey)
+
+	pkg.KeyID = "old"
+	err := pkg.LoadSigningKeys(pkg.AlgorithmEdDSA, oldPrivatePath, nil)
+	t.Require().Nil(err)
+	oldToken, _, _, err := pkg.GenerateAccessToken(s.userID, domain.Client)
+	t.Require().Nil(err)
+
+	pkg.KeyID = "new"
+	err = pkg.LoadSigningKeys(pkg.AlgorithmEdDSA, newPrivatePath, map[string]string{"old": oldPublicPath})
+	t.Require().Nil(err)
+
+	_, err = pkg.ValidateJWTToken(oldToken)
+	t.Require().Nil(err)
+
+	keySet := pkg.JWKS()
+	t.Require().Len(keySet.Keys, 2)
+	t.Require().Equal("new", keySet.Keys[0].Kid)
+	t.Require().Equal("old", keySet.Keys[1].Kid)
+	t.Require().Equal("OKP", keySet.Keys[1].Kty)
+	t.Require().Equal("Ed25519", keySet.Keys[1].Crv)
+	t.Require().Equal(pkg.AlgorithmEdDSA, keySet.Keys[1].Alg)
+}
+
+func (s *TokenTest) TestHS256JWKS(t provider.T) {
+	t.Require().Empty(pkg.JWKS().Keys)
+}
+
+func (s *TokenTest) TestBadAlgorithmLoadSigningKeys(t provider.T) {
+	defer restoreSigningKeys()()
+
+	err := pkg.LoadSigningKeys("HS512", "", nil)
+	t.Require().ErrorIs(err, pkg.ErrBadSigningKey)
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	privatePath, _ := writeKeyFiles(t, rsaKey)
+	err = pkg.LoadSigningKeys(pkg.AlgorithmRS256, privatePath, nil)
+	t.Require().ErrorIs(err, pkg.ErrBadSigningKey)
+}
+
+func TestTokenSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(TokenTest))
+}
@@ -9,19 +9,25 @@ import (
 	"avito-test-task/internal/usecase"
 	"avito-test-task/pkg"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/suite"
 	"go.uber.org/zap"
 	"os"
+	"sync"
 	"testing"
+	"time"
 )
 
 type UserIntegrationTest struct {
 	suite.Suite
 	userUsecase domain.UserUsecase
 	userRepo    domain.UserRepo
+	tokenRepo   domain.TokenRepo
+	denyList    *usecase.TokenDenyList
 	db          repo.IPool
 	mockLg      *zap.Logger
 	skipped     bool
@@ -38,8 +44,23 @@ func (u *UserIntegrationTest) BeforeAll(t provider.T) {
 		t.Fatalf("error while connecting to db: %v", err.Error())
 	}
 	u.userRepo = repo.NewPostrgesUserRepo(u.db, nil)
-	u.userUsecase = usecase.NewUserUsecase(u.userRepo)
 	u.mockLg = pkg.CreateMockLogger()
+	u.tokenRepo = repo.NewPostgresTokenRepo(u.db, nil)
+	done := make(chan bool, 1)
+	done <- true
+	u.denyList = usecase.NewTokenDenyList(u.tokenRepo, done, time.Second, time.Second, u.mockLg)
+	throttleDone := make(chan bool)
+	close(throttleDone)
+	loginThrottle := usecase.NewLoginThrottle(repo.NewPostgresLoginAttemptRepo(u.db, nil), domain.LoginPolicy{
+		FreeAttempts:        1,
+		BaseDelay:           time.Millisecond,
+		MaxDelay:            time.Millisecond,
+		AccountLockAttempts: 3,
+		IPLockAttempts:      100,
+		LockDuration:        time.Minute,
+		Window:              time.Hour,
+	}, throttleDone, time.Hour, time.Second, u.mockLg)
+	u.userUsecase = usecase.NewUserUsecase(u.userRepo, u.tokenRepo, u.denyList, loginThrottle)
 
 	args := os.Args
 	for _, arg := range args {
@@ -152,7 +173,7 @@ func (u *UserIntegrationTest) TestNormalClientLogin(t provider.T) {
 
 	loginResp, err := u.userUsecase.Login(context.Background(), &loginReq, u.mockLg)
 
-	userID, _ := pkg.ExtractPayloadFromToken(loginResp.Token, "userID")
+	userID, _ := pkg.ExtractPayloadFromToken(loginResp.Token, "sub")
 	userRole, _ := pkg.ExtractPayloadFromToken(loginResp.Token, "role")
 	t.Require().Nil(err)
 	t.Require().Equal(created.UserID, uuid.MustParse(userID))
@@ -179,13 +200,46 @@ func (u *UserIntegrationTest) TestNormalModeratorLogin(t provider.T) {
 
 	loginResp, err := u.userUsecase.Login(context.Background(), &loginReq, u.mockLg)
 
-	userID, _ := pkg.ExtractPayloadFromToken(loginResp.Token, "userID")
+	userID, _ := pkg.ExtractPayloadFromToken(loginResp.Token, "sub")
 	userRole, _ := pkg.ExtractPayloadFromToken(loginResp.Token, "role")
 	t.Require().Nil(err)
 	t.Require().Equal(created.UserID, uuid.MustParse(userID))
 	t.Require().Equal(userReq.UserType, userRole)
 }
 
+func (u *UserIntegrationTest) TestEmailLogin(t provider.T) {
+	if u.skipped {
+		t.Skip()
+	}
+
+	userReq := domain.RegisterUserRequest{
+		Email:    "MailLogin@mail.ru",
+		Password: "password",
+		UserType: domain.Client,
+	}
+
+	created, err := u.userUsecase.Register(context.Background(), &userReq, u.mockLg)
+	t.Require().Nil(err)
+
+	_, err = u.userUsecase.Register(context.Background(), &domain.RegisterUserRequest{
+		Email:    "maillogin@MAIL.RU",
+		Password: "other",
+		UserType: domain.Moderator,
+	}, u.mockLg)
+	t.Require().ErrorIs(err, domain.ErrUser_MailTaken)
+
+	loginReq := domain.LoginUserRequest{
+		Email:    "maillogin@mail.ru",
+		Password: userReq.Password,
+	}
+
+	loginResp, err := u.userUsecase.Login(context.Background(), &loginReq, u.mockLg)
+
+	userID, _ := pkg.ExtractPayloadFromToken(loginResp.Token, "sub")
+	t.Require().Nil(err)
+	t.Require().Equal(created.UserID, uuid.MustParse(userID))
+}
+
 func (u *UserIntegrationTest) TestBadNilRequestLogin(t provider.T) {
 	if u.skipped {
 		t.Skip()
@@ -252,6 +306,140 @@ func (u *UserIntegrationTest) TestBadUserTypeDummyLogin(t provider.T) {
 	t.Require().Empty(loginResp)
 }
 
+func (u *UserIntegrationTest) TestRefreshRotation(t provider.T) {
+	if u.skipped {
+		t.Skip()
+	}
+
+	userReq := domain.RegisterUserRequest{
+		Email:    "refresh@mail.ru",
+		Password: "password",
+		UserType: domain.Client,
+	}
+	created, _ := u.userUsecase.Register(context.Background(), &userReq, u.mockLg)
+	loginResp, err := u.userUsecase.Login(context.Background(),
+		&domain.LoginUserRequest{ID: created.UserID, Password: userReq.Password}, u.mockLg)
+	t.Require().Nil(err)
+
+	refreshResp, err := u.userUsecase.Refresh(context.Background(),
+		&domain.RefreshTokenRequest{RefreshToken: loginResp.RefreshToken}, u.mockLg)
+	t.Require().Nil(err)
+	t.Require().NotEqual(loginResp.RefreshToken, refreshResp.RefreshToken)
+
+	_, err = u.userUsecase.Refresh(context.Background(),
+		&domain.RefreshTokenRequest{RefreshToken: loginResp.RefreshToken}, u.mockLg)
+	t.Require().ErrorIs(err, domain.ErrToken_Reused)
+
+	_, err = u.userUsecase.Refresh(context.Background(),
+		&domain.RefreshTokenRequest{RefreshToken: refreshResp.RefreshToken}, u.mockLg)
+	t.Require().ErrorIs(err, domain.ErrToken_BadRefresh)
+
+	err = u.denyList.Sync(context.Background(), u.mockLg)
+	t.Require().Nil(err)
+	tokenID, _ := pkg.ExtractPayloadFromToken(refreshResp.Token, "jti")
+	t.Require().True(u.denyList.IsRevoked(tokenID))
+}
+
+func (u *UserIntegrationTest) TestLogout(t provider.T) {
+	if u.skipped {
+		t.Skip()
+	}
+
+	userReq := domain.RegisterUserRequest{
+		Email:    "logout@mail.ru",
+		Password: "password",
+		UserType: domain.Client,
+	}
+	created, _ := u.userUsecase.Register(context.Background(), &userReq, u.mockLg)
+	loginResp, err := u.userUsecase.Login(context.Background(),
+		&domain.LoginUserRequest{ID: created.UserID, Password: userReq.Password}, u.mockLg)
+	t.Require().Nil(err)
+
+	err = u.userUsecase.Logout(context.Background(), loginResp.Token,
+		&domain.LogoutRequest{RefreshToken: loginResp.RefreshToken}, u.mockLg)
+	t.Require().Nil(err)
+
+	tokenID, _ := pkg.ExtractPayloadFromToken(loginResp.Token, "jti")
+	t.Require().True(u.denyList.IsRevoked(tokenID))
+	_, err = u.userUsecase.Refresh(context.Background(),
+		&domain.RefreshTokenRequest{RefreshToken: loginResp.RefreshToken}, u.mockLg)
+	t.Require().ErrorIs(err, domain.ErrToken_BadRefresh)
+}
+
+func (u *UserIntegrationTest) TestLockoutLogin(t provider.T) {
+	if u.skipped {
+		t.Skip()
+	}
+
+	userReq := domain.RegisterUserRequest{
+		Email:    "lockout@mail.ru",
+		Password: "password",
+		UserType: domain.Client,
+	}
+	created, _ := u.userUsecase.Register(context.Background(), &userReq, u.mockLg)
+
+	badReq := domain.LoginUserRequest{Email: userReq.Email, Password: "badpassword", ClientIP: "10.0.0.5"}
+	for i := 0; i < 3; i++ {
+		time.Sleep(10 * time.Millisecond)
+		_, err := u.userUsecase.Login(context.Background(), &badReq, u.mockLg)
+		t.Require().Error(err)
+		t.Require().False(errors.Is(err, domain.ErrUser_Locked))
+	}
+
+	goodReq := domain.LoginUserRequest{Email: userReq.Email, Password: userReq.Password, ClientIP: "10.0.0.5"}
+	_, err := u.userUsecase.Login(context.Background(), &goodReq, u.mockLg)
+	t.Require().ErrorIs(err, domain.ErrUser_Locked)
+
+	err = u.userUsecase.Unlock(context.Background(), created.UserID, u.mockLg)
+	t.Require().Nil(err)
+	time.Sleep(10 * time.Millisecond)
+	_, err = u.userUsecase.Login(context.Background(), &goodReq, u.mockLg)
+	t.Require().Nil(err)
+}
+
+func (u *UserIntegrationTest) TestConcurrentLockoutLogin(t provider.T) {
+	if u.skipped {
+		t.Skip()
+	}
+
+	userReq := domain.RegisterUserRequest{
+		Email:    "concurrent-lockout@mail.ru",
+		Password: "password",
+		UserType: domain.Client,
+	}
+	_, err := u.userUsecase.Register(context.Background(), &userReq, u.mockLg)
+	t.Require().Nil(err)
+
+	const guesses = 10
+	errs := make(chan error, guesses)
+	var wg sync.WaitGroup
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			badReq := domain.LoginUserRequest{Email: userReq.Email, Password: "badpassword",
+				ClientIP: fmt.Sprintf("10.0.2.%d", i)}
+			_, err := u.userUsecase.Login(context.Background(), &badReq, u.mockLg)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	checked := 0
+	for err := range errs {
+		t.Require().Error(err)
+		if !errors.Is(err, domain.ErrUser_Locked) && !errors.Is(err, domain.ErrUser_TooManyAttempts) {
+			checked++
+		}
+	}
+	t.Require().LessOrEqual(checked, 3)
+
+	goodReq := domain.LoginUserRequest{Email: userReq.Email, Password: userReq.Password, ClientIP: "10.0.2.100"}
+	_, err = u.userUsecase.Login(context.Background(), &goodReq, u.mockLg)
+	t.Require().ErrorIs(err, domain.ErrUser_Locked)
+}
+
 func TestUserIntegrationSuiteRunner(t *testing.T) {
 	suite.RunSuite(t, new(UserIntegrationTest))
 }
@@ -72,6 +72,26 @@ func (u *UserRepoTest) TestExistsCreateUser(t provider.T) {
 	t.Require().Error(err)
 }
 
+func (u *UserRepoTest) TestMailTakenCreateUser(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	userRepo := repo.NewPostrgesUserRepo(poolMock, retryAdapter)
+
+	user := domain.User{
+		UserID:   uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db41"),
+		Mail:     "user1@mail.ru",
+		Password: "password",
+		Role:     domain.Client,
+	}
+	poolMock.EXPECT().Exec(context.Background(), gomock.Any(), user.UserID,
+		user.Mail, user.Password, user.Role).Return(pgconn.CommandTag{}, &pgconn.PgError{Code: "23505"})
+
+	err := userRepo.Create(context.Background(), &user, u.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrUser_MailTaken)
+}
+
 func (u *UserRepoTest) TestContextTimeoutCreateUser(t provider.T) {
 	ctrl := gomock.NewController(t)
 	poolMock := mock_domain.NewMockIPool(ctrl)
@@ -226,6 +246,21 @@ func (u *UserRepoTest) TestContextTimeoutGetByIDUser(t provider.T) {
 	t.Require().Equal(domain.User{}, usr)
 }
 
+func (u *UserRepoTest) TestNormalGetByMailUser(t provider.T) {
+	ctrl := gomock.NewController(t)
+	poolMock := mock_domain.NewMockIPool(ctrl)
+	rowMock := mock_domain.NewMockRow(ctrl)
+	retryAdapter := repo.NewPostgresRetryAdapter(poolMock, 3, time.Second)
+	userRepo := repo.NewPostrgesUserRepo(poolMock, retryAdapter)
+
+	poolMock.EXPECT().QueryRow(context.Background(), gomock.Any(), "user1@mail.ru").Return(rowMock)
+	rowMock.EXPECT().Scan(gomock.Any()).Return(nil)
+
+	_, err := userRepo.GetByMail(context.Background(), "user1@mail.ru", u.mockLg)
+
+	t.Require().Nil(err)
+}
+
 func (u *UserRepoTest) TestNormalGetAllUser(t provider.T) {
 	ctrl := gomock.NewController(t)
 	poolMock := mock_domain.NewMockIPool(ctrl)
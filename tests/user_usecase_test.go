@@ -10,29 +10,51 @@ import (
 	mock_domain "avito-test-task/tests/mocks"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/ozontech/allure-go/pkg/framework/provider"
 	"github.com/ozontech/allure-go/pkg/framework/suite"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"sync"
 	"testing"
+	"time"
 )
 
 type UserUsecaseTest struct {
 	suite.Suite
-	userRepoMock *mock_domain.MockUserRepo
-	mockLg       *zap.Logger
+	userRepoMock  *mock_domain.MockUserRepo
+	tokenRepoMock *mock_domain.MockTokenRepo
+	denyList      *mock_domain.MockTokenDenyList
+	attemptMock   *mock_domain.MockLoginAttemptRepo
+	loginThrottle *usecase.LoginThrottle
+	mockLg        *zap.Logger
 }
 
 func (u *UserUsecaseTest) BeforeAll(t provider.T) {
 	t.Log("Init mock")
 	ctrl := gomock.NewController(t)
 	u.userRepoMock = mock_domain.NewMockUserRepo(ctrl)
+	u.tokenRepoMock = mock_domain.NewMockTokenRepo(ctrl)
+	u.denyList = mock_domain.NewMockTokenDenyList(ctrl)
+	u.attemptMock = mock_domain.NewMockLoginAttemptRepo(ctrl)
 	u.mockLg = pkg.CreateMockLogger()
+
+	done := make(chan bool)
+	close(done)
+	u.loginThrottle = usecase.NewLoginThrottle(u.attemptMock, domain.LoginPolicy{
+		FreeAttempts:        3,
+		BaseDelay:           time.Second,
+		MaxDelay:            time.Minute,
+		AccountLockAttempts: 10,
+		IPLockAttempts:      100,
+		LockDuration:        15 * time.Minute,
+		Window:              time.Hour,
+	}, done, time.Hour, time.Second, u.mockLg)
 }
 
 func (u *UserUsecaseTest) TestNormalRegister(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 
 	req := domain.RegisterUserRequest{
 		Email:    "test@mail.ru",
@@ -48,7 +70,7 @@ func (u *UserUsecaseTest) TestNormalRegister(t provider.T) {
 }
 
 func (u *UserUsecaseTest) TestBadPasswordRegister(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 
 	req := domain.RegisterUserRequest{
 		Email:    "test@mail.ru",
@@ -62,7 +84,7 @@ func (u *UserUsecaseTest) TestBadPasswordRegister(t provider.T) {
 }
 
 func (u *UserUsecaseTest) TestBadMailRegister(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 
 	req := domain.RegisterUserRequest{
 		Email:    "testmail.ru",
@@ -76,7 +98,7 @@ func (u *UserUsecaseTest) TestBadMailRegister(t provider.T) {
 }
 
 func (u *UserUsecaseTest) TestBadUserTypeRegister(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 
 	req := domain.RegisterUserRequest{
 		Email:    "test@mail.ru",
@@ -90,7 +112,7 @@ func (u *UserUsecaseTest) TestBadUserTypeRegister(t provider.T) {
 }
 
 func (u *UserUsecaseTest) TestBadRepoCallRegister(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 
 	req := domain.RegisterUserRequest{
 		Email:    "test@mail.ru",
@@ -105,8 +127,28 @@ func (u *UserUsecaseTest) TestBadRepoCallRegister(t provider.T) {
 	t.Require().Error(err)
 }
 
+func (u *UserUsecaseTest) TestMailTakenRegister(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
+
+	req := domain.RegisterUserRequest{
+		Email:    " Test@Mail.ru ",
+		Password: "password",
+		UserType: domain.Client,
+	}
+
+	u.userRepoMock.EXPECT().Create(context.Background(), gomock.Any(), u.mockLg).
+		DoAndReturn(func(ctx context.Context, user *domain.User, lg *zap.Logger) error {
+			t.Require().Equal("test@mail.ru", user.Mail)
+			return domain.ErrUser_MailTaken
+		})
+
+	_, err := userUsecase.Register(context.Background(), &req, u.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrUser_MailTaken)
+}
+
 func (u *UserUsecaseTest) TestNormalLogin(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 	clientBuilder := NormalClientUserBuilder{}
 
 	clientBuilder.SetRole()
@@ -121,14 +163,16 @@ func (u *UserUsecaseTest) TestNormalLogin(t provider.T) {
 	}
 
 	u.userRepoMock.EXPECT().GetByID(context.Background(), req.ID, u.mockLg).Return(usr, nil)
+	u.tokenRepoMock.EXPECT().Create(context.Background(), gomock.Any(), u.mockLg).Return(nil)
 
-	_, err := userUsecase.Login(context.Background(), &req, u.mockLg)
+	loginResp, err := userUsecase.Login(context.Background(), &req, u.mockLg)
 
 	t.Require().Nil(err)
+	t.Require().NotEmpty(loginResp.RefreshToken)
 }
 
 func (u *UserUsecaseTest) TestBadPasswordLogin(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 	clientBuilder := NormalClientUserBuilder{}
 
 	clientBuilder.SetRole()
@@ -150,7 +194,7 @@ func (u *UserUsecaseTest) TestBadPasswordLogin(t provider.T) {
 }
 
 func (u *UserUsecaseTest) TestBadRepoCallLogin(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 
 	uid := uuid.New()
 	req := domain.LoginUserRequest{
@@ -166,7 +210,7 @@ func (u *UserUsecaseTest) TestBadRepoCallLogin(t provider.T) {
 }
 
 func (u *UserUsecaseTest) TestNormalDummyLogin(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 
 	u.userRepoMock.EXPECT().Create(context.Background(), gomock.Any(), u.mockLg)
 	_, err := userUsecase.DummyLogin(context.Background(), domain.Moderator, u.mockLg)
@@ -174,12 +218,313 @@ func (u *UserUsecaseTest) TestNormalDummyLogin(t provider.T) {
 }
 
 func (u *UserUsecaseTest) TestBadUserTypeDummyLogin(t provider.T) {
-	userUsecase := usecase.NewUserUsecase(u.userRepoMock)
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
 
 	_, err := userUsecase.DummyLogin(context.Background(), "user", u.mockLg)
 	t.Require().Error(err)
 }
 
+func (u *UserUsecaseTest) TestEmailLogin(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
+	clientBuilder := NormalClientUserBuilder{}
+
+	clientBuilder.SetRole()
+	clientBuilder.SetMail()
+	clientBuilder.SetPassword()
+	clientBuilder.SetUid("019126ee-2b7d-758e-bb22-fe2e45b2db40")
+	usr := clientBuilder.GetUser()
+
+	req := domain.LoginUserRequest{
+		Email:    "TEST@mail.ru",
+		Password: "password",
+	}
+
+	u.userRepoMock.EXPECT().GetByMail(context.Background(), "test@mail.ru", u.mockLg).Return(usr, nil)
+	u.tokenRepoMock.EXPECT().Create(context.Background(), gomock.Any(), u.mockLg).Return(nil)
+
+	loginResp, err := userUsecase.Login(context.Background(), &req, u.mockLg)
+
+	userID, _ := pkg.ExtractPayloadFromToken(loginResp.Token, "sub")
+	t.Require().Nil(err)
+	t.Require().Equal(usr.UserID.String(), userID)
+}
+
+func (u *UserUsecaseTest) TestNoMailNoIDLogin(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
+
+	req := domain.LoginUserRequest{
+		Password: "password",
+	}
+
+	_, err := userUsecase.Login(context.Background(), &req, u.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrUser_NoLogin)
+}
+
+func (u *UserUsecaseTest) TestNormalRefresh(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
+
+	usedToken := domain.RefreshToken{
+		FamilyID: uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db41"),
+		UserID:   uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db40"),
+		Role:     domain.Moderator,
+	}
+	req := domain.RefreshTokenRequest{RefreshToken: "refresh"}
+
+	u.tokenRepoMock.EXPECT().Use(context.Background(), pkg.HashRefreshToken("refresh"), u.mockLg).Return(usedToken, nil)
+	u.tokenRepoMock.EXPECT().Create(context.Background(), gomock.Any(), u.mockLg).
+		DoAndReturn(func(_ context.Context, token *domain.RefreshToken, _ *zap.Logger) error {
+			t.Require().Equal(usedToken.FamilyID, token.FamilyID)
+			return nil
+		})
+
+	refreshResp, err := userUsecase.Refresh(context.Background(), &req, u.mockLg)
+
+	userRole, _ := pkg.ExtractPayloadFromToken(refreshResp.Token, "role")
+	t.Require().Nil(err)
+	t.Require().Equal(domain.Moderator, userRole)
+	t.Require().NotEqual("refresh", refreshResp.RefreshToken)
+}
+
+func (u *UserUsecaseTest) TestReusedRefresh(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
+
+	req := domain.RefreshTokenRequest{RefreshToken: "reused"}
+
+	u.tokenRepoMock.EXPECT().Use(context.Background(), pkg.HashRefreshToken("reused"), u.mockLg).
+		Return(domain.RefreshToken{}, domain.ErrToken_Reused)
+
+	_, err := userUsecase.Refresh(context.Background(), &req, u.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrToken_Reused)
+}
+
+func (u *UserUsecaseTest) TestEmptyRefresh(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
+
+	_, err := userUsecase.Refresh(context.Background(), &domain.RefreshTokenRequest{}, u.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrToken_BadRefresh)
+}
+
+func (u *UserUsecaseTest) TestNormalLogout(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
+
+	userID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db40")
+	accessToken, tokenID, _, _ := pkg.GenerateAccessToken(userID, domain.Client)
+	req := domain.LogoutRequest{RefreshToken: "refresh"}
+
+	u.tokenRepoMock.EXPECT().RevokeAccessToken(context.Background(), tokenID, gomock.Any(), u.mockLg).Return(nil)
+	u.denyList.EXPECT().Revoke(tokenID, gomock.Any())
+	u.tokenRepoMock.EXPECT().RevokeFamily(context.Background(), pkg.HashRefreshToken("refresh"), userID, u.mockLg).Return(nil)
+
+	err := userUsecase.Logout(context.Background(), accessToken, &req, u.mockLg)
+
+	t.Require().Nil(err)
+}
+
+func (u *UserUsecaseTest) TestBadAccessLogout(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, nil)
+
+	err := userUsecase.Logout(context.Background(), "token", &domain.LogoutRequest{}, u.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrToken_BadAccess)
+}
+
+func (u *UserUsecaseTest) TestDelayedIPLogin(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, u.loginThrottle)
+
+	req := domain.LoginUserRequest{
+		Email:    "test@mail.ru",
+		Password: "password",
+		ClientIP: "10.0.0.1",
+	}
+
+	u.attemptMock.EXPECT().RegisterAttempt(context.Background(), domain.LoginScopeIP, "10.0.0.1", time.Hour, u.mockLg).
+		Return(domain.LoginFailures{Failures: 5, LastFailedAt: time.Now()}, nil)
+
+	_, err := userUsecase.Login(context.Background(), &req, u.mockLg)
+
+	var blockedErr *domain.LoginBlockedError
+	t.Require().ErrorIs(err, domain.ErrUser_TooManyAttempts)
+	t.Require().ErrorAs(err, &blockedErr)
+	t.Require().InDelta((2 * time.Second).Seconds(), blockedErr.RetryAfter.Seconds(), 1)
+}
+
+func (u *UserUsecaseTest) TestLockedAccountLogin(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, u.loginThrottle)
+	clientBuilder := NormalClientUserBuilder{}
+
+	clientBuilder.SetRole()
+	clientBuilder.SetMail()
+	clientBuilder.SetPassword()
+	clientBuilder.SetUid("019126ee-2b7d-758e-bb22-fe2e45b2db40")
+	usr := clientBuilder.GetUser()
+
+	req := domain.LoginUserRequest{
+		Email:    "test@mail.ru",
+		Password: "password",
+		ClientIP: "10.0.0.2",
+	}
+
+	u.attemptMock.EXPECT().RegisterAttempt(context.Background(), domain.LoginScopeIP, "10.0.0.2", time.Hour, u.mockLg).
+		Return(domain.LoginFailures{}, nil)
+	u.userRepoMock.EXPECT().GetByMail(context.Background(), "test@mail.ru", u.mockLg).Return(usr, nil)
+	u.attemptMock.EXPECT().RegisterAttempt(context.Background(), domain.LoginScopeAccount, usr.UserID.String(), time.Hour, u.mockLg).
+		Return(domain.LoginFailures{Failures: 10, LastFailedAt: time.Now().Add(-time.Minute)}, nil)
+
+	_, err := userUsecase.Login(context.Background(), &req, u.mockLg)
+
+	var blockedErr *domain.LoginBlockedError
+	t.Require().ErrorIs(err, domain.ErrUser_Locked)
+	t.Require().ErrorAs(err, &blockedErr)
+	t.Require().InDelta((14 * time.Minute).Seconds(), blockedErr.RetryAfter.Seconds(), 1)
+}
+
+func (u *UserUsecaseTest) TestBadPasswordCountedLogin(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, u.loginThrottle)
+	clientBuilder := NormalClientUserBuilder{}
+
+	clientBuilder.SetRole()
+	clientBuilder.SetMail()
+	clientBuilder.SetPassword()
+	clientBuilder.SetUid("019126ee-2b7d-758e-bb22-fe2e45b2db40")
+	usr := clientBuilder.GetUser()
+
+	req := domain.LoginUserRequest{
+		ID:       usr.UserID,
+		Password: "badpassword",
+		ClientIP: "10.0.0.3",
+	}
+
+	u.attemptMock.EXPECT().RegisterAttempt(context.Background(), domain.LoginScopeIP, "10.0.0.3", time.Hour, u.mockLg).
+		Return(domain.LoginFailures{}, nil)
+	u.userRepoMock.EXPECT().GetByID(context.Background(), req.ID, u.mockLg).Return(usr, nil)
+	// The account has waited out the delay after its fourth failure.
+	u.attemptMock.EXPECT().RegisterAttempt(context.Background(), domain.LoginScopeAccount, usr.UserID.String(), time.Hour, u.mockLg).
+		Return(domain.LoginFailures{Failures: 4, LastFailedAt: time.Now().Add(-2 * time.Second)}, nil)
+
+	_, err := userUsecase.Login(context.Background(), &req, u.mockLg)
+
+	t.Require().Error(err)
+	t.Require().False(errors.Is(err, domain.ErrUser_TooManyAttempts))
+}
+
+func (u *UserUsecaseTest) TestResetFailuresLogin(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, u.loginThrottle)
+	clientBuilder := NormalClientUserBuilder{}
+
+	clientBuilder.SetRole()
+	clientBuilder.SetMail()
+	clientBuilder.SetPassword()
+	clientBuilder.SetUid("019126ee-2b7d-758e-bb22-fe2e45b2db40")
+	usr := clientBuilder.GetUser()
+
+	req := domain.LoginUserRequest{
+		ID:       usr.UserID,
+		Password: "password",
+		ClientIP: "10.0.0.4",
+	}
+
+	u.attemptMock.EXPECT().RegisterAttempt(context.Background(), domain.LoginScopeIP, "10.0.0.4", time.Hour, u.mockLg).
+		Return(domain.LoginFailures{Failures: 2, LastFailedAt: time.Now()}, nil)
+	u.userRepoMock.EXPECT().GetByID(context.Background(), req.ID, u.mockLg).Return(usr, nil)
+	u.attemptMock.EXPECT().RegisterAttempt(context.Background(), domain.LoginScopeAccount, usr.UserID.String(), time.Hour, u.mockLg).
+		Return(domain.LoginFailures{Failures: 3, LastFailedAt: time.Now()}, nil)
+	u.tokenRepoMock.EXPECT().Create(context.Background(), gomock.Any(), u.mockLg).Return(nil)
+	u.attemptMock.EXPECT().Reset(context.Background(), domain.LoginScopeAccount, usr.UserID.String(), u.mockLg).Return(nil)
+	u.attemptMock.EXPECT().ForgiveAttempt(context.Background(), domain.LoginScopeIP, "10.0.0.4", u.mockLg).Return(nil)
+
+	_, err := userUsecase.Login(context.Background(), &req, u.mockLg)
+
+	t.Require().Nil(err)
+}
+
+func (u *UserUsecaseTest) TestConcurrentBadLogin(t provider.T) {
+	ctrl := gomock.NewController(t)
+	userRepoMock := mock_domain.NewMockUserRepo(ctrl)
+	attemptMock := mock_domain.NewMockLoginAttemptRepo(ctrl)
+	done := make(chan bool)
+	close(done)
+	loginThrottle := usecase.NewLoginThrottle(attemptMock, domain.LoginPolicy{
+		FreeAttempts:        3,
+		BaseDelay:           time.Second,
+		MaxDelay:            time.Minute,
+		AccountLockAttempts: 3,
+		IPLockAttempts:      100,
+		LockDuration:        15 * time.Minute,
+		Window:              time.Hour,
+	}, done, time.Hour, time.Second, u.mockLg)
+	userUsecase := usecase.NewUserUsecase(userRepoMock, u.tokenRepoMock, u.denyList, loginThrottle)
+	clientBuilder := NormalClientUserBuilder{}
+
+	clientBuilder.SetRole()
+	clientBuilder.SetMail()
+	clientBuilder.SetPassword()
+	clientBuilder.SetUid("019126ee-2b7d-758e-bb22-fe2e45b2db40")
+	usr := clientBuilder.GetUser()
+
+	// The counters are kept under a mutex, as Postgres keeps them under the row lock.
+	var mu sync.Mutex
+	counters := make(map[string]domain.LoginFailures)
+	attemptMock.EXPECT().RegisterAttempt(context.Background(), gomock.Any(), gomock.Any(), time.Hour, u.mockLg).
+		DoAndReturn(func(_ context.Context, scope string, subject string, _ time.Duration, _ *zap.Logger) (domain.LoginFailures, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			previous := counters[scope+subject]
+			counters[scope+subject] = domain.LoginFailures{Failures: previous.Failures + 1, LastFailedAt: time.Now()}
+			return previous, nil
+		}).AnyTimes()
+	userRepoMock.EXPECT().GetByID(context.Background(), usr.UserID, u.mockLg).Return(usr, nil).AnyTimes()
+
+	const guesses = 20
+	errs := make(chan error, guesses)
+	var wg sync.WaitGroup
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := domain.LoginUserRequest{ID: usr.UserID, Password: "badpassword", ClientIP: fmt.Sprintf("10.0.1.%d", i)}
+			_, err := userUsecase.Login(context.Background(), &req, u.mockLg)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	checked := 0
+	for err := range errs {
+		t.Require().Error(err)
+		if !errors.Is(err, domain.ErrUser_Locked) {
+			checked++
+		}
+	}
+	t.Require().Equal(3, checked)
+
+	req := domain.LoginUserRequest{ID: usr.UserID, Password: "password", ClientIP: "10.0.1.100"}
+	_, err := userUsecase.Login(context.Background(), &req, u.mockLg)
+	t.Require().ErrorIs(err, domain.ErrUser_Locked)
+}
+
+func (u *UserUsecaseTest) TestNormalUnlock(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, u.loginThrottle)
+
+	userID := uuid.MustParse("019126ee-2b7d-758e-bb22-fe2e45b2db40")
+	u.attemptMock.EXPECT().Reset(context.Background(), domain.LoginScopeAccount, userID.String(), u.mockLg).Return(nil)
+
+	err := userUsecase.Unlock(context.Background(), userID, u.mockLg)
+
+	t.Require().Nil(err)
+}
+
+func (u *UserUsecaseTest) TestBadIDUnlock(t provider.T) {
+	userUsecase := usecase.NewUserUsecase(u.userRepoMock, u.tokenRepoMock, u.denyList, u.loginThrottle)
+
+	err := userUsecase.Unlock(context.Background(), uuid.Nil, u.mockLg)
+
+	t.Require().ErrorIs(err, domain.ErrUser_BadId)
+}
+
 func TestUserUsecaseSuiteRunner(t *testing.T) {
 	suite.RunSuite(t, new(UserUsecaseTest))
 }
@@ -0,0 +1,54 @@
+//go:build unit
+// +build unit
+
+package tests
+
+import (
+	"archive/zip"
+	"avito-test-task/pkg"
+	"bytes"
+	"github.com/ozontech/allure-go/pkg/framework/provider"
+	"github.com/ozontech/allure-go/pkg/framework/suite"
+	"io"
+	"testing"
+)
+
+type XLSXWriterTest struct {
+	suite.Suite
+}
+
+func readZipEntry(t provider.T, archive *zip.Reader, name string) string {
+	entry, err := archive.Open(name)
+	t.Require().Nil(err)
+	defer entry.Close()
+
+	content, err := io.ReadAll(entry)
+	t.Require().Nil(err)
+	return string(content)
+}
+
+func (x *XLSXWriterTest) TestWriteRows(t provider.T) {
+	var buf bytes.Buffer
+	writer, err := pkg.NewXLSXWriter(&buf, "house 1")
+	t.Require().Nil(err)
+
+	t.Require().Nil(writer.WriteRow([]any{"Номер квартиры", "Цена"}))
+	t.Require().Nil(writer.WriteRow([]any{7, nil, "a < b & c"}))
+	t.Require().Nil(writer.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	t.Require().Nil(err)
+
+	t.Require().Contains(readZipEntry(t, archive, "xl/workbook.xml"), `<sheet name="house 1"`)
+	sheet := readZipEntry(t, archive, "xl/worksheets/sheet1.xml")
+	t.Require().Contains(sheet, `<row><c t="inlineStr"><is><t xml:space="preserve">Номер квартиры</t></is></c>`)
+	t.Require().Contains(sheet, `<row><c><v>7</v></c><c/><c t="inlineStr"><is><t xml:space="preserve">a &lt; b &amp; c</t></is></c></row>`)
+	t.Require().Contains(sheet, `</sheetData></worksheet>`)
+	readZipEntry(t, archive, "[Content_Types].xml")
+	readZipEntry(t, archive, "_rels/.rels")
+	readZipEntry(t, archive, "xl/_rels/workbook.xml.rels")
+}
+
+func TestXLSXWriterSuiteRunner(t *testing.T) {
+	suite.RunSuite(t, new(XLSXWriterTest))
+}